@@ -28,6 +28,27 @@ const (
 
 	// indicates that the clicker job should *not* cleanup the configmap it creates.
 	clickerSkipConfigMapCleanup = "skipConfigMapCleanup"
+
+	// indicates the path the launcher collect-diagnostics command writes its bundle to.
+	launcherDiagnosticsOutput = "output"
+
+	// indicates the launcher nodes command should output json rather than a table.
+	launcherNodesJSON = "json"
+
+	// indicates the launcher describe command should output unindented json.
+	launcherDescribeJSON = "json"
+
+	// indicates the go template the launcher describe command passes to docker inspect.
+	launcherDescribeFormat = "format"
+
+	// indicates the launcher copy command should write a gzipped tarball rather than plain files.
+	launcherCopyTarGz = "tar-gz"
+
+	// indicates the launcher tail-all command should not colorize node names even on a terminal.
+	launcherTailAllNoColor = "no-color"
+
+	// indicates the launcher drain command should not snapshot the node's logs before stopping it.
+	launcherDrainSkipLogs = "skip-logs"
 )
 
 // Entrypoint returns the clabernetes manager entrypoint, kicking off one of the clabernetes
@@ -68,6 +89,7 @@ func Entrypoint() *cli.App {
 
 					return nil
 				},
+				Subcommands: launcherSubcommands(),
 			},
 			{
 				Name:  "clicker",
@@ -118,3 +140,172 @@ func Entrypoint() *cli.App {
 		},
 	}
 }
+
+// launcherSubcommands returns the subcommands of the launch command, these are meant to be run
+// (via kubectl exec) inside of a running launcher pod.
+func launcherSubcommands() []*cli.Command {
+	return append(launcherNodeSubcommands(), launcherMaintenanceSubcommands()...)
+}
+
+// launcherNodeSubcommands returns the launcher subcommands that inspect the nodes.
+func launcherNodeSubcommands() []*cli.Command {
+	return []*cli.Command{
+		{
+			Name:      "tail",
+			Usage:     "follow the logs of the given node(s)",
+			ArgsUsage: "<node> [node...]",
+			Action: func(c *cli.Context) error {
+				if c.NArg() == 0 {
+					return cli.Exit("at least one node name is required", 1)
+				}
+
+				return claberneteslauncher.TailNodeLogs(c.Args().Slice())
+			},
+		},
+		{
+			Name:  "tail-all",
+			Usage: "follow the logs of every node, prefixed with its (colored) name",
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:     launcherTailAllNoColor,
+					Usage:    "do not colorize node names",
+					Required: false,
+					Value:    false,
+				},
+			},
+			Action: func(c *cli.Context) error {
+				return claberneteslauncher.TailAllNodeLogs(
+					c.Bool(launcherTailAllNoColor),
+				)
+			},
+		},
+		{
+			Name:  "nodes",
+			Usage: "print the container, status, and addresses of every node",
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:     launcherNodesJSON,
+					Usage:    "output json rather than a table",
+					Required: false,
+					Value:    false,
+				},
+			},
+			Action: func(c *cli.Context) error {
+				return claberneteslauncher.PrintNodeAddresses(c.Bool(launcherNodesJSON))
+			},
+		},
+		{
+			Name:      "describe",
+			Usage:     "print the full docker inspect output of the given node",
+			ArgsUsage: "<node>",
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:     launcherDescribeJSON,
+					Usage:    "output unindented json",
+					Required: false,
+					Value:    false,
+				},
+				&cli.StringFlag{
+					Name:     launcherDescribeFormat,
+					Usage:    "go template passed through to docker inspect",
+					Required: false,
+					Value:    "",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				if c.NArg() != 1 {
+					return cli.Exit("exactly one node name is required", 1)
+				}
+
+				return claberneteslauncher.DescribeNode(
+					c.Args().First(),
+					c.Bool(launcherDescribeJSON),
+					c.String(launcherDescribeFormat),
+				)
+			},
+		},
+		{
+			Name: "copy",
+			Usage: "copy a file or directory out of the given node, a source with a" +
+				" trailing slash copies the directory contents",
+			ArgsUsage: "<node> <source> <destination>",
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:     launcherCopyTarGz,
+					Usage:    "write a gzipped tarball to the destination",
+					Required: false,
+					Value:    false,
+				},
+			},
+			Action: func(c *cli.Context) error {
+				if c.NArg() != 3 { //nolint:mnd
+					return cli.Exit("node, source, and destination are required", 1)
+				}
+
+				return claberneteslauncher.CopyFromNode(
+					c.Args().Get(0),
+					c.Args().Get(1),
+					c.Args().Get(2),
+					c.Bool(launcherCopyTarGz),
+				)
+			},
+		},
+	}
+}
+
+// launcherMaintenanceSubcommands returns the launcher subcommands that act on the launcher or its
+// nodes for troubleshooting.
+func launcherMaintenanceSubcommands() []*cli.Command {
+	return []*cli.Command{
+		{
+			Name:  "collect-diagnostics",
+			Usage: "collect a diagnostics bundle of the launcher and its nodes",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name: launcherDiagnosticsOutput,
+					Usage: "path to write the diagnostics tarball to, defaults to" +
+						" diagnostics.tar.gz in the launcher work directory",
+					Required: false,
+					Value:    "",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				return claberneteslauncher.CollectDiagnostics(
+					c.String(launcherDiagnosticsOutput),
+				)
+			},
+		},
+		{
+			Name: "drain",
+			Usage: "snapshot the logs of and stop the given node with its configured" +
+				" stop signal and timeout, leaving all other nodes running",
+			ArgsUsage: "<node>",
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:     launcherDrainSkipLogs,
+					Usage:    "do not snapshot the node logs before stopping it",
+					Required: false,
+					Value:    false,
+				},
+			},
+			Action: func(c *cli.Context) error {
+				if c.NArg() != 1 {
+					return cli.Exit("exactly one node name is required", 1)
+				}
+
+				return claberneteslauncher.DrainNode(
+					c.Args().First(),
+					c.Bool(launcherDrainSkipLogs),
+				)
+			},
+		},
+		{
+			Name: "restart-docker",
+			Usage: "stop (if running) and start the launcher docker daemon, node" +
+				" containers survive only if docker live-restore is enabled",
+			Action: func(_ *cli.Context) error {
+				return claberneteslauncher.RestartDocker()
+			},
+		},
+	}
+}
@@ -53,6 +53,11 @@ const (
 	// value of the timeout flag of clabernetes when invoked on the launcher pod.
 	LauncherContainerlabTimeout = "LAUNCHER_CONTAINERLAB_TIMEOUT"
 
+	// LauncherNodeLaunchWorkers is the env var that holds the number of nodes containerlab creates
+	// and starts at once when deploying the topology, unset means all nodes at once. Images are
+	// pulled (one at a time) before nodes are created, so this does not add to image pulls.
+	LauncherNodeLaunchWorkers = "LAUNCHER_NODE_LAUNCH_WORKERS"
+
 	// LauncherContainerlabPersist is the environment variable name that can be used to enable the
 	// persistence of clabernetes when invoked on the launcher pod.
 	LauncherContainerlabPersist = "LAUNCHER_CONTAINERLAB_PERSIST"
@@ -65,10 +70,19 @@ const (
 	// privileged mode or our "not so privileged mode".
 	LauncherPrivilegedEnv = "LAUNCHER_PRIVILEGED"
 
+	// LauncherRootlessEnv is an envar that indicates if the launcher is running in rootless mode
+	// (as a non-root user), this is mutually exclusive with LauncherPrivilegedEnv.
+	LauncherRootlessEnv = "LAUNCHER_ROOTLESS"
+
 	// LauncherInsecureRegistries env var that tells the launcher pods which registries are
 	// insecure. Should be set by the controller via the topology spec.
 	LauncherInsecureRegistries = "LAUNCHER_INSECURE_REGISTRIES"
 
+	// LauncherInsecureRegistriesFile is the env var that holds the path to a (mounted) file of
+	// additional insecure registries, one per line, with "#" comments and blank lines ignored. The
+	// registries are merged with (and deduplicated against) LauncherInsecureRegistries.
+	LauncherInsecureRegistriesFile = "LAUNCHER_INSECURE_REGISTRIES_FILE"
+
 	// LauncherImagePullThroughModeEnv env var tells the manager how to configure the launcher,
 	// which in turn tells the launcher how it should attempt to pull images for the node it
 	// represents.
@@ -112,6 +126,392 @@ const (
 	// LauncherSSHProbePassword is the env var that holds the password to use in the ssh probe (if
 	// configured).
 	LauncherSSHProbePassword = "LAUNCHER_SSH_PROBE_PASSWORD" //nolint:gosec
+
+	// LauncherSkipDockerInfo is the env var that, when set to "true", disables logging the docker
+	// info output after docker has been started in the launcher.
+	LauncherSkipDockerInfo = "LAUNCHER_SKIP_DOCKER_INFO"
+
+	// LauncherDockerBIP is the env var that holds the (optional) bridge ip (in CIDR notation) the
+	// launcher should configure for the docker daemon's default bridge.
+	LauncherDockerBIP = "LAUNCHER_DOCKER_BIP"
+
+	// LauncherDockerFeatures is the env var that holds the (optional) comma separated list of
+	// docker daemon features to set, for example "containerd-snapshotter=true,buildkit=false".
+	LauncherDockerFeatures = "LAUNCHER_DOCKER_FEATURES"
+
+	// LauncherDockerCgroupParent is the env var that holds the (optional) parent cgroup the docker
+	// daemon should place containers under. Note that this generally requires the launcher to be
+	// running in privileged mode as otherwise the cgroup hierarchy is not writable.
+	LauncherDockerCgroupParent = "LAUNCHER_DOCKER_CGROUP_PARENT"
+
+	// LauncherDockerDisableBridge is the env var that, when set to "true", configures the docker
+	// daemon to start without its default bridge ("bridge": "none") -- containerlab creates its
+	// own management network so nodes do not need the default bridge.
+	LauncherDockerDisableBridge = "LAUNCHER_DOCKER_DISABLE_BRIDGE"
+
+	// LauncherDockerBridgeName is the env var that holds the (optional) name of the bridge the
+	// docker daemon attaches containers to instead of "docker0" -- useful to avoid bridge name
+	// collisions when docker daemons share a network namespace. Docker does not create a bridge
+	// with a custom name, so it must already exist. Mutually exclusive with LauncherDockerBIP and
+	// LauncherDockerDisableBridge.
+	LauncherDockerBridgeName = "LAUNCHER_DOCKER_BRIDGE_NAME"
+
+	// LauncherDockerDaemonConfigPath is the env var that holds the path of the docker daemon config
+	// the launcher renders, checks, and validates, defaulting to /etc/docker/daemon.json -- for
+	// rootless or otherwise nonstandard docker installs whose daemon reads its config elsewhere.
+	LauncherDockerDaemonConfigPath = "LAUNCHER_DOCKER_DAEMON_CONFIG_PATH"
+
+	// LauncherDaemonConfigMode is the env var that holds how the launcher handles the docker daemon
+	// config -- "write" (the default) writes the rendered config to the daemon config path,
+	// "assert" only verifies the (externally managed) file matches the rendered config and fails
+	// with a diff if it does not.
+	LauncherDaemonConfigMode = "LAUNCHER_DAEMON_CONFIG_MODE"
+
+	// LauncherDaemonConfigStrict is the env var that, when set to "true", makes the launcher
+	// validate the docker daemon config (rendered or user provided) against the docker daemon.json
+	// schema before starting docker, failing on any unknown or mistyped keys.
+	LauncherDaemonConfigStrict = "LAUNCHER_DAEMON_CONFIG_STRICT"
+
+	// LauncherSelfTest is the env var that, when set to "true", makes the launcher run a throwaway
+	// container once docker is up, failing startup if pulling, running, or removing it fails.
+	LauncherSelfTest = "LAUNCHER_SELFTEST"
+
+	// LauncherSelfTestImage is the env var that holds the image the self-test runs, defaulting to
+	// "hello-world" -- for air-gapped setups it should point at an image in a reachable registry.
+	LauncherSelfTestImage = "LAUNCHER_SELFTEST_IMAGE"
+
+	// LauncherDockerICC is the env var that holds the (optional) boolean to set as the docker
+	// daemon "icc" (inter-container communication on the default bridge) setting. If unset the key
+	// is omitted and docker's default (true) applies.
+	LauncherDockerICC = "LAUNCHER_DOCKER_ICC"
+
+	// LauncherDockerIPForward is the env var that holds the (optional) boolean to set as the docker
+	// daemon "ip-forward" setting, i.e. "false" when the host or cni handles ip forwarding. If
+	// unset the key is omitted and docker's default applies.
+	LauncherDockerIPForward = "LAUNCHER_DOCKER_IP_FORWARD"
+
+	// LauncherDockerIPMasq is the env var that holds the (optional) boolean to set as the docker
+	// daemon "ip-masq" setting, i.e. "false" when the host or cni handles masquerading. If unset
+	// the key is omitted and docker's default applies.
+	LauncherDockerIPMasq = "LAUNCHER_DOCKER_IP_MASQ"
+
+	// LauncherDockerShmSize is the env var that holds the (optional) default /dev/shm size (i.e.
+	// "256m") for containers, set as the docker daemon "default-shm-size" setting -- some node
+	// images need more than docker's 64m default. If unset the key is omitted.
+	LauncherDockerShmSize = "LAUNCHER_DOCKER_SHM_SIZE"
+
+	// LauncherDockerStorageOpts is the env var that holds a comma separated list of key=value
+	// storage opts (i.e. "overlay2.size=10G") for the docker daemon config; opts that do not apply
+	// to the selected storage driver are skipped.
+	LauncherDockerStorageOpts = "LAUNCHER_DOCKER_STORAGE_OPTS"
+
+	// LauncherDockerRuntimes is the env var that holds a comma separated list of name=path pairs
+	// of additional (oci) runtimes for the docker daemon config, i.e. "runsc=/usr/local/bin/runsc".
+	LauncherDockerRuntimes = "LAUNCHER_DOCKER_RUNTIMES"
+
+	// LauncherDockerDefaultRuntime is the env var that holds the name of the default runtime for
+	// the docker daemon config, this must be "runc" or one of the LauncherDockerRuntimes.
+	LauncherDockerDefaultRuntime = "LAUNCHER_DOCKER_DEFAULT_RUNTIME"
+
+	// LauncherDockerTLSCACert is the env var that holds the path to the (mounted) ca certificate
+	// used to verify clients of the docker daemon tls api. When this, LauncherDockerTLSCert, and
+	// LauncherDockerTLSKey are all set, the docker daemon will listen for tls (verified)
+	// connections on port 2376 in addition to its local socket.
+	LauncherDockerTLSCACert = "LAUNCHER_DOCKER_TLS_CA_CERT"
+
+	// LauncherDockerTLSCert is the env var that holds the path to the (mounted) server certificate
+	// for the docker daemon tls api.
+	LauncherDockerTLSCert = "LAUNCHER_DOCKER_TLS_CERT"
+
+	// LauncherDockerTLSKey is the env var that holds the path to the (mounted) server key for the
+	// docker daemon tls api.
+	LauncherDockerTLSKey = "LAUNCHER_DOCKER_TLS_KEY"
+
+	// LauncherLogUploadEnabled is the env var that, when set to "true", enables uploading the node
+	// log files to an s3 compatible bucket when the launcher shuts down.
+	LauncherLogUploadEnabled = "LAUNCHER_LOG_UPLOAD_ENABLED"
+
+	// LauncherLogUploadEndpoint is the env var that holds the endpoint (url) of the s3 compatible
+	// service to upload node logs to, i.e. "https://s3.us-east-1.amazonaws.com".
+	LauncherLogUploadEndpoint = "LAUNCHER_LOG_UPLOAD_ENDPOINT"
+
+	// LauncherLogUploadBucket is the env var that holds the bucket name to upload node logs to.
+	LauncherLogUploadBucket = "LAUNCHER_LOG_UPLOAD_BUCKET"
+
+	// LauncherLogUploadPrefix is the env var that holds the (optional) key prefix for uploaded node
+	// logs.
+	LauncherLogUploadPrefix = "LAUNCHER_LOG_UPLOAD_PREFIX"
+
+	// LauncherLogUploadRegion is the env var that holds the region used when signing log upload
+	// requests, defaults to "us-east-1".
+	LauncherLogUploadRegion = "LAUNCHER_LOG_UPLOAD_REGION"
+
+	// LauncherLogUploadCredentialsPath is the env var that holds the path to the directory the log
+	// upload credentials secret is mounted at -- the directory must contain "access-key-id" and
+	// "secret-access-key" files.
+	LauncherLogUploadCredentialsPath = "LAUNCHER_LOG_UPLOAD_CREDENTIALS_PATH" //nolint:gosec
+
+	// LauncherHTTPPort is the env var that holds the port the launcher http server (serving things
+	// like recent node logs) listens on; if unset or zero the http server is not started.
+	LauncherHTTPPort = "LAUNCHER_HTTP_PORT"
+
+	// LauncherNodeLogBufferLines is the env var that holds the number of recent log lines to keep
+	// in memory for each node container.
+	LauncherNodeLogBufferLines = "LAUNCHER_NODE_LOG_BUFFER_LINES"
+
+	// LauncherWorkDir is the env var that holds the directory the launcher writes all of its
+	// artifacts (node logs, containerlab log, image tarballs) to, defaults to the current working
+	// directory.
+	LauncherWorkDir = "LAUNCHER_WORK_DIR"
+
+	// LauncherNodeLogPrefixFormat is the env var that holds the (optional) prefix format applied to
+	// each line written to the combined node log. The placeholders "{node}", "{id}", and "{ts}" are
+	// replaced with the node (container) name, the container id, and the RFC3339 time the line was
+	// received respectively. Defaults to no prefix.
+	LauncherNodeLogPrefixFormat = "LAUNCHER_NODE_LOG_PREFIX_FORMAT"
+
+	// LauncherNodeLogDestinations is the env var that holds a comma separated list of destinations
+	// the combined node logs are written to -- "file" (node.log in the work directory), "stdout"
+	// (the node logger), and/or "syslog://host:port" (udp). Defaults to "file,stdout".
+	LauncherNodeLogDestinations = "LAUNCHER_NODE_LOG_DESTINATIONS"
+
+	// LauncherNodeLogJSONFile is the env var that, when set to "true", makes the launcher read the
+	// node containers' json-file logs directly (rather than via docker logs) and tag each line with
+	// its stream (stdout/stderr). Containers not using the json-file log driver use docker logs.
+	LauncherNodeLogJSONFile = "LAUNCHER_NODE_LOG_JSON_FILE"
+
+	// LauncherNodeLogRateLimit is the env var that holds the max number of log lines per second the
+	// launcher writes for each node container, excess lines are dropped (and the number dropped is
+	// logged) to protect the launcher from a runaway node. Unset or zero means no limit.
+	LauncherNodeLogRateLimit = "LAUNCHER_NODE_LOG_RATE_LIMIT"
+
+	// LauncherNodeLogReceiveTimestamps is the env var that, when set to "true", prepends each node
+	// log line with the RFC3339Nano time the launcher received it -- unlike the container side
+	// docker timestamps this is unaffected by node clock drift.
+	LauncherNodeLogReceiveTimestamps = "LAUNCHER_NODE_LOG_RECEIVE_TIMESTAMPS"
+
+	// LauncherHeartbeatInterval is the env var that holds the interval (as a go duration string,
+	// i.e. "5m") at which the launcher logs a summary of the running containers. If unset or zero
+	// no heartbeat is logged.
+	LauncherHeartbeatInterval = "LAUNCHER_HEARTBEAT_INTERVAL"
+
+	// LauncherDockerWatchdogInterval is the env var that holds the interval (as a go duration
+	// string) at which the launcher pings the docker daemon after startup. Defaults to 15s, zero
+	// disables the watchdog.
+	LauncherDockerWatchdogInterval = "LAUNCHER_DOCKER_WATCHDOG_INTERVAL"
+
+	// LauncherDockerWatchdogFailureThreshold is the env var that holds the number of consecutive
+	// failed docker pings after which the watchdog acts. Defaults to three.
+	LauncherDockerWatchdogFailureThreshold = "LAUNCHER_DOCKER_WATCHDOG_FAILURE_THRESHOLD"
+
+	// LauncherDockerWatchdogPolicy is the env var that holds what the watchdog does when docker is
+	// unresponsive -- "log" (the default) only reports it, "exit" crashes the launcher so the pod
+	// is restarted, "restart" attempts to restart docker (and exits if that fails).
+	LauncherDockerWatchdogPolicy = "LAUNCHER_DOCKER_WATCHDOG_POLICY"
+
+	// LauncherExpectedNodeCount is the env var that holds the number of node containers that must
+	// be running for the launcher to consider startup successful and report ready. Defaults to the
+	// number of expected nodes (at least one).
+	LauncherExpectedNodeCount = "LAUNCHER_EXPECTED_NODE_COUNT"
+
+	// LauncherExpectedNodes is the env var that holds a comma separated list of (containerlab)
+	// node names, in addition to the launcher's own node, that must be ready before the launcher
+	// considers startup successful.
+	LauncherExpectedNodes = "LAUNCHER_EXPECTED_NODES"
+
+	// LauncherNodeStateEvents is the env var that, when set to "true", makes the launcher track
+	// the node container states from the docker event stream rather than polling docker for them
+	// (for readiness and the container watchdog), falling back to polling whenever the event
+	// stream drops.
+	LauncherNodeStateEvents = "LAUNCHER_NODE_STATE_EVENTS"
+
+	// LauncherWaitPollInterval is the env var that holds the interval (as a go duration string) at
+	// which the launcher's wait helpers (waiting for node containers to exist, run, become
+	// healthy, etc.) poll docker. Defaults to 1s, each poll adds a small random jitter.
+	LauncherWaitPollInterval = "LAUNCHER_WAIT_POLL_INTERVAL"
+
+	// LauncherContainerListTimeout is the env var that holds the max duration (as a go duration
+	// string) the launcher will retry listing containers after launch until at least
+	// LauncherContainerListMinCount containers are present. Defaults to zero -- no retries.
+	LauncherContainerListTimeout = "LAUNCHER_CONTAINER_LIST_TIMEOUT"
+
+	// LauncherContainerListMinCount is the env var that holds the minimum number of containers the
+	// launcher expects to list after launch, see LauncherContainerListTimeout. Defaults to one.
+	LauncherContainerListMinCount = "LAUNCHER_CONTAINER_LIST_MIN_COUNT"
+
+	// LauncherSkipIPTablesLegacy is the env var that, when set to "true", makes the launcher skip
+	// the fallback to legacy ip tables if docker fails to start -- for environments where nft is
+	// required and the legacy switch breaks node connectivity.
+	LauncherSkipIPTablesLegacy = "LAUNCHER_SKIP_IPTABLES_LEGACY"
+
+	// LauncherIPTablesBackend is the env var that holds the ip tables backend ("legacy", "nft", or
+	// "auto") the launcher selects (for both iptables and ip6tables) before starting docker. The
+	// default, "auto", keeps the image's default backend and only falls back to legacy if docker
+	// fails to start (unless LauncherSkipIPTablesLegacy is set), an explicitly selected backend is
+	// never changed.
+	LauncherIPTablesBackend = "LAUNCHER_IPTABLES_BACKEND"
+
+	// LauncherPreDockerHook is the env var that holds the path to an (executable) script the
+	// launcher runs before starting docker, a failing pre hook is fatal.
+	LauncherPreDockerHook = "LAUNCHER_PRE_DOCKER_HOOK"
+
+	// LauncherPostDockerHook is the env var that holds the path to an (executable) script the
+	// launcher runs once docker is running, a failing post hook is only logged by default.
+	LauncherPostDockerHook = "LAUNCHER_POST_DOCKER_HOOK"
+
+	// LauncherPostDockerHookFatal is the env var that, when set to "true", makes a failing post
+	// docker hook fatal.
+	LauncherPostDockerHookFatal = "LAUNCHER_POST_DOCKER_HOOK_FATAL"
+
+	// LauncherPostConvergenceHook is the env var that holds the path to an (executable) script the
+	// launcher runs once all nodes reported ready. The script receives the node addresses as comma
+	// separated "node=address" pairs in the CLABERNETES_NODE_ADDRESSES env var.
+	LauncherPostConvergenceHook = "LAUNCHER_POST_CONVERGENCE_HOOK"
+
+	// LauncherPostConvergenceHookPolicy is the env var that holds how a failing (or, if not all
+	// nodes became ready, skipped) post convergence hook is handled -- "warn" (the default) logs a
+	// warning, "fatal" exits the launcher, and "ignore" only logs at debug level.
+	LauncherPostConvergenceHookPolicy = "LAUNCHER_POST_CONVERGENCE_HOOK_POLICY"
+
+	// LauncherContainerRestartPolicy is the env var that holds the docker restart policy ("no",
+	// "on-failure[:max-retries]", "always", or "unless-stopped") applied to the node containers
+	// once launched. Restarts are handled by the docker daemon in the launcher pod, so a node that
+	// stays down (i.e. exhausts its on-failure retries) still causes the launcher to exit, and
+	// nothing is restarted once the launcher itself exits.
+	LauncherContainerRestartPolicy = "LAUNCHER_CONTAINER_RESTART_POLICY"
+
+	// LauncherImagePullMirror is the env var that holds the host[:port] of a pull-through cache
+	// mirror the launcher pulls the node image from before launching -- falling back to the
+	// canonical image reference if the mirror pull fails.
+	LauncherImagePullMirror = "LAUNCHER_IMAGE_PULL_MIRROR"
+
+	// LauncherRegistryConfigStrict is the env var that, when set to "true", makes inconsistencies
+	// between LauncherInsecureRegistries and LauncherImagePullMirror (i.e. a plain http mirror
+	// that is not an insecure registry) fatal rather than only logged as warnings.
+	LauncherRegistryConfigStrict = "LAUNCHER_REGISTRY_CONFIG_STRICT"
+
+	// LauncherImagePullPolicy is the env var that holds the kubernetes style pull policy ("Always",
+	// "IfNotPresent", or "Never") the launcher applies to the node image before launching. If unset
+	// the launcher only pulls the image itself when LauncherImagePullMirror is set.
+	LauncherImagePullPolicy = "LAUNCHER_IMAGE_PULL_POLICY"
+
+	// LauncherImagePullTimeout is the env var that holds the max duration (as a go duration string)
+	// a single image pull the launcher runs may take before it is killed and retried. Unset/zero
+	// means no timeout.
+	LauncherImagePullTimeout = "LAUNCHER_IMAGE_PULL_TIMEOUT"
+
+	// LauncherImageBuildContext is the env var that holds the path of a (mounted) docker build
+	// context directory the launcher builds the node image from before launching. If unset no image
+	// is built.
+	LauncherImageBuildContext = "LAUNCHER_IMAGE_BUILD_CONTEXT"
+
+	// LauncherImageBuildDockerfile is the env var that holds the path of the dockerfile to build,
+	// relative to LauncherImageBuildContext. Defaults to the "Dockerfile" of the build context.
+	LauncherImageBuildDockerfile = "LAUNCHER_IMAGE_BUILD_DOCKERFILE"
+
+	// LauncherImageBuildTag is the env var that holds the tag of the image built from
+	// LauncherImageBuildContext. Defaults to the node image so containerlab uses the built image.
+	LauncherImageBuildTag = "LAUNCHER_IMAGE_BUILD_TAG"
+
+	// LauncherImageBuildArgs is the env var that holds a yaml/json mapping of build arg name to
+	// value passed to the image build.
+	LauncherImageBuildArgs = "LAUNCHER_IMAGE_BUILD_ARGS"
+
+	// LauncherImageBuildTimeout is the env var that holds the max duration (as a go duration
+	// string) the image build may take before it is killed. Defaults to thirty minutes.
+	LauncherImageBuildTimeout = "LAUNCHER_IMAGE_BUILD_TIMEOUT"
+
+	// LauncherStartupDeadline is the env var that holds the max duration (as a go duration string)
+	// the whole launcher startup sequence may take; if exceeded the launcher logs a phase by phase
+	// timing summary and exits. Unset/zero means no deadline.
+	LauncherStartupDeadline = "LAUNCHER_STARTUP_DEADLINE"
+
+	// LauncherNodeEnvFile is the env var that holds the path to a (mounted) yaml/json file mapping
+	// node name to env var name to value, that env is injected into the node containers.
+	LauncherNodeEnvFile = "LAUNCHER_NODE_ENV_FILE"
+
+	// LauncherNodeEnv is the env var that holds the same per node env mapping as the file
+	// referenced by LauncherNodeEnvFile, inline; ignored if LauncherNodeEnvFile is set.
+	LauncherNodeEnv = "LAUNCHER_NODE_ENV"
+
+	// LauncherNodeResources is the env var that holds a yaml/json mapping of node name to the
+	// "memory" (docker size string), "cpus", and "pids-limit" limits of that node's container.
+	LauncherNodeResources = "LAUNCHER_NODE_RESOURCES"
+
+	// LauncherNodeSysctls is the env var that holds a yaml/json mapping of node name to a mapping
+	// of sysctl name to value to set on that node's container, i.e. "net.ipv4.ip_forward: 1". Only
+	// sysctls docker permits for the node's network mode may be set.
+	LauncherNodeSysctls = "LAUNCHER_NODE_SYSCTLS"
+
+	// LauncherNodeDNSSearch is the env var that holds a comma separated list of dns search domains
+	// to set on all node containers, i.e. so nodes can resolve their peers by short name.
+	LauncherNodeDNSSearch = "LAUNCHER_NODE_DNS_SEARCH"
+
+	// LauncherNodeDNSOptions is the env var that holds a comma separated list of resolver options
+	// (as in resolv.conf, i.e. "ndots:2,rotate") to set on all node containers.
+	LauncherNodeDNSOptions = "LAUNCHER_NODE_DNS_OPTIONS"
+
+	// LauncherNodeStopConfig is the env var that holds a yaml/json mapping of node name to the
+	// stop "signal" and "timeout" (go duration string) to stop that node's container with when the
+	// launcher shuts down. Nodes not in the mapping are not stopped by the launcher.
+	LauncherNodeStopConfig = "LAUNCHER_NODE_STOP_CONFIG"
+
+	// LauncherContainerStopTimeout is the env var that holds the default stop timeout (go duration
+	// string) for node containers. If set, the launcher stops all node containers when shutting
+	// down, using this timeout for nodes without a timeout in LauncherNodeStopConfig.
+	LauncherContainerStopTimeout = "LAUNCHER_CONTAINER_STOP_TIMEOUT"
+
+	// LauncherExistingNodePolicy is the env var that holds what the launcher does about node
+	// containers left over from a previous run -- "reuse" them, "recreate" them if the topology
+	// changed since they were created (reusing them otherwise), or "error". If unset the launcher
+	// does not check for existing containers and containerlab handles them as usual.
+	LauncherExistingNodePolicy = "LAUNCHER_EXISTING_NODE_POLICY"
+
+	// LauncherOverlayNetworkConfig is the env var that holds the (optional) yaml/json config of a
+	// multi-host network the launcher creates (or joins if it exists) before launching containerlab
+	// -- its "name", "driver" (defaults to "overlay", which requires the docker daemon to be part
+	// of a swarm), "subnet", "attachable", "driverOpts", and whether to "connectNodes" to it.
+	LauncherOverlayNetworkConfig = "LAUNCHER_OVERLAY_NETWORK_CONFIG"
+
+	// LauncherNodeLabels is the env var that holds the (optional) comma separated key=value labels
+	// the launcher sets on all node containers in addition to its standard labels (see
+	// LabelLauncherInstance). The standard labels cannot be overridden.
+	LauncherNodeLabels = "LAUNCHER_NODE_LABELS"
+
+	// LauncherNodeRemoveGrace is the env var that holds the (optional) grace period (go duration
+	// string) existing node containers are stopped with before being removed when the existing
+	// node policy recreates them. Unset or zero means they are force removed right away.
+	LauncherNodeRemoveGrace = "LAUNCHER_NODE_REMOVE_GRACE"
+
+	// LauncherNodeRemoveSnapshotLogs is the env var that, when set to "true", writes the logs of
+	// existing node containers to the node log before they are removed so the final logs of the
+	// previous run are not lost.
+	LauncherNodeRemoveSnapshotLogs = "LAUNCHER_NODE_REMOVE_SNAPSHOT_LOGS"
+
+	// LauncherNodeReadyLogPattern is the env var that holds an (optional) regular expression the
+	// launcher waits for the node container to log a matching line for before considering the
+	// node launched, i.e. a NOS specific "system ready" message.
+	LauncherNodeReadyLogPattern = "LAUNCHER_NODE_READY_LOG_PATTERN"
+
+	// LauncherNodeReadyLogTimeout is the env var that holds the max duration (as a go duration
+	// string) to wait for a line matching LauncherNodeReadyLogPattern. Defaults to five minutes.
+	LauncherNodeReadyLogTimeout = "LAUNCHER_NODE_READY_LOG_TIMEOUT"
+
+	// LauncherNodeReachability is the env var that holds how the launcher confirms the node is
+	// reachable on its container address before considering it launched -- a tcp port number to
+	// dial, or "icmp" to ping the node (privileged launchers only). Unset means no check.
+	LauncherNodeReachability = "LAUNCHER_NODE_REACHABILITY"
+
+	// LauncherPartialFailurePolicy is the env var that holds what the launcher does when not all
+	// nodes become ready -- "fail-fast" gives up (and exits) as soon as any node is known to have
+	// failed, "best-effort" (the default) waits out the node ready timeout, reports which nodes did
+	// not start, and carries on with the others.
+	LauncherPartialFailurePolicy = "LAUNCHER_PARTIAL_FAILURE_POLICY"
+
+	// LauncherHoldOnFailure is the env var that, when set to "true", keeps the launcher running on
+	// a node failure (that would otherwise exit or shut it down) so the broken state can be
+	// inspected -- /readyz reports the launcher degraded until it is shut down by a signal.
+	LauncherHoldOnFailure = "LAUNCHER_HOLD_ON_FAILURE"
 )
 
 const (
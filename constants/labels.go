@@ -70,3 +70,38 @@ const (
 	// puller pod.
 	LabelPullerNodeTarget = "clabernetes/pullerNodeTarget"
 )
+
+const (
+	// LabelLauncherNodeConfigHash is a label the launcher sets on node containers (via the
+	// containerlab topology) that holds the hash of the topology the container was created from,
+	// so that a re-run launcher can tell if an existing node container is still up to date.
+	LabelLauncherNodeConfigHash = "clabernetes/launcherNodeConfigHash"
+)
+
+const (
+	// LabelLauncherInstance is a label the launcher sets on node containers (via the containerlab
+	// topology) that holds the name of the launcher pod that created the container. Along with
+	// LabelApp (the app name), LabelTopologyOwner (the topology name), and LabelTopologyNode (the
+	// containerlab node name), which the launcher sets on node containers as well, this lets
+	// external tooling reliably find launcher managed containers with "docker ps --filter label=".
+	LabelLauncherInstance = "clabernetes/launcherInstance"
+)
+
+const (
+	// LabelNodeParent is a label set on the sidecar containers of a node (i.e. a helper running
+	// next to the NOS container) that holds the containerlab name of the node the sidecar belongs
+	// to, so the launcher can group the sidecar's logs with those of its node.
+	LabelNodeParent = "clabernetes/nodeParent"
+
+	// LabelNodeRole is a label set on the sidecar containers of a node that holds the role of the
+	// sidecar (i.e. "helper"), used to label the sidecar's log stream. Defaults to "sidecar".
+	LabelNodeRole = "clabernetes/nodeRole"
+)
+
+const (
+	// LabelLauncherHelper is a label the launcher sets on the short-lived helper containers it runs
+	// itself (i.e. the docker self-test container), as opposed to node containers, so that helpers
+	// left behind (say because the launcher stopped while a helper was running) can be found and
+	// removed.
+	LabelLauncherHelper = "clabernetes/launcherHelper"
+)
@@ -1,6 +1,9 @@
 package errors
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // ErrConnectivity is the error returned when encountering issues with clabernetes connectivity.
 var ErrConnectivity = errors.New("errConnectivity")
@@ -8,3 +11,27 @@ var ErrConnectivity = errors.New("errConnectivity")
 // ErrLaunch is the error returned when encountering issues with launching things in a
 // clabernetes pod.
 var ErrLaunch = errors.New("errLaunch")
+
+// ErrDockerUnavailable is the error returned when the docker daemon in a clabernetes pod cannot be
+// reached (i.e. it is not running, or the docker cli is not present).
+var ErrDockerUnavailable = fmt.Errorf("%w: errDockerUnavailable", ErrLaunch)
+
+// ErrContainerNotFound is the error returned when a container (or other docker object) that the
+// launcher expected to exist does not exist (anymore).
+var ErrContainerNotFound = fmt.Errorf("%w: errContainerNotFound", ErrLaunch)
+
+// ErrDockerInvalidArgs is the error returned when docker rejects the arguments the launcher
+// invoked it with.
+var ErrDockerInvalidArgs = fmt.Errorf("%w: errDockerInvalidArgs", ErrLaunch)
+
+// ErrNetworkNotFound is the error returned when a docker network that the launcher expected to
+// exist does not exist.
+var ErrNetworkNotFound = fmt.Errorf("%w: errNetworkNotFound", ErrLaunch)
+
+// ErrImagePullTimeout is the error returned when pulling an image did not complete within the
+// configured image pull timeout.
+var ErrImagePullTimeout = fmt.Errorf("%w: errImagePullTimeout", ErrLaunch)
+
+// ErrContainerUnreachable is the error returned when a container's address did not become
+// reachable within the given timeout.
+var ErrContainerUnreachable = fmt.Errorf("%w: errContainerUnreachable", ErrLaunch)
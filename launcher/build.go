@@ -0,0 +1,157 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"time"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+	claberneteslogging "github.com/srl-labs/clabernetes/logging"
+	clabernetesutil "github.com/srl-labs/clabernetes/util"
+	"gopkg.in/yaml.v3"
+)
+
+const defaultImageBuildTimeout = 30 * time.Minute
+
+// loadImageBuildArgs loads the image build args (a map of build arg name to value) from
+// LauncherImageBuildArgs in yaml (or json) form.
+func loadImageBuildArgs() (map[string]string, error) {
+	buildArgs := map[string]string{}
+
+	err := yaml.Unmarshal(
+		[]byte(os.Getenv(clabernetesconstants.LauncherImageBuildArgs)),
+		&buildArgs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"%w: invalid image build args, must be a mapping of build arg name to value, err: %w",
+			claberneteserrors.ErrLaunch,
+			err,
+		)
+	}
+
+	for name := range buildArgs {
+		if !envNamePattern.MatchString(name) {
+			return nil, fmt.Errorf(
+				"%w: invalid image build arg name %q",
+				claberneteserrors.ErrLaunch,
+				name,
+			)
+		}
+	}
+
+	return buildArgs, nil
+}
+
+// buildImage builds the image from the given build context directory and dockerfile (relative to
+// the context directory, defaulting to the context's "Dockerfile"), tagging it with tag and
+// returning that tag. The build args from LauncherImageBuildArgs are passed to the build, the build
+// output is streamed to the logger, and the build is killed if it runs longer than
+// LauncherImageBuildTimeout.
+func buildImage(
+	ctx context.Context,
+	logger claberneteslogging.Instance,
+	contextDir, dockerfile, tag string,
+) (string, error) {
+	if contextDir == "" || tag == "" {
+		return "", fmt.Errorf(
+			"%w: both a build context and an image tag are required to build an image",
+			claberneteserrors.ErrLaunch,
+		)
+	}
+
+	buildArgs, err := loadImageBuildArgs()
+	if err != nil {
+		return "", err
+	}
+
+	timeout := clabernetesutil.GetEnvDurationOrDefault(
+		clabernetesconstants.LauncherImageBuildTimeout,
+		defaultImageBuildTimeout,
+	)
+
+	args := []string{"build", "--tag", tag}
+
+	if dockerfile != "" {
+		if !filepath.IsAbs(dockerfile) {
+			dockerfile = filepath.Join(contextDir, dockerfile)
+		}
+
+		args = append(args, "--file", dockerfile)
+	}
+
+	buildArgNames := make([]string, 0, len(buildArgs))
+
+	for name := range buildArgs {
+		buildArgNames = append(buildArgNames, name)
+	}
+
+	slices.Sort(buildArgNames)
+
+	for _, name := range buildArgNames {
+		args = append(args, "--build-arg", name+"="+buildArgs[name])
+	}
+
+	args = append(args, contextDir)
+
+	buildCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	buildCmd := exec.CommandContext(buildCtx, "docker", args...)
+
+	buildCmd.Stdout = logger
+	buildCmd.Stderr = logger
+
+	err = runner.Run(buildCmd)
+
+	switch {
+	case err == nil:
+		return tag, nil
+	case ctx.Err() == nil && errors.Is(buildCtx.Err(), context.DeadlineExceeded):
+		return "", fmt.Errorf(
+			"%w: building image %q did not complete within %s",
+			claberneteserrors.ErrLaunch,
+			tag,
+			timeout,
+		)
+	default:
+		return "", classifyDockerError(err)
+	}
+}
+
+// imageBuild builds the node image from the configured build context (if any) before launching,
+// tagging it as the node image (unless another tag is configured) so that containerlab uses it.
+func (c *clabernetes) imageBuild() {
+	contextDir := os.Getenv(clabernetesconstants.LauncherImageBuildContext)
+	if contextDir == "" {
+		return
+	}
+
+	tag := clabernetesutil.GetEnvStrOrDefault(
+		clabernetesconstants.LauncherImageBuildTag,
+		c.imageName,
+	)
+
+	c.logger.Infof("building image %q from %q...", tag, contextDir)
+
+	builtImage, err := buildImage(
+		c.ctx,
+		c.logger,
+		contextDir,
+		os.Getenv(clabernetesconstants.LauncherImageBuildDockerfile),
+		tag,
+	)
+	if err != nil {
+		c.logger.Fatalf("failed building image %q, err: %s", tag, err)
+	}
+
+	c.builtImage = builtImage
+
+	c.logger.Infof("built image %q", builtImage)
+}
@@ -0,0 +1,104 @@
+package launcher_test
+
+import (
+	"context"
+	"testing"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+func TestBuildImage(t *testing.T) {
+	cases := []struct {
+		name          string
+		contextDir    string
+		dockerfile    string
+		buildArgs     string
+		buildErr      error
+		expectedCalls map[string]int
+		expectErr     bool
+	}{
+		{
+			name:       "simple",
+			contextDir: "/build",
+			expectedCalls: map[string]int{
+				"docker build --tag srl:custom /build": 1,
+			},
+		},
+		{
+			name:       "dockerfile-and-build-args",
+			contextDir: "/build",
+			dockerfile: "images/Dockerfile.srl",
+			buildArgs:  `{"VERSION": "24.10", "BASE": "ghcr.io/nokia/srlinux"}`,
+			expectedCalls: map[string]int{
+				"docker build --tag srl:custom --file /build/images/Dockerfile.srl" +
+					" --build-arg BASE=ghcr.io/nokia/srlinux --build-arg VERSION=24.10 /build": 1,
+			},
+		},
+		{
+			name:       "absolute-dockerfile",
+			contextDir: "/build",
+			dockerfile: "/elsewhere/Dockerfile",
+			expectedCalls: map[string]int{
+				"docker build --tag srl:custom --file /elsewhere/Dockerfile /build": 1,
+			},
+		},
+		{
+			name:          "invalid-build-arg",
+			contextDir:    "/build",
+			buildArgs:     `{"NOT-VALID": "x"}`,
+			expectedCalls: map[string]int{},
+			expectErr:     true,
+		},
+		{
+			name:          "missing-context",
+			expectedCalls: map[string]int{},
+			expectErr:     true,
+		},
+		{
+			name:       "build-failure",
+			contextDir: "/build",
+			buildErr:   errFakeCommand,
+			expectedCalls: map[string]int{
+				"docker build --tag srl:custom /build": 1,
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				t.Setenv(clabernetesconstants.LauncherImageBuildArgs, testCase.buildArgs)
+
+				fakeRunner := newFakeCommandRunner()
+
+				for key := range testCase.expectedCalls {
+					fakeRunner.results[key] = testCase.buildErr
+				}
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				tag, err := claberneteslauncher.BuildImage(
+					context.Background(),
+					testCase.contextDir,
+					testCase.dockerfile,
+					"srl:custom",
+				)
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+
+				if !testCase.expectErr && tag != "srl:custom" {
+					clabernetestesthelper.FailOutput(t, tag, "srl:custom")
+				}
+
+				clabernetestesthelper.MarshaledEqual(t, fakeRunner.calls, testCase.expectedCalls)
+			})
+	}
+}
@@ -6,10 +6,16 @@ import (
 	"math/rand"
 	"net"
 	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
 	clabernetesgeneratedclientset "github.com/srl-labs/clabernetes/generated/clientset"
 	claberneteslogging "github.com/srl-labs/clabernetes/logging"
 	clabernetesutil "github.com/srl-labs/clabernetes/util"
@@ -23,6 +29,8 @@ const (
 	statusProbeCheckTimeout  = 5 * time.Second
 	clientDefaultTimeout     = time.Minute
 	defaultSSHPort           = 22
+	defaultWorkDir           = "."
+	nodeReadyTimeout         = 5 * time.Minute
 )
 
 // StartClabernetes is a function that starts the clabernetes launcher. It cannot fail, only panic.
@@ -71,6 +79,19 @@ func StartClabernetes() {
 		nodeLogger:           nodeLogger,
 		imageName:            os.Getenv(clabernetesconstants.LauncherNodeImageEnv),
 		imagePullThroughMode: os.Getenv(clabernetesconstants.LauncherImagePullThroughModeEnv),
+		workDir:              launcherWorkDir(),
+		nodeLogBuffers: newLogRingBuffers(
+			clabernetesutil.GetEnvIntOrDefault(
+				clabernetesconstants.LauncherNodeLogBufferLines,
+				defaultNodeLogBufferLines,
+			),
+		),
+		containerLogTails:   newContainerLogTails(),
+		nodeLogPrefixFormat: os.Getenv(clabernetesconstants.LauncherNodeLogPrefixFormat),
+		startupTimings:      newStartupTimings(),
+		oomKills:            newOOMKills(),
+		nodeExitCodes:       newNodeExitCodes(),
+		nodeStates:          newNodeStates(),
 	}
 
 	clabernetesInstance.startup()
@@ -93,6 +114,11 @@ type clabernetes struct {
 
 	imageName            string
 	imagePullThroughMode string
+	// builtImage is the tag of the image the launcher built from the image build context, if any
+	builtImage string
+
+	// workDir is the directory all launcher artifacts (logs, image tarballs, etc.) are written to
+	workDir string
 
 	// containerIDs holds *all* ids of containers running --in theory we could have other side-car
 	// type stuff running so just catching all them here so we know if/when things fail
@@ -100,6 +126,35 @@ type clabernetes struct {
 	// meanwhile nodeContainerID is the container id of hte specific node this launcher represents
 	// -- meaning the single node from the original topology this launcher is representing
 	nodeContainerID string
+	// nodeContainers is the index of node name to container id for all containerlab managed
+	// containers
+	nodeContainers *nodeContainerIndex
+	// containerLogFiles is a mapping of container id to the path of the log file that container's
+	// logs are being written to
+	containerLogFiles map[string]string
+	// nodeLogBuffers holds the most recent log lines of each container, keyed by container name
+	nodeLogBuffers *logRingBuffers
+	// containerLogTails holds the cancel funcs of the running container log tails
+	containerLogTails *containerLogTails
+	// nodeLogPrefixFormat is the prefix format applied to each line of the combined node log
+	nodeLogPrefixFormat string
+	// logTailState tracks how far each container's logs have been tailed across restarts
+	logTailState *logTailState
+	// startupTimings records how long each startup phase took
+	startupTimings *startupTimings
+	// startupComplete is set once all startup phases have completed
+	startupComplete atomic.Bool
+	// nodeStates holds the node container states as reported by docker events, if enabled
+	nodeStates *nodeStates
+	// oomKills tracks the node containers that were OOMKilled
+	oomKills *oomKills
+	// nodeExitCodes tracks the exit codes of the node containers that exited
+	nodeExitCodes *nodeExitCodes
+	// degradedReason is the node failure the launcher is held on, if LauncherHoldOnFailure is set
+	degradedReason atomic.Pointer[string]
+	// missingDockerSubcommands holds the docker cli subcommands of optional features that the
+	// docker cli preflight found to be missing
+	missingDockerSubcommands map[string]bool
 }
 
 func (c *clabernetes) startup() {
@@ -107,23 +162,357 @@ func (c *clabernetes) startup() {
 
 	c.logger.Debugf("clabernetes version %s", clabernetesconstants.Version)
 
-	c.containerlabVersion()
-	c.setup()
-	c.image()
-	c.launch()
-	c.connectivity()
+	startupDeadline := clabernetesutil.GetEnvDurationOrDefault(
+		clabernetesconstants.LauncherStartupDeadline,
+		0,
+	)
+
+	var deadlineTimer *time.Timer
+
+	if startupDeadline > 0 {
+		deadlineTimer = time.AfterFunc(startupDeadline, func() {
+			c.startupDeadlineExceeded(startupDeadline)
+		})
+	}
+
+	for _, phase := range []struct {
+		name string
+		f    func()
+	}{
+		{name: "prepare-work-dir", f: c.prepareWorkDir},
+		{name: "validate-config", f: c.validateConfig},
+		{name: "prepare-topology", f: c.prepareTopology},
+		{name: "docker-cli-preflight", f: c.dockerCLIPreflight},
+		{name: "start-http-server", f: c.startHTTPServer},
+		{name: "containerlab-version", f: c.containerlabVersion},
+		{name: "setup", f: c.setup},
+		{name: "image", f: c.image},
+		{name: "image-build", f: c.imageBuild},
+		{name: "image-pull", f: c.imagePull},
+		{name: "launch", f: c.launch},
+		{name: "connectivity", f: c.connectivity},
+	} {
+		c.startupTimings.run(phase.name, phase.f)
+	}
+
+	if deadlineTimer != nil {
+		deadlineTimer.Stop()
+	}
+
+	c.reportStartupTimings()
+
+	c.startupComplete.Store(true)
 
 	go c.imageCleanup()
 	go c.runProbes()
+	go c.watchNodeStates()
 	go c.watchContainers()
+	go c.heartbeat()
+	go c.watchDocker()
 
 	c.logger.Info("running for forever or until sigint...")
 
 	<-c.ctx.Done()
 
+	c.stopNodes()
+
+	c.cleanupHelperContainers()
+
+	c.reportNodeExitCodes()
+
+	if c.logTailState != nil {
+		err := c.logTailState.save()
+		if err != nil {
+			c.logger.Warnf("failed saving log tail state, err: %s", err)
+		}
+	}
+
+	c.uploadLogs()
+
 	claberneteslogging.GetManager().Flush()
 }
 
+// startupDeadlineExceeded cancels everything and crashes the launcher, reporting where the startup
+// time went.
+func (c *clabernetes) startupDeadlineExceeded(startupDeadline time.Duration) {
+	if c.degraded() != "" {
+		// held on a node failure for debugging, keep it that way
+		return
+	}
+
+	c.cancel()
+
+	c.logger.Fatalf(
+		"startup deadline of %s exceeded, startup phase timings:\n%s",
+		startupDeadline,
+		c.startupTimings.summary(),
+	)
+}
+
+// reportStartupTimings logs the startup phase timings and writes them to the work directory so
+// they end up in log uploads and diagnostics bundles.
+func (c *clabernetes) reportStartupTimings() {
+	c.logger.Infof(
+		"startup completed in %s, startup phase timings:\n%s",
+		c.startupTimings.total().Round(time.Millisecond),
+		c.startupTimings.summary(),
+	)
+
+	err := c.startupTimings.writeFile(c.workPath(startupTimingsFileName))
+	if err != nil {
+		c.logger.Warnf("failed writing startup timings, err: %s", err)
+	}
+}
+
+func (c *clabernetes) prepareWorkDir() {
+	c.logger.Debugf("ensuring work directory %q exists...", c.workDir)
+
+	err := os.MkdirAll(c.workDir, clabernetesconstants.PermissionsEveryoneAllPermissions)
+	if err != nil {
+		c.logger.Fatalf("failed creating work directory %q, err: %s", c.workDir, err)
+	}
+}
+
+// configValidator validates a single piece of launcher configuration, name is used to report a
+// failed validation.
+type configValidator struct {
+	name     string
+	validate func() error
+}
+
+// discardValue adapts a config loader to a validation func, discarding the loaded value.
+func discardValue[T any](load func() (T, error)) func() error {
+	return func() error {
+		_, err := load()
+
+		return err
+	}
+}
+
+// validateEnvIfSet returns a validation func that validates the value of the given env var only if
+// it is non-empty.
+func validateEnvIfSet(envName string, validate func(string) error) func() error {
+	return func() error {
+		value := os.Getenv(envName)
+		if value == "" {
+			return nil
+		}
+
+		return validate(value)
+	}
+}
+
+// validateEnvOrDefault returns a validation func that validates the value of the given env var, or
+// the given default if it is unset.
+func validateEnvOrDefault(
+	envName, defaultValue string,
+	validate func(string) error,
+) func() error {
+	return func() error {
+		return validate(clabernetesutil.GetEnvStrOrDefault(envName, defaultValue))
+	}
+}
+
+func (c *clabernetes) configValidators() []configValidator {
+	return []configValidator{
+		{
+			name: "node log prefix format",
+			validate: func() error {
+				return validateNodeLogPrefixFormat(c.nodeLogPrefixFormat)
+			},
+		},
+		{
+			name: "node log destinations",
+			validate: func() error {
+				_, err := parseNodeLogDestinations(
+					os.Getenv(clabernetesconstants.LauncherNodeLogDestinations),
+				)
+
+				return err
+			},
+		},
+		{
+			name: "image pull policy",
+			validate: validateEnvIfSet(
+				clabernetesconstants.LauncherImagePullPolicy,
+				validateImagePullPolicy,
+			),
+		},
+		{
+			name: "container restart policy",
+			validate: validateEnvIfSet(
+				clabernetesconstants.LauncherContainerRestartPolicy,
+				validateRestartPolicy,
+			),
+		},
+		{
+			name: "docker watchdog policy",
+			validate: validateEnvIfSet(
+				clabernetesconstants.LauncherDockerWatchdogPolicy,
+				validateDockerWatchdogPolicy,
+			),
+		},
+		{
+			name: "node ready log pattern",
+			validate: func() error {
+				_, err := regexp.Compile(
+					os.Getenv(clabernetesconstants.LauncherNodeReadyLogPattern),
+				)
+
+				return err
+			},
+		},
+		{name: "node reachability", validate: discardValue(loadNodeReachability)},
+		{
+			name: "expected nodes",
+			validate: func() error {
+				_, _, err := expectedNodes(c.nodeName)
+
+				return err
+			},
+		},
+		{
+			name: "daemon config mode",
+			validate: validateEnvIfSet(
+				clabernetesconstants.LauncherDaemonConfigMode,
+				validateDaemonConfigMode,
+			),
+		},
+		{
+			name: "existing node policy",
+			validate: validateEnvIfSet(
+				clabernetesconstants.LauncherExistingNodePolicy,
+				validateExistingNodePolicy,
+			),
+		},
+		{
+			name: "partial failure policy",
+			validate: validateEnvOrDefault(
+				clabernetesconstants.LauncherPartialFailurePolicy,
+				partialFailurePolicyBestEffort,
+				validatePartialFailurePolicy,
+			),
+		},
+		{name: "image build args", validate: discardValue(loadImageBuildArgs)},
+		{name: "node resources", validate: discardValue(loadNodeResources)},
+		{name: "node launch workers", validate: discardValue(loadNodeLaunchWorkers)},
+		{name: "node sysctls", validate: discardValue(loadNodeSysctls)},
+		{name: "node stop config", validate: discardValue(loadNodeStopConfig)},
+		{name: "container stop timeout", validate: discardValue(loadContainerStopTimeout)},
+		{name: "overlay network config", validate: discardValue(loadOverlayNetworkConfig)},
+		{
+			name: "node labels",
+			validate: func() error {
+				_, err := parseNodeLabels(os.Getenv(clabernetesconstants.LauncherNodeLabels))
+
+				return err
+			},
+		},
+		{
+			name: "wait poll interval",
+			validate: func() error {
+				// unlike most settings an explicitly empty poll interval is invalid
+				value, ok := os.LookupEnv(clabernetesconstants.LauncherWaitPollInterval)
+				if !ok {
+					return nil
+				}
+
+				return validateWaitPollInterval(value)
+			},
+		},
+		{
+			name: "post convergence hook policy",
+			validate: validateEnvOrDefault(
+				clabernetesconstants.LauncherPostConvergenceHookPolicy,
+				postConvergenceHookPolicyWarn,
+				validatePostConvergenceHookPolicy,
+			),
+		},
+		{
+			name: "ip tables backend",
+			validate: validateEnvOrDefault(
+				clabernetesconstants.LauncherIPTablesBackend,
+				ipTablesBackendAuto,
+				validateIPTablesBackend,
+			),
+		},
+		{
+			name: "node dns config",
+			validate: func() error {
+				_, _, err := loadNodeDNS()
+
+				return err
+			},
+		},
+	}
+}
+
+// validateConfig validates any launcher configuration that can be checked upfront so that we fail
+// fast rather than deep into startup.
+func (c *clabernetes) validateConfig() {
+	err := checkRunMode(
+		strings.EqualFold(
+			os.Getenv(clabernetesconstants.LauncherPrivilegedEnv),
+			clabernetesconstants.True,
+		),
+		strings.EqualFold(
+			os.Getenv(clabernetesconstants.LauncherRootlessEnv),
+			clabernetesconstants.True,
+		),
+		os.Geteuid(),
+	)
+	if err != nil {
+		c.logger.Fatalf("launcher is running as the wrong user for its mode, err: %s", err)
+	}
+
+	for _, validator := range c.configValidators() {
+		err = validator.validate()
+		if err != nil {
+			c.logger.Fatalf("invalid %s, err: %s", validator.name, err)
+		}
+	}
+}
+
+// checkRunMode ensures the effective uid the launcher runs as matches the selected mode --
+// privileged mode requires root, rootless mode requires a non-root user.
+func checkRunMode(privileged, rootless bool, euid int) error {
+	switch {
+	case privileged && rootless:
+		return fmt.Errorf(
+			"%w: %s and %s are mutually exclusive",
+			claberneteserrors.ErrLaunch,
+			clabernetesconstants.LauncherPrivilegedEnv,
+			clabernetesconstants.LauncherRootlessEnv,
+		)
+	case privileged && euid != 0:
+		return fmt.Errorf(
+			"%w: privileged mode requires running as root but running as uid %d",
+			claberneteserrors.ErrLaunch,
+			euid,
+		)
+	case rootless && euid == 0:
+		return fmt.Errorf(
+			"%w: rootless mode requires running as a non-root user but running as root",
+			claberneteserrors.ErrLaunch,
+		)
+	}
+
+	return nil
+}
+
+// launcherWorkDir returns the configured launcher work directory, see LauncherWorkDir.
+func launcherWorkDir() string {
+	return clabernetesutil.GetEnvStrOrDefault(
+		clabernetesconstants.LauncherWorkDir,
+		defaultWorkDir,
+	)
+}
+
+// workPath returns the given path elements joined and relative to the launcher work directory.
+func (c *clabernetes) workPath(elem ...string) string {
+	return filepath.Join(append([]string{c.workDir}, elem...)...)
+}
+
 func (c *clabernetes) containerlabVersion() {
 	c.logger.Debug("checking containerlab version settings...")
 
@@ -153,61 +542,278 @@ func (c *clabernetes) setup() {
 		c.handleMounts()
 	}
 
-	if daemonConfigExists() {
-		c.logger.Infof("%q exists, skipping insecure registries", dockerDaemonConfig)
+	// whether the launcher owns the docker daemon config, and so may rewrite it if docker fails to
+	// start with it
+	daemonConfigManaged := !dockerHostIsExternal() &&
+		!daemonConfigExists() &&
+		clabernetesutil.GetEnvStrOrDefault(
+			clabernetesconstants.LauncherDaemonConfigMode,
+			daemonConfigModeWrite,
+		) == daemonConfigModeWrite
+
+	if dockerHostIsExternal() {
+		c.logger.Infof(
+			"%s points at an external docker daemon, skipping docker daemon config",
+			dockerHostEnv,
+		)
+	} else if daemonConfigExists() {
+		c.logger.Infof("%q exists, skipping docker daemon config", dockerDaemonConfigPath())
 	} else {
-		c.logger.Debug("configure insecure registries if requested...")
+		c.logger.Debug("configure docker daemon (insecure registries, bip, etc.) if requested...")
 
-		err := handleInsecureRegistries()
+		err := c.startupTimings.runE("setup/daemon-config", func() error {
+			return handleInsecureRegistries(c.ctx, c.logger)
+		})
 		if err != nil {
-			c.logger.Fatalf("failed configuring insecure docker registries, err: %s", err)
+			c.logger.Fatalf("failed configuring docker daemon, err: %s", err)
 		}
 	}
 
-	c.logger.Debug("ensuring docker is running...")
+	if !dockerHostIsExternal() && strings.EqualFold(
+		os.Getenv(clabernetesconstants.LauncherDaemonConfigStrict),
+		clabernetesconstants.True,
+	) {
+		err := validateDaemonConfigFile(dockerDaemonConfigPath())
+		if err != nil {
+			c.logger.Fatalf("docker daemon config failed validation, err: %s", err)
+		}
+	}
+
+	ipTablesBackend := clabernetesutil.GetEnvStrOrDefault(
+		clabernetesconstants.LauncherIPTablesBackend,
+		ipTablesBackendAuto,
+	)
+
+	if !dockerHostIsExternal() {
+		if ipTablesBackend != ipTablesBackendAuto {
+			err := c.startupTimings.runE("setup/iptables-backend", func() error {
+				return setIPTablesBackend(c.ctx, c.logger, ipTablesBackend)
+			})
+			if err != nil {
+				c.logger.Fatalf(
+					"failed selecting %s ip tables backend, err: %s",
+					ipTablesBackend,
+					err,
+				)
+			}
+		}
+
+		c.logger.Infof("using %s ip tables backend", ipTablesBackend)
+	}
+
+	c.runPreDockerHook()
+
+	c.ensureDockerRunning(daemonConfigManaged, ipTablesBackend)
+
+	c.runPostDockerHook()
+
+	c.selfTest()
+
+	if !strings.EqualFold(
+		os.Getenv(clabernetesconstants.LauncherSkipDockerInfo),
+		clabernetesconstants.True,
+	) {
+		c.logDockerInfo()
+	}
+
+	c.logger.Debug("getting files from url if requested...")
 
-	err := startDocker(c.ctx, c.logger)
+	err := c.getFilesFromURL()
 	if err != nil {
+		c.logger.Fatalf("failed getting file(s) from remote url, err: %s", err)
+	}
+}
+
+// ensureDockerRunning starts docker, falling back to the vfs storage driver if the launcher manages
+// the daemon config and overlay2 is not usable, and to legacy ip tables if the ip tables backend is
+// "auto" and docker fails to start with the default backend. Failing to start docker is fatal.
+func (c *clabernetes) ensureDockerRunning(daemonConfigManaged bool, ipTablesBackend string) {
+	c.logger.Debug("ensuring docker is running...")
+
+	err := c.startupTimings.runE("setup/docker-start", func() error {
+		return startDocker(c.ctx, c.logger)
+	})
+	if err != nil && daemonConfigManaged {
+		err = c.fallbackToVFSStorageDriver(err)
+	}
+
+	switch {
+	case err == nil:
+	case dockerHostIsExternal():
+		c.logger.Fatalf("failed reaching external docker daemon, err: %s", err)
+	case ipTablesBackend != ipTablesBackendAuto:
+		c.reportDockerDaemonLogs()
+
+		c.logger.Fatalf(
+			"failed ensuring docker is running with the selected %s ip tables backend, err: %s",
+			ipTablesBackend,
+			err,
+		)
+	case strings.EqualFold(
+		os.Getenv(clabernetesconstants.LauncherSkipIPTablesLegacy),
+		clabernetesconstants.True,
+	):
+		c.reportDockerDaemonLogs()
+
+		c.logger.Infof(
+			"%s is set, skipped fallback to legacy ip tables",
+			clabernetesconstants.LauncherSkipIPTablesLegacy,
+		)
+
+		c.logger.Fatalf("failed ensuring docker is running, err: %s", err)
+	default:
+		c.reportDockerDaemonLogs()
+
 		c.logger.Warn(
 			"failed ensuring docker is running, attempting to fallback to legacy ip tables",
 		)
 
+		// docker failing to start with the image's default (nft) backend is the known bad
+		// combination the auto backend handles
 		// see https://github.com/srl-labs/clabernetes/issues/47
-		err = enableLegacyIPTables(c.ctx, c.logger)
+		err = c.startupTimings.runE("setup/iptables-legacy", func() error {
+			return setIPTablesBackend(c.ctx, c.logger, ipTablesBackendLegacy)
+		})
 		if err != nil {
 			c.logger.Fatalf("failed enabling legacy ip tables, err: %s", err)
 		}
 
-		err = startDocker(c.ctx, c.logger)
+		err = c.startupTimings.runE("setup/docker-start-legacy-iptables", func() error {
+			return startDocker(c.ctx, c.logger)
+		})
 		if err != nil {
+			c.reportDockerDaemonLogs()
+
 			c.logger.Fatalf("failed ensuring docker is running, err: %s", err)
 		}
 
 		c.logger.Warn("docker started, but using legacy ip tables")
 	}
+}
 
-	c.logger.Debug("getting files from url if requested...")
+// reportDockerDaemonLogs logs the tail of the docker daemon log and writes it to the work
+// directory so that the actual reason dockerd failed to start is visible.
+func (c *clabernetes) reportDockerDaemonLogs() {
+	daemonLogTail, err := getDockerDaemonLogTail(c.ctx, dockerDaemonLogTailLineCount)
+	if err != nil {
+		c.logger.Warnf("failed reading docker daemon logs, err: %s", err)
 
-	err = c.getFilesFromURL()
+		return
+	}
+
+	c.logger.Warnf("docker daemon log tail:\n%s", daemonLogTail)
+
+	err = os.WriteFile(
+		c.workPath(dockerDaemonLogFileName),
+		daemonLogTail,
+		clabernetesconstants.PermissionsEveryoneReadWrite,
+	)
 	if err != nil {
-		c.logger.Fatalf("failed getting file(s) from remote url, err: %s", err)
+		c.logger.Warnf("failed writing %q, err: %s", dockerDaemonLogFileName, err)
 	}
 }
 
-func (c *clabernetes) launch() {
-	c.logger.Debug("launching containerlab...")
+// fallbackToVFSStorageDriver handles docker failing to start (with the given error) -- if the
+// daemon logs show that the failure is down to the overlay2 storage driver not being usable on the
+// host, the daemon config is rewritten to use the vfs storage driver and docker is started once
+// more, returning the result of that. Otherwise, the given error is returned as is.
+func (c *clabernetes) fallbackToVFSStorageDriver(startErr error) error {
+	daemonLogTail, err := getDockerDaemonLogTail(c.ctx, dockerDaemonLogTailLineCount)
+	if err != nil {
+		return startErr
+	}
+
+	overlayErr := overlayStorageDriverError(string(daemonLogTail))
+	if overlayErr == "" {
+		return startErr
+	}
+
+	c.logger.Warnf(
+		"!!! docker failed to start due to the overlay2 storage driver (%q), falling back to the "+
+			"vfs storage driver -- nodes will use more disk space and start slower !!!",
+		overlayErr,
+	)
 
-	err := c.runContainerlab()
+	c.reportDockerDaemonLogs()
+
+	err = setDaemonConfigStorageDriver(dockerDaemonConfigPath(), vfsStorageDriver)
 	if err != nil {
-		c.logger.Criticalf(
-			"failed launching containerlab,"+
-				" will try to gather crashed container logs then will exit, err: %s", err,
-		)
+		c.logger.Warnf("failed switching docker daemon config to vfs storage driver, err: %s", err)
+
+		return startErr
+	}
+
+	err = c.startupTimings.runE("setup/docker-start-vfs", func() error {
+		return startDocker(c.ctx, c.logger)
+	})
+	if err != nil {
+		return err
+	}
+
+	c.logger.Warn("docker started, but using the vfs storage driver")
+
+	return nil
+}
+
+func (c *clabernetes) logDockerInfo() {
+	info, err := getDockerInfo(c.ctx)
+	if err != nil {
+		c.logger.Warnf("failed gathering docker info, will continue, err: %s", err)
+
+		return
+	}
+
+	c.logger.Infof(
+		"docker %s running with storage driver %q, cgroup driver %q, cgroup version %q",
+		info.ServerVersion,
+		info.StorageDriver,
+		info.CgroupDriver,
+		info.CgroupVersion,
+	)
 
-		c.reportContainerLaunchFail()
+	for _, warning := range info.Warnings {
+		c.logger.Warnf("docker reported warning: %s", strings.TrimSpace(warning))
 	}
+}
+
+func (c *clabernetes) launch() {
+	c.injectNodeEnv()
+	c.injectNodeResources()
+	c.injectNodeSysctls()
+	c.injectNodeDNS()
+	c.setupOverlayNetwork()
+
+	if c.handleExistingNodes() {
+		c.logger.Debug("reused existing node containers, not launching containerlab")
+	} else {
+		// injected only now so the (per pod) launcher instance label doesn't change the topology
+		// config hash the existing node check compares
+		c.injectNodeLabels()
+
+		c.logger.Debug("launching containerlab...")
+
+		err := c.runContainerlab()
+		if err != nil {
+			c.logger.Criticalf(
+				"failed launching containerlab,"+
+					" will try to gather crashed container logs then will exit, err: %s", err,
+			)
 
-	c.containerIDs, err = getContainerIDs(c.ctx, false)
+			c.reportContainerLaunchFail()
+		}
+	}
+
+	var err error
+
+	c.containerIDs, err = getContainerIDsWithRetry(
+		c.ctx,
+		false,
+		clabernetesutil.GetEnvIntOrDefault(clabernetesconstants.LauncherContainerListMinCount, 1),
+		clabernetesutil.GetEnvDurationOrDefault(
+			clabernetesconstants.LauncherContainerListTimeout,
+			0,
+		),
+	)
 	if err != nil {
 		c.logger.Warnf(
 			"failed determining container ids will continue but will not log container output,"+
@@ -219,9 +825,15 @@ func (c *clabernetes) launch() {
 	if len(c.containerIDs) > 0 {
 		c.logger.Debugf("found container ids %q", c.containerIDs)
 
-		err = tailContainerLogs(c.ctx, c.logger, c.nodeLogger, c.containerIDs)
+		c.setRestartPolicy()
+
+		if c.dockerSubcommandAvailable("events") {
+			go c.updateTailsOnContainerEvents()
+		}
+
+		c.containerLogFiles, err = c.tailContainerLogs(c.containerIDs)
 		if err != nil {
-			c.logger.Warnf("failed creating node log file, err: %s", err)
+			c.logger.Warnf("failed tailing node logs, err: %s", err)
 		}
 	} else {
 		c.logger.Warn(
@@ -230,14 +842,139 @@ func (c *clabernetes) launch() {
 		)
 	}
 
-	c.nodeContainerID, err = getContainerIDForNodeName(c.ctx, c.nodeName)
+	c.nodeContainers, err = newNodeContainerIndex(c.ctx)
 	if err != nil {
-		c.logger.Fatalf("failed determining node %q container id, err: %s", c.nodeName, err)
+		c.logger.Warnf("failed building node container index, will continue, err: %s", err)
+
+		c.nodeContainers = &nodeContainerIndex{nodeContainers: map[string]string{}}
 	}
 
+	c.applyNodePidsLimits()
+
+	c.connectNodesToOverlayNetwork()
+
+	c.waitNodesConverged()
+
 	c.logger.Debug("containerlab launched successfully")
 }
 
+// waitNodesConverged gates the launch on the nodes converging -- all nodes reporting ready (per
+// the partial failure policy), the expected node containers running, the node logging its ready
+// line and being reachable -- and then runs the post convergence hook.
+func (c *clabernetes) waitNodesConverged() {
+	// validated in validateConfig
+	nodeNames, expectedCount, _ := expectedNodes(c.nodeName)
+
+	var nodeStatuses map[string]*nodeReadyStatus
+
+	// validated in validateConfig
+	partialFailurePolicy := clabernetesutil.GetEnvStrOrDefault(
+		clabernetesconstants.LauncherPartialFailurePolicy,
+		partialFailurePolicyBestEffort,
+	)
+
+	err := c.startupTimings.runE("launch/node-ready-wait", func() error {
+		var waitErr error
+
+		nodeStatuses, waitErr = waitAllNodesReady(
+			c.ctx,
+			nodeNames,
+			nodeReadyTimeout,
+			partialFailurePolicy == partialFailurePolicyFailFast,
+		)
+
+		return waitErr
+	})
+
+	for _, nodeName := range nodeNames {
+		c.logger.Infof("node %q %s", nodeName, nodeStatuses[nodeName].outcome())
+	}
+
+	switch {
+	case err == nil:
+	case partialFailurePolicy == partialFailurePolicyFailFast:
+		c.nodeFailure(
+			"not all nodes reported ready and partial failure policy is %q, err: %s",
+			partialFailurePolicyFailFast,
+			err,
+		)
+	default:
+		c.logger.Warnf("not all nodes reported ready, will continue, err: %s", err)
+	}
+
+	nodesReadyErr := err
+
+	err = c.startupTimings.runE("launch/expected-node-count-wait", func() error {
+		return waitExpectedNodeCount(c.ctx, expectedCount, nodeReadyTimeout)
+	})
+	if err != nil {
+		c.nodeFailure("expected node containers never started, err: %s", err)
+	}
+
+	c.nodeContainerID = nodeStatuses[c.nodeName].ContainerID
+	if c.nodeContainerID == "" {
+		c.nodeFailure(
+			"failed determining node %q container id, err: %s",
+			c.nodeName,
+			nodeStatuses[c.nodeName].Err,
+		)
+	}
+
+	c.waitNodeReadyLogLine()
+
+	c.waitNodeReachable()
+
+	c.runPostConvergenceHook(nodeStatuses, nodesReadyErr)
+}
+
+// waitNodeReadyLogLine waits for the node container to log a line matching the configured node
+// ready log pattern (if any), so that readiness reflects the node's own application level signal
+// rather than just the container running.
+func (c *clabernetes) waitNodeReadyLogLine() {
+	readyLogPattern := os.Getenv(clabernetesconstants.LauncherNodeReadyLogPattern)
+	if readyLogPattern == "" {
+		return
+	}
+
+	// validated in validateConfig
+	pattern := regexp.MustCompile(readyLogPattern)
+
+	timeout := clabernetesutil.GetEnvDurationOrDefault(
+		clabernetesconstants.LauncherNodeReadyLogTimeout,
+		nodeReadyTimeout,
+	)
+
+	err := c.startupTimings.runE("launch/node-ready-log-wait", func() error {
+		return waitForLogLine(c.ctx, c.nodeContainerID, pattern, timeout)
+	})
+	if err != nil {
+		c.logger.Warnf("node did not log its ready line, will continue, err: %s", err)
+
+		return
+	}
+
+	c.logger.Infof("node logged a line matching ready log pattern %q", readyLogPattern)
+}
+
+// setRestartPolicy applies the user provided restart policy (if any) to all the launched
+// containers. The restart policy is handled by the docker daemon in the launcher pod, so it only
+// covers node container crashes -- when the launcher exits, the pod and its docker daemon go too.
+func (c *clabernetes) setRestartPolicy() {
+	restartPolicy := os.Getenv(clabernetesconstants.LauncherContainerRestartPolicy)
+	if restartPolicy == "" {
+		return
+	}
+
+	err := setContainersRestartPolicy(c.ctx, c.containerIDs, restartPolicy)
+	if err != nil {
+		c.logger.Warnf("failed setting container restart policy, will continue, err: %s", err)
+
+		return
+	}
+
+	c.logger.Infof("set restart policy %q on container ids %q", restartPolicy, c.containerIDs)
+}
+
 func (c *clabernetes) runProbes() {
 	c.logger.Debug("starting status probe(s) if configured...")
 
@@ -308,7 +1045,10 @@ func (c *clabernetes) runProbes() {
 				Timeout: statusProbeCheckTimeout,
 			}
 
-			tcpConn, err := dialer.Dial("tcp", fmt.Sprintf("%s:%d", nodeAddr, tcpProbePort))
+			tcpConn, err := dialer.Dial(
+				"tcp",
+				net.JoinHostPort(nodeAddr, strconv.Itoa(tcpProbePort)),
+			)
 			if err != nil {
 				tcpProbeOk = false
 			} else {
@@ -371,7 +1111,7 @@ func probeSSH(port int, nodeAddr, username, password string) bool {
 
 	conn, err := ssh.Dial(
 		"tcp",
-		fmt.Sprintf("%s:%d", nodeAddr, port),
+		net.JoinHostPort(nodeAddr, strconv.Itoa(port)),
 		sshConfig,
 	)
 	if err != nil {
@@ -390,7 +1130,20 @@ func (c *clabernetes) watchContainers() {
 
 	ticker := time.NewTicker(containerCheckInterval)
 
-	for range ticker.C {
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-c.nodeStates.changed:
+			// a container changed state, check right away rather than waiting for the next tick
+		case <-ticker.C:
+			// while the node states are live from docker events there is nothing to poll for,
+			// changes are checked as they happen
+			if c.nodeStates.isLive() {
+				continue
+			}
+		}
+
 		currentContainerIDs, err := getContainerIDs(c.ctx, false)
 		if err != nil {
 			c.logger.Warnf(
@@ -400,12 +1153,25 @@ func (c *clabernetes) watchContainers() {
 		}
 
 		if len(currentContainerIDs) != len(c.containerIDs) {
-			c.logger.Criticalf(
-				"expected %d running containers, but got %d, sending done signal",
+			for _, containerID := range c.containerIDs {
+				if !slices.Contains(currentContainerIDs, containerID) {
+					c.checkContainerOOMKilled(containerID)
+					c.checkContainerExitCode(containerID)
+				}
+			}
+
+			reason := fmt.Sprintf(
+				"expected %d running containers, but got %d",
 				len(c.containerIDs),
 				len(currentContainerIDs),
 			)
 
+			if c.degrade(reason) {
+				return
+			}
+
+			c.logger.Criticalf("%s, sending done signal", reason)
+
 			c.cancel()
 
 			return
@@ -416,7 +1182,7 @@ func (c *clabernetes) watchContainers() {
 func (c *clabernetes) reportContainerLaunchFail() {
 	allContainerIDs, err := getContainerIDs(c.ctx, true)
 	if err != nil {
-		c.logger.Fatalf(
+		c.nodeFailure(
 			"failed launching containerlab, then failed gathering all container "+
 				"ids to report container status. error: %s", err,
 		)
@@ -424,6 +1190,8 @@ func (c *clabernetes) reportContainerLaunchFail() {
 
 	printContainerLogs(c.ctx, c.nodeLogger, allContainerIDs)
 
+	c.holdOnFailure("failed launching containerlab")
+
 	claberneteslogging.GetManager().Flush()
 
 	os.Exit(clabernetesconstants.ExitCodeError)
@@ -0,0 +1,124 @@
+package launcher_test
+
+import (
+	"cmp"
+	"os"
+	"path/filepath"
+	"testing"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+func TestCheckRunMode(t *testing.T) {
+	cases := []struct {
+		name       string
+		privileged bool
+		rootless   bool
+		euid       int
+		expectErr  bool
+	}{
+		{
+			name: "default-root",
+			euid: 0,
+		},
+		{
+			name:       "privileged-root",
+			privileged: true,
+			euid:       0,
+		},
+		{
+			name:       "privileged-non-root",
+			privileged: true,
+			euid:       1000,
+			expectErr:  true,
+		},
+		{
+			name:     "rootless-non-root",
+			rootless: true,
+			euid:     1000,
+		},
+		{
+			name:      "rootless-root",
+			rootless:  true,
+			euid:      0,
+			expectErr: true,
+		},
+		{
+			name:       "privileged-and-rootless",
+			privileged: true,
+			rootless:   true,
+			euid:       0,
+			expectErr:  true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				err := claberneteslauncher.CheckRunMode(
+					testCase.privileged,
+					testCase.rootless,
+					testCase.euid,
+				)
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+			})
+	}
+}
+
+func TestPrepareWorkDir(t *testing.T) {
+	cases := []struct {
+		name    string
+		workDir string
+	}{
+		{
+			name:    "default",
+			workDir: "",
+		},
+		{
+			name:    "configured",
+			workDir: filepath.Join(t.TempDir(), "nested", "work"),
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				t.Setenv(clabernetesconstants.LauncherWorkDir, testCase.workDir)
+
+				// an unset work directory means the current directory
+				expectedWorkDir := cmp.Or(testCase.workDir, ".")
+
+				workDir, paths := claberneteslauncher.PrepareWorkDir("clab-topo-srl1.log")
+				if workDir != expectedWorkDir {
+					clabernetestesthelper.FailOutput(t, workDir, expectedWorkDir)
+				}
+
+				info, err := os.Stat(workDir)
+				if err != nil || !info.IsDir() {
+					t.Fatalf("expected work directory %q to be created, err: %v", workDir, err)
+				}
+
+				expectedPaths := map[string]string{
+					"node-log": filepath.Join(expectedWorkDir, "node.log"),
+					"container-log": filepath.Join(
+						expectedWorkDir,
+						"node-logs",
+						"clab-topo-srl1.log",
+					),
+					"image": filepath.Join(expectedWorkDir, ".image", "node-image.tar"),
+				}
+
+				clabernetestesthelper.MarshaledEqual(t, paths, expectedPaths)
+			})
+	}
+}
@@ -0,0 +1,24 @@
+package launcher
+
+import "os/exec"
+
+// commandRunner is the interface the launcher uses to actually execute the commands it builds --
+// this exists so that we can swap out the "real" runner for a fake one in tests.
+type commandRunner interface {
+	// Run runs the given command, see exec.Cmd Run.
+	Run(cmd *exec.Cmd) error
+	// Output runs the given command and returns its stdout, see exec.Cmd Output.
+	Output(cmd *exec.Cmd) ([]byte, error)
+}
+
+type execCommandRunner struct{}
+
+func (r *execCommandRunner) Run(cmd *exec.Cmd) error {
+	return cmd.Run()
+}
+
+func (r *execCommandRunner) Output(cmd *exec.Cmd) ([]byte, error) {
+	return cmd.Output()
+}
+
+var runner commandRunner = &execCommandRunner{} //nolint:gochecknoglobals
@@ -9,11 +9,15 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"strconv"
 
 	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
 	clabernetesutil "github.com/srl-labs/clabernetes/util"
 )
 
+const containerlabLogFileName = "containerlab.log"
+
 func extractContainerlabBin(r io.Reader) error {
 	gzipReader, err := gzip.NewReader(r)
 	if err != nil {
@@ -104,8 +108,28 @@ func (c *clabernetes) installContainerlabVersion(version string) error {
 	return extractContainerlabBin(inTarFile)
 }
 
+// loadNodeLaunchWorkers loads the number of nodes containerlab may create and start at once from
+// LauncherNodeLaunchWorkers, zero (containerlab's default of all nodes at once) if unset.
+func loadNodeLaunchWorkers() (int, error) {
+	rawWorkers := os.Getenv(clabernetesconstants.LauncherNodeLaunchWorkers)
+	if rawWorkers == "" {
+		return 0, nil
+	}
+
+	workers, err := strconv.Atoi(rawWorkers)
+	if err != nil || workers < 1 {
+		return 0, fmt.Errorf(
+			"%w: invalid node launch workers %q, must be a positive number",
+			claberneteserrors.ErrLaunch,
+			rawWorkers,
+		)
+	}
+
+	return workers, nil
+}
+
 func (c *clabernetes) runContainerlab() error {
-	containerlabLogFile, err := os.Create("containerlab.log")
+	containerlabLogFile, err := os.Create(c.workPath(containerlabLogFileName))
 	if err != nil {
 		return err
 	}
@@ -115,7 +139,7 @@ func (c *clabernetes) runContainerlab() error {
 	args := []string{
 		"deploy",
 		"-t",
-		"topo.clab.yaml",
+		c.topologyPath(),
 	}
 
 	if !(os.Getenv(clabernetesconstants.LauncherContainerlabPersist) == clabernetesconstants.True) {
@@ -131,6 +155,14 @@ func (c *clabernetes) runContainerlab() error {
 		args = append(args, []string{"--timeout", containerlabTimeout}...)
 	}
 
+	// validated in validateConfig; containerlab creates and starts the nodes with this many
+	// workers, any images it still has to pull are pulled before that so pulls never compound
+	// with node creation
+	nodeLaunchWorkers, _ := loadNodeLaunchWorkers()
+	if nodeLaunchWorkers > 0 {
+		args = append(args, "--max-workers", strconv.Itoa(nodeLaunchWorkers))
+	}
+
 	cmd := exec.CommandContext(c.ctx, "containerlab", args...)
 
 	cmd.Stdout = containerlabOutWriter
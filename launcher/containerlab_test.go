@@ -0,0 +1,56 @@
+package launcher_test
+
+import (
+	"testing"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+func TestLoadNodeLaunchWorkers(t *testing.T) {
+	cases := []struct {
+		name      string
+		workers   string
+		expected  int
+		expectErr bool
+	}{
+		{
+			name: "unset",
+		},
+		{
+			name:     "set",
+			workers:  "4",
+			expected: 4,
+		},
+		{
+			name:      "zero",
+			workers:   "0",
+			expectErr: true,
+		},
+		{
+			name:      "not-a-number",
+			workers:   "lots",
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				t.Setenv(clabernetesconstants.LauncherNodeLaunchWorkers, testCase.workers)
+
+				actual, err := claberneteslauncher.LoadNodeLaunchWorkers()
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+
+				if actual != testCase.expected {
+					clabernetestesthelper.FailOutput(t, actual, testCase.expected)
+				}
+			})
+	}
+}
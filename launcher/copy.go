@@ -0,0 +1,112 @@
+package launcher
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+)
+
+const copyTimeout = 10 * time.Minute
+
+// CopyFromNode copies the file or directory at src in the container of the given (exactly
+// matched) node to dst, or, if asTarGz is true, into a gzipped tarball at dst. A src with a
+// trailing slash copies the contents of that directory rather than the directory itself.
+func CopyFromNode(nodeName, src, dst string, asTarGz bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), copyTimeout)
+	defer cancel()
+
+	index, err := newNodeContainerIndex(ctx)
+	if err != nil {
+		return err
+	}
+
+	containerID, err := index.resolve(ctx, nodeName)
+	if err != nil {
+		return err
+	}
+
+	if containerID == "" {
+		return fmt.Errorf(
+			"%w: no container found for node %q",
+			claberneteserrors.ErrContainerNotFound,
+			nodeName,
+		)
+	}
+
+	if asTarGz {
+		return copyFromContainerTarGz(ctx, containerID, src, dst)
+	}
+
+	return copyFromContainer(ctx, containerID, src, dst)
+}
+
+// copyFromContainer copies the file or directory (recursively) at src in the given container to
+// dst, creating any missing parent directories of dst. As with docker cp, a directory src is
+// copied to dst if dst does not exist, or into dst if it is an existing directory. A src with a
+// trailing slash instead always copies the contents of the directory into dst (created if
+// missing) -- docker cp treats "dir/" the same as "dir", which is rarely what users expect.
+func copyFromContainer(ctx context.Context, containerID, src, dst string) error {
+	if src == "" || dst == "" {
+		return fmt.Errorf(
+			"%w: both a source and destination path are required",
+			claberneteserrors.ErrLaunch,
+		)
+	}
+
+	dstDir := filepath.Dir(dst)
+
+	if strings.HasSuffix(src, "/") {
+		// docker cp spells "copy the contents of dir" as "dir/."
+		src += "."
+		dstDir = dst
+	}
+
+	err := os.MkdirAll(dstDir, clabernetesconstants.PermissionsEveryoneReadWriteOwnerExecute)
+	if err != nil {
+		return err
+	}
+
+	cpCmd := exec.CommandContext(ctx, "docker", "cp", containerID+":"+src, dst)
+
+	_, err = runner.Output(cpCmd)
+	if err != nil {
+		return classifyDockerError(err)
+	}
+
+	return nil
+}
+
+// copyFromContainerTarGz copies the file or directory at src in the given container (see
+// copyFromContainer) into a gzipped tarball at outputPath. The tarball holds the copied file or
+// directory at its root, or for a src with a trailing slash, the contents of that directory.
+func copyFromContainerTarGz(ctx context.Context, containerID, src, outputPath string) error {
+	tempDir, err := os.MkdirTemp("", "clabernetes-copy")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = os.RemoveAll(tempDir)
+	}()
+
+	dst := tempDir
+
+	if !strings.HasSuffix(src, "/") {
+		dst = filepath.Join(tempDir, path.Base(src))
+	}
+
+	err = copyFromContainer(ctx, containerID, src, dst)
+	if err != nil {
+		return err
+	}
+
+	return writeTarGz(tempDir, outputPath)
+}
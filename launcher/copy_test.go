@@ -0,0 +1,244 @@
+package launcher_test
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+// copyingCommandRunner fakes docker cp by writing a small file tree to the copy destination,
+// mimicking docker cp's handling of "dir" vs "dir/." sources.
+type copyingCommandRunner struct {
+	*fakeCommandRunner
+}
+
+func (r *copyingCommandRunner) Output(cmd *exec.Cmd) ([]byte, error) {
+	output, err := r.fakeCommandRunner.Output(cmd)
+	if err != nil || len(cmd.Args) != 4 || cmd.Args[1] != "cp" {
+		return output, err
+	}
+
+	dst := cmd.Args[3]
+
+	if !strings.HasSuffix(cmd.Args[2], "/.") {
+		_, statErr := os.Stat(dst)
+		if statErr == nil {
+			dst = filepath.Join(dst, filepath.Base(cmd.Args[2]))
+		}
+	}
+
+	for path, content := range map[string]string{
+		"config.json":         "{}",
+		"checkpoint/0.json":   "{}",
+		"checkpoint/1.json":   "{}",
+		"appmgr/appmgr.yaml":  "apps: []",
+		"appmgr/.placeholder": "",
+	} {
+		err = os.MkdirAll(filepath.Dir(filepath.Join(dst, path)), 0o755) //nolint:gosec
+		if err != nil {
+			return nil, err
+		}
+
+		err = os.WriteFile(filepath.Join(dst, path), []byte(content), 0o644) //nolint:gosec
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return output, nil
+}
+
+func TestCopyFromContainer(t *testing.T) {
+	cases := []struct {
+		name        string
+		src         string
+		dst         string
+		cmdErr      error
+		expectedSrc string
+		expectedDir string
+		expectErr   bool
+	}{
+		{
+			name:        "directory",
+			src:         "/etc/opt/srlinux",
+			dst:         "out/nested/srlinux",
+			expectedSrc: "/etc/opt/srlinux",
+			expectedDir: "out/nested",
+		},
+		{
+			name:        "directory-trailing-slash",
+			src:         "/etc/opt/srlinux/",
+			dst:         "out/nested/srlinux",
+			expectedSrc: "/etc/opt/srlinux/.",
+			expectedDir: "out/nested/srlinux",
+		},
+		{
+			name:        "file",
+			src:         "/etc/opt/srlinux/config.json",
+			dst:         "out/config.json",
+			expectedSrc: "/etc/opt/srlinux/config.json",
+			expectedDir: "out",
+		},
+		{
+			name:      "missing-source",
+			dst:       "out",
+			expectErr: true,
+		},
+		{
+			name:        "docker-error",
+			src:         "/etc/opt/srlinux",
+			dst:         "out/srlinux",
+			cmdErr:      errFakeCommand,
+			expectedSrc: "/etc/opt/srlinux",
+			expectErr:   true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				root := t.TempDir()
+
+				dst := filepath.Join(root, testCase.dst)
+
+				cpKey := "docker cp abc123:" + testCase.expectedSrc + " " + dst
+
+				fakeRunner := newFakeCommandRunner()
+
+				fakeRunner.results[cpKey] = testCase.cmdErr
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				err := claberneteslauncher.CopyFromContainer(
+					context.Background(),
+					"abc123",
+					testCase.src,
+					dst,
+				)
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+
+				if testCase.expectedSrc != "" && fakeRunner.calls[cpKey] != 1 {
+					t.Fatalf("expected %q to be called once, calls: %v", cpKey, fakeRunner.calls)
+				}
+
+				if testCase.expectedDir == "" {
+					return
+				}
+
+				expectedDir := filepath.Join(root, testCase.expectedDir)
+
+				info, err := os.Stat(expectedDir)
+				if err != nil || !info.IsDir() {
+					t.Fatalf("expected destination directory %q to exist, err: %v", expectedDir, err)
+				}
+			})
+	}
+}
+
+func TestCopyFromContainerTarGz(t *testing.T) {
+	cases := []struct {
+		name     string
+		src      string
+		expected []string
+	}{
+		{
+			name: "directory",
+			src:  "/etc/opt/srlinux",
+			expected: []string{
+				"srlinux/appmgr/.placeholder",
+				"srlinux/appmgr/appmgr.yaml",
+				"srlinux/checkpoint/0.json",
+				"srlinux/checkpoint/1.json",
+				"srlinux/config.json",
+			},
+		},
+		{
+			name: "directory-trailing-slash",
+			src:  "/etc/opt/srlinux/",
+			expected: []string{
+				"appmgr/.placeholder",
+				"appmgr/appmgr.yaml",
+				"checkpoint/0.json",
+				"checkpoint/1.json",
+				"config.json",
+			},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				restore := claberneteslauncher.SetCommandRunner(
+					&copyingCommandRunner{fakeCommandRunner: newFakeCommandRunner()},
+				)
+				defer restore()
+
+				outputPath := filepath.Join(t.TempDir(), "copy.tar.gz")
+
+				err := claberneteslauncher.CopyFromContainerTarGz(
+					context.Background(),
+					"abc123",
+					testCase.src,
+					outputPath,
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				f, err := os.Open(outputPath) //nolint:gosec
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				defer func() {
+					_ = f.Close()
+				}()
+
+				gzipReader, err := gzip.NewReader(f)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				tarReader := tar.NewReader(gzipReader)
+
+				var actual []string
+
+				for {
+					header, err := tarReader.Next()
+					if errors.Is(err, io.EOF) {
+						break
+					}
+
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					actual = append(actual, header.Name)
+				}
+
+				slices.Sort(actual)
+
+				clabernetestesthelper.MarshaledEqual(t, actual, testCase.expected)
+			})
+	}
+}
@@ -0,0 +1,250 @@
+package launcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+)
+
+const (
+	dockerDaemonConfigSchema = "docker-daemon.schema.json"
+
+	// daemonConfigKeySuggestionRatio bounds how different (in edits per character) an unknown key
+	// may be from a known one for the known key to be suggested.
+	daemonConfigKeySuggestionRatio = 4
+	daemonConfigKeySuggestionMin   = 2
+)
+
+// daemonConfigSchema is the (small) subset of json schema used to describe the docker daemon
+// config -- types, object properties, and array items.
+type daemonConfigSchema struct {
+	Type       string                         `json:"type"`
+	Properties map[string]*daemonConfigSchema `json:"properties"`
+	// AdditionalProperties is either a boolean or a schema for any properties not in Properties
+	AdditionalProperties json.RawMessage     `json:"additionalProperties"`
+	Items                *daemonConfigSchema `json:"items"`
+}
+
+func loadDaemonConfigSchema() (*daemonConfigSchema, error) {
+	content, err := Assets.ReadFile("assets/" + dockerDaemonConfigSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := &daemonConfigSchema{}
+
+	err = json.Unmarshal(content, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return schema, nil
+}
+
+// validate returns the issues of the given (json decoded, with numbers as json.Number) value at
+// the given path against the schema.
+func (s *daemonConfigSchema) validate(path string, value any) []string {
+	actualType := jsonSchemaType(value)
+
+	if s.Type != "" && actualType != s.Type &&
+		(s.Type != "number" || actualType != "integer") {
+		if path == "" {
+			return []string{fmt.Sprintf("config must be of type %s, got %s", s.Type, actualType)}
+		}
+
+		return []string{fmt.Sprintf("%q must be of type %s, got %s", path, s.Type, actualType)}
+	}
+
+	var issues []string
+
+	switch typed := value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(typed))
+
+		for key := range typed {
+			keys = append(keys, key)
+		}
+
+		slices.Sort(keys)
+
+		for _, key := range keys {
+			issues = append(issues, s.validateProperty(path, key, typed[key])...)
+		}
+	case []any:
+		if s.Items == nil {
+			return nil
+		}
+
+		for idx, item := range typed {
+			issues = append(issues, s.Items.validate(fmt.Sprintf("%s[%d]", path, idx), item)...)
+		}
+	}
+
+	return issues
+}
+
+func (s *daemonConfigSchema) validateProperty(path, key string, value any) []string {
+	propertyPath := key
+	if path != "" {
+		propertyPath = path + "." + key
+	}
+
+	propertySchema, ok := s.Properties[key]
+	if ok {
+		return propertySchema.validate(propertyPath, value)
+	}
+
+	additional := bytes.TrimSpace(s.AdditionalProperties)
+
+	switch {
+	case len(additional) == 0 || bytes.Equal(additional, []byte("true")):
+		return nil
+	case bytes.Equal(additional, []byte("false")):
+		issue := fmt.Sprintf("unknown key %q", propertyPath)
+
+		suggestion := s.suggestProperty(key)
+		if suggestion != "" {
+			issue += fmt.Sprintf(", did you mean %q?", suggestion)
+		}
+
+		return []string{issue}
+	}
+
+	additionalSchema := &daemonConfigSchema{}
+
+	err := json.Unmarshal(additional, additionalSchema)
+	if err != nil {
+		return []string{fmt.Sprintf("invalid schema for %q, err: %s", propertyPath, err)}
+	}
+
+	return additionalSchema.validate(propertyPath, value)
+}
+
+// suggestProperty returns the known property closest to the given unknown key, or an empty string
+// if none is close enough to be what was meant.
+func (s *daemonConfigSchema) suggestProperty(key string) string {
+	var suggestion string
+
+	bestDistance := max(len(key)/daemonConfigKeySuggestionRatio, daemonConfigKeySuggestionMin) + 1
+
+	for property := range s.Properties {
+		distance := editDistance(strings.ToLower(key), property)
+		if distance < bestDistance || (distance == bestDistance && property < suggestion) {
+			suggestion = property
+			bestDistance = distance
+		}
+	}
+
+	return suggestion
+}
+
+// jsonSchemaType returns the json schema type name of the given json decoded value.
+func jsonSchemaType(value any) string {
+	switch typed := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if strings.ContainsAny(typed.String(), ".eE") {
+			return "number"
+		}
+
+		return "integer"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// editDistance returns the levenshtein distance of the given strings.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			substitution := previous[j-1]
+			if a[i-1] != b[j-1] {
+				substitution++
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, substitution)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
+
+// validateDaemonConfig validates the given docker daemon config against the embedded docker
+// daemon.json schema, returning an error listing every unknown or mistyped key -- docker silently
+// ignores a lot of those (i.e. "insecure-registry" rather than "insecure-registries").
+func validateDaemonConfig(content []byte) error {
+	schema, err := loadDaemonConfigSchema()
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+
+	var config any
+
+	err = decoder.Decode(&config)
+	if err != nil {
+		return fmt.Errorf(
+			"%w: failed parsing docker daemon config, err: %w",
+			claberneteserrors.ErrLaunch,
+			err,
+		)
+	}
+
+	issues := schema.validate("", config)
+	if len(issues) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"%w: invalid docker daemon config:\n  - %s",
+		claberneteserrors.ErrLaunch,
+		strings.Join(issues, "\n  - "),
+	)
+}
+
+// validateDaemonConfigFile validates the docker daemon config at the given path (see
+// validateDaemonConfig), a missing config is valid.
+func validateDaemonConfigFile(path string) error {
+	content, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	err = validateDaemonConfig(content)
+	if err != nil {
+		return fmt.Errorf("%q: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package launcher_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+func TestValidateDaemonConfig(t *testing.T) {
+	cases := []struct {
+		name           string
+		content        string
+		expectedIssues []string
+	}{
+		{
+			name: "valid",
+			content: `{
+				"storage-driver": "overlay2",
+				"insecure-registries": ["registry:5000"],
+				"features": {"containerd-snapshotter": true},
+				"mtu": 1450,
+				"log-opts": {"max-size": "10m"}
+			}`,
+		},
+		{
+			name:    "typo-suggested",
+			content: `{"insecure-registry": ["registry:5000"]}`,
+			expectedIssues: []string{
+				`unknown key "insecure-registry", did you mean "insecure-registries"?`,
+			},
+		},
+		{
+			name:           "unknown-not-suggested",
+			content:        `{"something-else": true}`,
+			expectedIssues: []string{`unknown key "something-else"`},
+		},
+		{
+			name:    "mistyped",
+			content: `{"icc": "false", "mtu": 1450.5, "dns": ["1.1.1.1", 8]}`,
+			expectedIssues: []string{
+				`"dns[1]" must be of type string, got integer`,
+				`"icc" must be of type boolean, got string`,
+				`"mtu" must be of type integer, got number`,
+			},
+		},
+		{
+			name:           "mistyped-feature",
+			content:        `{"features": {"buildkit": "yes"}}`,
+			expectedIssues: []string{`"features.buildkit" must be of type boolean, got string`},
+		},
+		{
+			name:           "not-an-object",
+			content:        `["storage-driver"]`,
+			expectedIssues: []string{"config must be of type object, got array"},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				err := claberneteslauncher.ValidateDaemonConfig([]byte(testCase.content))
+
+				if len(testCase.expectedIssues) == 0 {
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					return
+				}
+
+				if !errors.Is(err, claberneteserrors.ErrLaunch) {
+					t.Fatalf("expected launch error, got: %v", err)
+				}
+
+				_, issues, _ := strings.Cut(err.Error(), "\n  - ")
+
+				clabernetestesthelper.MarshaledEqual(
+					t,
+					strings.Split(issues, "\n  - "),
+					testCase.expectedIssues,
+				)
+			})
+	}
+}
+
+func TestValidateDaemonConfigRendered(t *testing.T) {
+	goldenFiles, err := filepath.Glob(
+		filepath.Join("test-fixtures", "golden", renderDaemonConfigTestName, "*.json"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(goldenFiles) == 0 {
+		t.Fatal("expected rendered daemon config golden files")
+	}
+
+	for _, goldenFile := range goldenFiles {
+		content, err := os.ReadFile(goldenFile) //nolint:gosec
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = claberneteslauncher.ValidateDaemonConfig(content)
+		if err != nil {
+			t.Fatalf("rendered daemon config %q failed validation, err: %s", goldenFile, err)
+		}
+	}
+}
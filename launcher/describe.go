@@ -0,0 +1,106 @@
+package launcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+)
+
+const describeTimeout = 30 * time.Second
+
+// DescribeNode writes the full docker inspect output of the container of the given (exactly
+// matched) node to stdout -- pretty printed by default, unindented if asJSON is true, or rendered
+// with the given docker inspect go template format if one is set.
+func DescribeNode(nodeName string, asJSON bool, format string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), describeTimeout)
+	defer cancel()
+
+	return describeNode(ctx, os.Stdout, nodeName, asJSON, format)
+}
+
+func describeNode(
+	ctx context.Context,
+	w io.Writer,
+	nodeName string,
+	asJSON bool,
+	format string,
+) error {
+	index, err := newNodeContainerIndex(ctx)
+	if err != nil {
+		return err
+	}
+
+	containerID, err := index.resolve(ctx, nodeName)
+	if err != nil {
+		return err
+	}
+
+	if containerID == "" {
+		return fmt.Errorf(
+			"%w: no container found for node %q",
+			claberneteserrors.ErrContainerNotFound,
+			nodeName,
+		)
+	}
+
+	args := []string{"inspect"}
+
+	if format != "" {
+		args = append(args, "--format", format)
+	}
+
+	inspectCmd := exec.CommandContext(ctx, "docker", append(args, containerID)...)
+
+	output, err := runner.Output(inspectCmd)
+	if err != nil {
+		return classifyDockerError(err)
+	}
+
+	if format != "" {
+		_, err = w.Write(output)
+
+		return err
+	}
+
+	// docker inspect always returns an array, but we only ever inspect the one container
+	var results []json.RawMessage
+
+	err = json.Unmarshal(output, &results)
+	if err != nil {
+		return err
+	}
+
+	if len(results) != 1 {
+		return fmt.Errorf(
+			"%w: expected a single inspect result for node %q, got %d",
+			claberneteserrors.ErrLaunch,
+			nodeName,
+			len(results),
+		)
+	}
+
+	out := &bytes.Buffer{}
+
+	if asJSON {
+		err = json.Compact(out, results[0])
+	} else {
+		err = json.Indent(out, results[0], "", "  ")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	out.WriteByte('\n')
+
+	_, err = w.Write(out.Bytes())
+
+	return err
+}
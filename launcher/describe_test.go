@@ -0,0 +1,96 @@
+package launcher_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+const describeNodeTestName = "describe-node"
+
+func TestDescribeNode(t *testing.T) {
+	cases := []struct {
+		name   string
+		asJSON bool
+		format string
+	}{
+		{
+			name: "pretty",
+		},
+		{
+			name:   "json",
+			asJSON: true,
+		},
+		{
+			name:   "format",
+			format: "{{.State.Status}}",
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				fakeRunner := newFakeCommandRunner()
+
+				fakeRunner.outputs[`docker ps --all --filter label=containerlab`+
+					` --format {{.Label "clab-node-name"}} {{.ID}}`] = []byte(
+					"srl2 deadbeef\nsrl1 4f66ad9a0b2e\n",
+				)
+
+				fakeRunner.outputs["docker inspect 4f66ad9a0b2e"] = clabernetestesthelper.
+					ReadTestFixtureFile(t, "docker-inspect/partial.json")
+				fakeRunner.outputs["docker inspect --format {{.State.Status}} 4f66ad9a0b2e"] = []byte(
+					"running\n",
+				)
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				actual, err := claberneteslauncher.DescribeNodeOutput(
+					context.Background(),
+					"srl1",
+					testCase.asJSON,
+					testCase.format,
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				goldenFileName := fmt.Sprintf(
+					"golden/%s/%s.txt",
+					describeNodeTestName,
+					testCase.name,
+				)
+
+				if *clabernetestesthelper.Update {
+					clabernetestesthelper.WriteTestFixtureFile(t, goldenFileName, actual)
+				}
+
+				expected := clabernetestesthelper.ReadTestFixtureFile(t, goldenFileName)
+
+				if string(actual) != string(expected) {
+					clabernetestesthelper.FailOutput(t, actual, expected)
+				}
+			})
+	}
+}
+
+func TestDescribeNodeNotFound(t *testing.T) {
+	fakeRunner := newFakeCommandRunner()
+
+	restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+	defer restore()
+
+	_, err := claberneteslauncher.DescribeNodeOutput(context.Background(), "srl", false, "")
+	if !errors.Is(err, claberneteserrors.ErrContainerNotFound) {
+		clabernetestesthelper.FailOutput(t, err, claberneteserrors.ErrContainerNotFound)
+	}
+}
@@ -0,0 +1,275 @@
+package launcher
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+)
+
+const (
+	diagnosticsTimeout        = 2 * time.Minute
+	diagnosticsBundleName     = "diagnostics.tar.gz"
+	diagnosticsLogTailLines   = "500"
+	diagnosticsErrorsFileName = "errors.txt"
+)
+
+// CollectDiagnostics gathers the docker daemon config, docker info/version, the inspect output and
+// recent logs of all containers, and the launcher work directory logs into a gzipped tarball at
+// outputPath -- if outputPath is empty the bundle is written to the launcher work directory.
+// Collection is best effort, anything that fails to collect is recorded in the bundle's errors.txt.
+func CollectDiagnostics(outputPath string) error {
+	workDir := launcherWorkDir()
+
+	if outputPath == "" {
+		outputPath = filepath.Join(workDir, diagnosticsBundleName)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), diagnosticsTimeout)
+	defer cancel()
+
+	tempDir, err := os.MkdirTemp("", "clabernetes-diagnostics")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = os.RemoveAll(tempDir)
+	}()
+
+	collectErrs := collectDiagnostics(ctx, workDir, tempDir)
+
+	if len(collectErrs) > 0 {
+		err = os.WriteFile(
+			filepath.Join(tempDir, diagnosticsErrorsFileName),
+			[]byte(strings.Join(collectErrs, "\n")+"\n"),
+			clabernetesconstants.PermissionsEveryoneReadWrite,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return writeTarGz(tempDir, outputPath)
+}
+
+func collectDiagnostics(ctx context.Context, workDir, tempDir string) []string {
+	var collectErrs []string
+
+	record := func(name string, err error) {
+		if err != nil {
+			collectErrs = append(collectErrs, fmt.Sprintf("%s: %s", name, err))
+		}
+	}
+
+	daemonConfigContent, err := os.ReadFile(dockerDaemonConfigPath())
+	if err == nil {
+		daemonConfigContent, err = redactDaemonConfig(daemonConfigContent)
+	}
+
+	if err == nil {
+		err = os.WriteFile(
+			filepath.Join(tempDir, "daemon.json"),
+			daemonConfigContent,
+			clabernetesconstants.PermissionsEveryoneReadWrite,
+		)
+	}
+
+	record("daemon.json", err)
+
+	record("docker-info.json", writeCommandOutput(
+		ctx, filepath.Join(tempDir, "docker-info.json"), "info", "--format", "{{json .}}",
+	))
+	record("docker-version.json", writeCommandOutput(
+		ctx, filepath.Join(tempDir, "docker-version.json"), "version", "--format", "{{json .}}",
+	))
+
+	containerIDs, err := getContainerIDs(ctx, true)
+	record("container ids", err)
+
+	for _, containerID := range containerIDs {
+		record(containerID, writeCommandOutput(
+			ctx,
+			filepath.Join(tempDir, "containers", containerID+"-inspect.json"),
+			"inspect",
+			containerID,
+		))
+		record(containerID, writeCommandOutput(
+			ctx,
+			filepath.Join(tempDir, "containers", containerID+".log"),
+			"logs",
+			"--tail",
+			diagnosticsLogTailLines,
+			containerID,
+		))
+	}
+
+	for _, logFileName := range []string{
+		containerlabLogFileName,
+		nodeLogFileName,
+		startupTimingsFileName,
+	} {
+		record(logFileName, copyFileIfExists(
+			filepath.Join(workDir, logFileName),
+			filepath.Join(tempDir, "logs", logFileName),
+		))
+	}
+
+	return collectErrs
+}
+
+// redactDaemonConfig replaces the values of any secret looking keys in the given daemon config
+// json with a placeholder.
+func redactDaemonConfig(content []byte) ([]byte, error) {
+	var config map[string]any
+
+	err := json.Unmarshal(content, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	redactDiagnosticsValue(config)
+
+	return json.MarshalIndent(config, "", "    ")
+}
+
+func redactDiagnosticsValue(v any) {
+	switch typedV := v.(type) {
+	case map[string]any:
+		for key, value := range typedV {
+			if secretLooking(key) {
+				typedV[key] = redactedValue
+
+				continue
+			}
+
+			redactDiagnosticsValue(value)
+		}
+	case []any:
+		for _, value := range typedV {
+			redactDiagnosticsValue(value)
+		}
+	}
+}
+
+// writeCommandOutput runs docker with the given args and writes the combined output to path.
+func writeCommandOutput(ctx context.Context, path string, args ...string) error {
+	err := os.MkdirAll(
+		filepath.Dir(path),
+		clabernetesconstants.PermissionsEveryoneReadWriteOwnerExecute,
+	)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path) //nolint:gosec
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	cmd := exec.CommandContext(ctx, "docker", args...)
+
+	cmd.Stdout = f
+	cmd.Stderr = f
+
+	return classifyDockerError(runner.Run(cmd))
+}
+
+func copyFileIfExists(src, dst string) error {
+	content, err := os.ReadFile(src) //nolint:gosec
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	err = os.MkdirAll(
+		filepath.Dir(dst),
+		clabernetesconstants.PermissionsEveryoneReadWriteOwnerExecute,
+	)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(dst, content, clabernetesconstants.PermissionsEveryoneReadWrite)
+}
+
+// writeTarGz writes all the files in srcDir to a gzipped tarball at outputPath.
+func writeTarGz(srcDir, outputPath string) error {
+	f, err := os.Create(outputPath) //nolint:gosec
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	gzipWriter := gzip.NewWriter(f)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	err = filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+
+		header.Name, err = filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+
+		err = tarWriter.WriteHeader(header)
+		if err != nil {
+			return err
+		}
+
+		src, err := os.Open(path) //nolint:gosec
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			_ = src.Close()
+		}()
+
+		_, err = io.Copy(tarWriter, src)
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	err = tarWriter.Close()
+	if err != nil {
+		return err
+	}
+
+	return gzipWriter.Close()
+}
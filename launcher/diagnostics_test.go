@@ -0,0 +1,57 @@
+package launcher_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+func TestRedactDaemonConfig(t *testing.T) {
+	cases := []struct {
+		name     string
+		in       string
+		expected map[string]any
+	}{
+		{
+			name: "nothing-to-redact",
+			in:   `{"storage-driver":"overlay2","insecure-registries":["1.2.3.4"]}`,
+			expected: map[string]any{
+				"storage-driver":      "overlay2",
+				"insecure-registries": []any{"1.2.3.4"},
+			},
+		},
+		{
+			name: "nested-auth",
+			in:   `{"registry-mirrors":[{"url":"https://mirror","authToken":"hunter2"}]}`,
+			expected: map[string]any{
+				"registry-mirrors": []any{
+					map[string]any{"url": "https://mirror", "authToken": "REDACTED"},
+				},
+			},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				actual, err := claberneteslauncher.RedactDaemonConfig([]byte(testCase.in))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				var actualConfig map[string]any
+
+				err = json.Unmarshal(actual, &actualConfig)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				clabernetestesthelper.MarshaledEqual(t, actualConfig, testCase.expected)
+			})
+	}
+}
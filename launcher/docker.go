@@ -3,144 +3,1260 @@ package launcher
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"text/template"
 	"time"
+	"unicode"
 
 	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
 	claberneteserrors "github.com/srl-labs/clabernetes/errors"
 	claberneteslogging "github.com/srl-labs/clabernetes/logging"
+	clabernetesutil "github.com/srl-labs/clabernetes/util"
 )
 
 const (
-	dockerDaemonConfig   = "/etc/docker/daemon.json"
-	vfsStorageDriver     = "vfs"
-	overlayStorageDriver = "overlay2"
+	defaultDockerDaemonConfig  = "/etc/docker/daemon.json"
+	dockerDaemonConfigTemplate = "docker-daemon.json.template"
+	dockerHostEnv              = "DOCKER_HOST"
+	defaultDockerHost          = "unix:///var/run/docker.sock"
+	dockerTLSHost              = "tcp://0.0.0.0:2376"
+	vfsStorageDriver           = "vfs"
+	overlayStorageDriver       = "overlay2"
+	noneBridge                 = "none"
+	maxInterfaceNameLength     = 15
+	defaultDockerRuntime       = "runc"
+
+	containerListInitialBackoff = 250 * time.Millisecond
+	containerListMaxBackoff     = 5 * time.Second
+
+	daemonConfigModeWrite  = "write"
+	daemonConfigModeAssert = "assert"
+
+	ipTablesBackendLegacy = "legacy"
+	ipTablesBackendNFT    = "nft"
+	ipTablesBackendAuto   = "auto"
+
+	restartPolicyNo            = "no"
+	restartPolicyAlways        = "always"
+	restartPolicyUnlessStopped = "unless-stopped"
+	restartPolicyOnFailure     = "on-failure"
+
+	dockerDaemonLogFile          = "/var/log/docker.log"
+	dockerDaemonLogFileName      = "docker-daemon.log"
+	dockerDaemonLogTailLineCount = 50
+
+	// maxConcurrentContainerAddrLookups is the number of container addresses getContainerAddrs
+	// looks up at once.
+	maxConcurrentContainerAddrLookups = 8
+
+	// minimumDockerFeaturesVersion is the minimum major docker version we will emit the features
+	// object for -- the containerd snapshotter (the main reason to set features at all) requires
+	// at least docker 24.
+	minimumDockerFeaturesVersion = 24
+)
+
+// dockerSizePattern matches the size strings docker accepts, a number with an optional (binary)
+// unit such as "256m", "1g", or "512MiB".
+var dockerSizePattern = regexp.MustCompile( //nolint:gochecknoglobals
+	`^[0-9]+(\.[0-9]+)?\s?([kKmMgGtTpP]([iI]?[bB])?|[bB])?$`,
 )
 
+// dockerStartRetryInterval is the time to wait between docker start attempts in startDocker.
+var dockerStartRetryInterval = time.Second //nolint:gochecknoglobals
+
+// dockerDaemonConfigPath returns the path of the docker daemon config, the
+// LauncherDockerDaemonConfigPath if set (i.e. for rootless docker), otherwise the default
+// "/etc/docker/daemon.json".
+func dockerDaemonConfigPath() string {
+	return clabernetesutil.GetEnvStrOrDefault(
+		clabernetesconstants.LauncherDockerDaemonConfigPath,
+		defaultDockerDaemonConfig,
+	)
+}
+
 func daemonConfigExists() bool {
-	_, err := os.Stat(dockerDaemonConfig)
+	_, err := os.Stat(dockerDaemonConfigPath())
 
 	return err == nil
 }
 
-func handleInsecureRegistries() error {
-	insecureRegistries := os.Getenv(clabernetesconstants.LauncherInsecureRegistries)
+// daemonConfig holds the values used to render the docker daemon config template.
+type daemonConfig struct {
+	StorageDriver      string
+	InsecureRegistries string
+	Bip                string
+	Features           string
+	CgroupParent       string
+	Bridge             string
+	TLSCACert          string
+	TLSCert            string
+	TLSKey             string
+	LocalHost          string
+	TLSHost            string
+	StorageOpts        string
+	DefaultRuntime     string
+	Runtimes           string
+	ICC                string
+	IPForward          string
+	IPMasq             string
+	DefaultShmSize     string
+}
+
+// configured returns true if any user provided settings are set in the daemon config -- if not,
+// there is no reason to write out a daemon config at all.
+func (d *daemonConfig) configured() bool {
+	return d.InsecureRegistries != "" ||
+		d.Bip != "" ||
+		d.Features != "" ||
+		d.CgroupParent != "" ||
+		d.Bridge != "" ||
+		d.TLSHost != "" ||
+		d.StorageOpts != "" ||
+		d.DefaultRuntime != "" ||
+		d.Runtimes != "" ||
+		d.ICC != "" ||
+		d.IPForward != "" ||
+		d.IPMasq != "" ||
+		d.DefaultShmSize != ""
+}
+
+// parseInsecureRegistries splits the comma separated insecure registries string into its
+// registries, stripping any http(s) scheme since docker only accepts host[:port] entries.
+func parseInsecureRegistries(
+	logger claberneteslogging.Instance,
+	insecureRegistries string,
+) []string {
+	var registries []string
+
+	for _, registry := range strings.Split(insecureRegistries, ",") {
+		registry = strings.TrimSpace(registry)
+		if registry == "" {
+			continue
+		}
+
+		for _, scheme := range []string{"http://", "https://"} {
+			if !strings.HasPrefix(strings.ToLower(registry), scheme) {
+				continue
+			}
+
+			strippedRegistry := strings.TrimSuffix(registry[len(scheme):], "/")
+
+			logger.Warnf(
+				"insecure registry %q includes a scheme, docker does not support this, using %q",
+				registry,
+				strippedRegistry,
+			)
+
+			registry = strippedRegistry
+		}
+
+		registries = append(registries, registry)
+	}
+
+	return registries
+}
+
+// readInsecureRegistriesFile reads the insecure registries file at the given path -- one registry
+// per line, blank lines and anything after a "#" are ignored.
+func readInsecureRegistriesFile(path string) ([]string, error) {
+	content, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return nil, fmt.Errorf(
+			"%w: failed reading insecure registries file %q, err: %w",
+			claberneteserrors.ErrLaunch,
+			path,
+			err,
+		)
+	}
+
+	var registries []string
+
+	for _, line := range strings.Split(string(content), "\n") {
+		line, _, _ = strings.Cut(line, "#")
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		registries = append(registries, line)
+	}
+
+	return registries, nil
+}
+
+// loadInsecureRegistries returns the insecure registries from LauncherInsecureRegistries merged
+// with those in the LauncherInsecureRegistriesFile (if set), deduplicated (case insensitively) in
+// the order they were first listed.
+func loadInsecureRegistries(logger claberneteslogging.Instance) ([]string, error) {
+	insecureRegistries := os.Getenv(clabernetesconstants.LauncherInsecureRegistries)
+
+	path := os.Getenv(clabernetesconstants.LauncherInsecureRegistriesFile)
+	if path != "" {
+		fileRegistries, err := readInsecureRegistriesFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		insecureRegistries += "," + strings.Join(fileRegistries, ",")
+	}
+
+	var registries []string
+
+	seen := map[string]bool{}
+
+	for _, registry := range parseInsecureRegistries(logger, insecureRegistries) {
+		if seen[strings.ToLower(registry)] {
+			logger.Debugf("insecure registry %q listed more than once, skipping", registry)
+
+			continue
+		}
+
+		seen[strings.ToLower(registry)] = true
+
+		registries = append(registries, registry)
+	}
+
+	return registries, nil
+}
+
+// registryIsInsecure returns true if docker treats the given registry host[:port] as insecure --
+// that is, if it is listed in the given insecure registries (directly or, for ip hosts, via a
+// listed CIDR) or is a loopback host, which docker always treats as insecure.
+func registryIsInsecure(insecureRegistries []string, registry string) bool {
+	host := registry
+
+	splitHost, _, err := net.SplitHostPort(registry)
+	if err == nil {
+		host = splitHost
+	}
+
+	ip := net.ParseIP(host)
+
+	if strings.EqualFold(host, "localhost") || (ip != nil && ip.IsLoopback()) {
+		return true
+	}
+
+	for _, insecureRegistry := range insecureRegistries {
+		if strings.EqualFold(insecureRegistry, registry) {
+			return true
+		}
+
+		_, insecureNet, err := net.ParseCIDR(insecureRegistry)
+		if err == nil && ip != nil && insecureNet.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// registryConfigIssues returns the inconsistencies between the given insecure registries and image
+// pull mirror -- a mirror that includes a scheme (which docker does not accept in image
+// references) or that is plain http but not an insecure registry.
+// Docker does not complain about any of these itself, they only surface as opaque pull failures.
+func registryConfigIssues(insecureRegistries []string, mirror string) []string {
+	var issues []string
+
+	for _, scheme := range []string{"http://", "https://"} {
+		if !strings.HasPrefix(strings.ToLower(mirror), scheme) {
+			continue
+		}
+
+		mirrorHost, _, _ := strings.Cut(mirror[len(scheme):], "/")
+
+		issues = append(
+			issues,
+			fmt.Sprintf(
+				"image pull mirror %q includes a scheme, it must be host[:port] such as %q",
+				mirror,
+				mirrorHost,
+			),
+		)
+
+		if scheme == "http://" && !registryIsInsecure(insecureRegistries, mirrorHost) {
+			issues = append(
+				issues,
+				fmt.Sprintf(
+					"image pull mirror %q is plain http but %q is not an insecure registry",
+					mirror,
+					mirrorHost,
+				),
+			)
+		}
+	}
+
+	return issues
+}
+
+// checkRegistryConfig logs a warning for each of the registryConfigIssues of the given insecure
+// registries and image pull mirror, or, if LauncherRegistryConfigStrict is set, fails on them.
+func checkRegistryConfig(
+	logger claberneteslogging.Instance,
+	insecureRegistries []string,
+	mirror string,
+) error {
+	issues := registryConfigIssues(insecureRegistries, mirror)
+	if len(issues) == 0 {
+		return nil
+	}
+
+	if strings.EqualFold(
+		os.Getenv(clabernetesconstants.LauncherRegistryConfigStrict),
+		clabernetesconstants.True,
+	) {
+		return fmt.Errorf(
+			"%w: inconsistent registry config: %s",
+			claberneteserrors.ErrLaunch,
+			strings.Join(issues, "; "),
+		)
+	}
+
+	for _, issue := range issues {
+		logger.Warnf("inconsistent registry config, %s", issue)
+	}
+
+	return nil
+}
+
+// storageDriverOptPrefixes holds the storage opt key prefixes that the storage drivers the launcher
+// may select actually accept.
+var storageDriverOptPrefixes = map[string][]string{ //nolint:gochecknoglobals
+	overlayStorageDriver: {"overlay2."},
+	vfsStorageDriver:     {"size"},
+}
+
+// parseStorageOpts splits the comma separated storage opts string into its key=value opts, skipping
+// (with a warning) any opts that are malformed or incompatible with the given storage driver.
+func parseStorageOpts(
+	logger claberneteslogging.Instance,
+	storageDriver,
+	storageOpts string,
+) []string {
+	var opts []string
+
+	for _, opt := range strings.Split(storageOpts, ",") {
+		opt = strings.TrimSpace(opt)
+		if opt == "" {
+			continue
+		}
+
+		key, _, ok := strings.Cut(opt, "=")
+		if !ok || key == "" {
+			logger.Warnf("storage opt %q is not in the form key=value, skipping", opt)
+
+			continue
+		}
+
+		var compatible bool
+
+		for _, prefix := range storageDriverOptPrefixes[storageDriver] {
+			if strings.HasPrefix(strings.ToLower(key), prefix) {
+				compatible = true
+
+				break
+			}
+		}
+
+		if !compatible {
+			logger.Warnf(
+				"storage opt %q is not supported by storage driver %q, skipping",
+				opt,
+				storageDriver,
+			)
+
+			continue
+		}
+
+		opts = append(opts, opt)
+	}
+
+	return opts
+}
+
+// parseDaemonFeatures parses a comma separated list of feature=bool pairs into a map suitable for
+// the docker daemon config features object.
+func parseDaemonFeatures(features string) (map[string]bool, error) {
+	parsedFeatures := map[string]bool{}
+
+	for _, feature := range strings.Split(features, ",") {
+		feature = strings.TrimSpace(feature)
+		if feature == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(feature, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf(
+				"%w: invalid docker feature %q, must be in the form name=true|false",
+				claberneteserrors.ErrLaunch,
+				feature,
+			)
+		}
+
+		switch value {
+		case clabernetesconstants.True:
+			parsedFeatures[name] = true
+		case clabernetesconstants.False:
+			parsedFeatures[name] = false
+		default:
+			return nil, fmt.Errorf(
+				"%w: invalid value %q for docker feature %q, must be true or false",
+				claberneteserrors.ErrLaunch,
+				value,
+				name,
+			)
+		}
+	}
+
+	return parsedFeatures, nil
+}
+
+// getDockerMajorVersion returns the major version of the (not necessarily running) docker daemon
+// by parsing the output of "dockerd --version", i.e. "Docker version 24.0.7, build 311b9ff".
+func getDockerMajorVersion(ctx context.Context) (int, error) {
+	versionCmd := exec.CommandContext(ctx, "dockerd", "--version")
+
+	output, err := runner.Output(versionCmd)
+	if err != nil {
+		return 0, err
+	}
+
+	_, version, ok := strings.Cut(strings.TrimSpace(string(output)), "version ")
+	if !ok {
+		return 0, fmt.Errorf(
+			"%w: unable to parse docker version from output %q",
+			claberneteserrors.ErrLaunch,
+			output,
+		)
+	}
+
+	majorVersion, _, _ := strings.Cut(version, ".")
+
+	return strconv.Atoi(majorVersion)
+}
+
+func buildDaemonConfig(
+	ctx context.Context,
+	logger claberneteslogging.Instance,
+) (*daemonConfig, error) {
+	config := &daemonConfig{
+		StorageDriver: vfsStorageDriver,
+	}
+
+	// if the pod is privileged we can run w/ overlayfs instead of vfs which should
+	// be much more efficient size-wise if not also perofrmance-wise; this *does* assume
+	// the hosts kernel supports overlayfs but that *should* be true almost everywhere at
+	// this point in time... i hope :P
+	if !strings.EqualFold(
+		os.Getenv(clabernetesconstants.LauncherPrivilegedEnv),
+		clabernetesconstants.True,
+	) {
+		config.StorageDriver = overlayStorageDriver
+	}
+
+	err := setDaemonConfigRegistries(logger, config)
+	if err != nil {
+		return nil, err
+	}
+
+	err = setDaemonConfigBridge(config)
+	if err != nil {
+		return nil, err
+	}
+
+	config.CgroupParent = os.Getenv(clabernetesconstants.LauncherDockerCgroupParent)
+
+	err = setDaemonConfigNetworking(config)
+	if err != nil {
+		return nil, err
+	}
+
+	err = setDaemonConfigShmSize(config)
+	if err != nil {
+		return nil, err
+	}
+
+	err = setDaemonConfigStorageOpts(logger, config)
+	if err != nil {
+		return nil, err
+	}
+
+	err = setDaemonConfigTLS(config)
+	if err != nil {
+		return nil, err
+	}
+
+	err = setDaemonConfigRuntimes(config)
+	if err != nil {
+		return nil, err
+	}
+
+	err = setDaemonConfigFeatures(ctx, logger, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// setDaemonConfigRegistries sets the insecure registries of the daemon config, ensuring they do
+// not conflict with the image pull mirror.
+func setDaemonConfigRegistries(logger claberneteslogging.Instance, config *daemonConfig) error {
+	insecureRegistries, err := loadInsecureRegistries(logger)
+	if err != nil {
+		return err
+	}
+
+	err = checkRegistryConfig(
+		logger,
+		insecureRegistries,
+		os.Getenv(clabernetesconstants.LauncherImagePullMirror),
+	)
+	if err != nil {
+		return err
+	}
+
+	if len(insecureRegistries) == 0 {
+		return nil
+	}
+
+	quotedRegistries := make([]string, len(insecureRegistries))
+
+	for idx, elem := range insecureRegistries {
+		quotedRegistries[idx] = jsonQuote(elem)
+	}
+
+	config.InsecureRegistries = strings.Join(quotedRegistries, ",")
+
+	return nil
+}
+
+// setDaemonConfigBridge sets the default bridge settings of the daemon config -- the bip, or
+// disabling the default bridge, or a custom bridge name -- rejecting conflicting combinations.
+func setDaemonConfigBridge(config *daemonConfig) error {
+	bip := os.Getenv(clabernetesconstants.LauncherDockerBIP)
+
+	if bip != "" {
+		_, _, err := net.ParseCIDR(bip)
+		if err != nil {
+			return fmt.Errorf(
+				"%w: invalid docker bip %q, must be in CIDR notation, err: %w",
+				claberneteserrors.ErrLaunch,
+				bip,
+				err,
+			)
+		}
+
+		config.Bip = bip
+	}
+
+	if strings.EqualFold(
+		os.Getenv(clabernetesconstants.LauncherDockerDisableBridge),
+		clabernetesconstants.True,
+	) {
+		if config.Bip != "" {
+			return fmt.Errorf(
+				"%w: docker bip cannot be set when the default bridge is disabled",
+				claberneteserrors.ErrLaunch,
+			)
+		}
+
+		config.Bridge = noneBridge
+	}
+
+	bridgeName := os.Getenv(clabernetesconstants.LauncherDockerBridgeName)
+	if bridgeName == "" {
+		return nil
+	}
+
+	switch {
+	case config.Bridge != "":
+		return fmt.Errorf(
+			"%w: docker bridge name cannot be set when the default bridge is disabled",
+			claberneteserrors.ErrLaunch,
+		)
+	case config.Bip != "":
+		return fmt.Errorf(
+			"%w: docker bip cannot be set along with a docker bridge name",
+			claberneteserrors.ErrLaunch,
+		)
+	}
+
+	err := validateInterfaceName(bridgeName)
+	if err != nil {
+		return err
+	}
+
+	config.Bridge = bridgeName
+
+	return nil
+}
+
+// setDaemonConfigNetworking sets the icc, ip forward, and ip masquerade toggles of the daemon
+// config, leaving each at the docker default if its env var is unset.
+func setDaemonConfigNetworking(config *daemonConfig) error {
+	var err error
+
+	config.ICC, err = parseDaemonConfigBool(clabernetesconstants.LauncherDockerICC)
+	if err != nil {
+		return err
+	}
+
+	config.IPForward, err = parseDaemonConfigBool(clabernetesconstants.LauncherDockerIPForward)
+	if err != nil {
+		return err
+	}
+
+	config.IPMasq, err = parseDaemonConfigBool(clabernetesconstants.LauncherDockerIPMasq)
+
+	return err
+}
+
+// setDaemonConfigShmSize sets the default shm size of the daemon config if requested.
+func setDaemonConfigShmSize(config *daemonConfig) error {
+	shmSize := os.Getenv(clabernetesconstants.LauncherDockerShmSize)
+	if shmSize == "" {
+		return nil
+	}
+
+	if !dockerSizePattern.MatchString(shmSize) {
+		return fmt.Errorf(
+			"%w: invalid docker shm size %q, must be a size such as \"256m\" or \"1g\"",
+			claberneteserrors.ErrLaunch,
+			shmSize,
+		)
+	}
+
+	config.DefaultShmSize = shmSize
+
+	return nil
+}
+
+// setDaemonConfigStorageOpts sets the storage driver options of the daemon config, dropping any
+// that do not apply to the selected storage driver.
+func setDaemonConfigStorageOpts(logger claberneteslogging.Instance, config *daemonConfig) error {
+	storageOpts := parseStorageOpts(
+		logger,
+		config.StorageDriver,
+		os.Getenv(clabernetesconstants.LauncherDockerStorageOpts),
+	)
+
+	if len(storageOpts) == 0 {
+		return nil
+	}
+
+	storageOptsJSON, err := json.Marshal(storageOpts)
+	if err != nil {
+		return err
+	}
+
+	config.StorageOpts = string(storageOptsJSON)
+
+	return nil
+}
+
+// setDaemonConfigFeatures sets the features of the daemon config if requested, skipping them
+// (with a warning) if the docker version cannot be determined or does not support features.
+func setDaemonConfigFeatures(
+	ctx context.Context,
+	logger claberneteslogging.Instance,
+	config *daemonConfig,
+) error {
+	features := os.Getenv(clabernetesconstants.LauncherDockerFeatures)
+	if features == "" {
+		return nil
+	}
+
+	parsedFeatures, err := parseDaemonFeatures(features)
+	if err != nil {
+		return err
+	}
+
+	majorVersion, err := getDockerMajorVersion(ctx)
+
+	switch {
+	case err != nil:
+		logger.Warnf(
+			"failed determining docker version, skipping docker features, err: %s", err,
+		)
+
+		return nil
+	case majorVersion < minimumDockerFeaturesVersion:
+		logger.Warnf(
+			"docker major version %d does not support features, skipping docker features",
+			majorVersion,
+		)
+
+		return nil
+	}
+
+	featuresJSON, err := json.Marshal(parsedFeatures)
+	if err != nil {
+		return err
+	}
+
+	config.Features = string(featuresJSON)
+
+	return nil
+}
+
+// validateInterfaceName ensures the given name is a legal linux network interface name -- at most
+// 15 characters, not "." or "..", and without slashes, colons, or whitespace. "none" is rejected as
+// well since docker treats it as disabling the bridge.
+func validateInterfaceName(name string) error {
+	if name == "" || name == "." || name == ".." || name == noneBridge ||
+		len(name) > maxInterfaceNameLength ||
+		strings.ContainsAny(name, "/:") ||
+		strings.IndexFunc(name, unicode.IsSpace) != -1 {
+		return fmt.Errorf(
+			"%w: invalid interface name %q, must be at most %d characters and must not"+
+				" contain slashes, colons, or whitespace",
+			claberneteserrors.ErrLaunch,
+			name,
+			maxInterfaceNameLength,
+		)
+	}
+
+	return nil
+}
+
+// parseDaemonConfigBool returns the json boolean for the value of the given (optional) env var, or
+// an empty string if the env var is unset so that the key is omitted and docker's default applies.
+func parseDaemonConfigBool(envName string) (string, error) {
+	value := os.Getenv(envName)
+	if value == "" {
+		return "", nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return "", fmt.Errorf(
+			"%w: invalid value %q for %s, must be a boolean",
+			claberneteserrors.ErrLaunch,
+			value,
+			envName,
+		)
+	}
+
+	return strconv.FormatBool(parsed), nil
+}
+
+// setDaemonConfigTLS sets the tls api settings of the daemon config if (all of) the tls cert env
+// vars are set, ensuring the referenced files actually exist.
+func setDaemonConfigTLS(config *daemonConfig) error {
+	tlsFiles := []string{
+		os.Getenv(clabernetesconstants.LauncherDockerTLSCACert),
+		os.Getenv(clabernetesconstants.LauncherDockerTLSCert),
+		os.Getenv(clabernetesconstants.LauncherDockerTLSKey),
+	}
+
+	var setCount int
+
+	for _, tlsFile := range tlsFiles {
+		if tlsFile == "" {
+			continue
+		}
+
+		setCount++
+
+		_, err := os.Stat(tlsFile)
+		if err != nil {
+			return fmt.Errorf(
+				"%w: docker tls file %q not accessible, err: %w",
+				claberneteserrors.ErrLaunch,
+				tlsFile,
+				err,
+			)
+		}
+	}
+
+	switch setCount {
+	case 0:
+		return nil
+	case len(tlsFiles):
+	default:
+		return fmt.Errorf(
+			"%w: docker tls ca cert, cert, and key must all be set to enable the tls api",
+			claberneteserrors.ErrLaunch,
+		)
+	}
+
+	config.TLSCACert = tlsFiles[0]
+	config.TLSCert = tlsFiles[1]
+	config.TLSKey = tlsFiles[2]
+
+	// always keep the local socket so that the launcher itself can keep talking to docker as it
+	// normally would
+	config.LocalHost = defaultDockerHost
+	config.TLSHost = dockerTLSHost
+
+	return nil
+}
+
+// daemonRuntime is a single entry of the docker daemon config runtimes object.
+type daemonRuntime struct {
+	Path string `json:"path"`
+}
+
+// parseDaemonRuntimes parses a comma separated list of name=path pairs into a map suitable for the
+// docker daemon config runtimes object, ensuring each runtime binary actually exists.
+func parseDaemonRuntimes(runtimes string) (map[string]daemonRuntime, error) {
+	parsedRuntimes := map[string]daemonRuntime{}
+
+	for _, runtime := range strings.Split(runtimes, ",") {
+		runtime = strings.TrimSpace(runtime)
+		if runtime == "" {
+			continue
+		}
+
+		name, path, ok := strings.Cut(runtime, "=")
+		if !ok || name == "" || path == "" {
+			return nil, fmt.Errorf(
+				"%w: invalid docker runtime %q, must be in the form name=path",
+				claberneteserrors.ErrLaunch,
+				runtime,
+			)
+		}
+
+		_, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"%w: docker runtime %q binary %q not found, err: %w",
+				claberneteserrors.ErrLaunch,
+				name,
+				path,
+				err,
+			)
+		}
+
+		parsedRuntimes[name] = daemonRuntime{Path: path}
+	}
+
+	return parsedRuntimes, nil
+}
+
+// setDaemonConfigRuntimes sets the additional runtimes and the default runtime of the daemon config
+// if requested, ensuring the default runtime is either the builtin runc or one of the runtimes.
+func setDaemonConfigRuntimes(config *daemonConfig) error {
+	runtimes, err := parseDaemonRuntimes(os.Getenv(clabernetesconstants.LauncherDockerRuntimes))
+	if err != nil {
+		return err
+	}
+
+	if len(runtimes) > 0 {
+		runtimesJSON, err := json.Marshal(runtimes)
+		if err != nil {
+			return err
+		}
+
+		config.Runtimes = string(runtimesJSON)
+	}
+
+	defaultRuntime := os.Getenv(clabernetesconstants.LauncherDockerDefaultRuntime)
+	if defaultRuntime == "" {
+		return nil
+	}
+
+	_, ok := runtimes[defaultRuntime]
+	if !ok && defaultRuntime != defaultDockerRuntime {
+		return fmt.Errorf(
+			"%w: docker default runtime %q is neither %q nor one of the configured runtimes",
+			claberneteserrors.ErrLaunch,
+			defaultRuntime,
+			defaultDockerRuntime,
+		)
+	}
+
+	config.DefaultRuntime = defaultRuntime
+
+	return nil
+}
+
+// jsonQuote returns s encoded as a json string (quotes included), so that user provided values
+// with quotes, newlines or other special characters can't break (or inject into) the rendered
+// daemon config.
+func jsonQuote(s string) string {
+	// marshaling a string can't fail
+	quoted, _ := json.Marshal(s) //nolint:errchkjson
+
+	return string(quoted)
+}
+
+func renderDaemonConfig(config *daemonConfig) ([]byte, error) {
+	t, err := template.New(dockerDaemonConfigTemplate).
+		Funcs(template.FuncMap{"json": jsonQuote}).
+		ParseFS(Assets, "assets/"+dockerDaemonConfigTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	var rendered bytes.Buffer
+
+	err = t.Execute(&rendered, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return rendered.Bytes(), nil
+}
+
+func handleInsecureRegistries(ctx context.Context, logger claberneteslogging.Instance) error {
+	config, err := buildDaemonConfig(ctx, logger)
+	if err != nil {
+		return err
+	}
 
-	if insecureRegistries == "" {
+	if !config.configured() {
 		return nil
 	}
 
-	splitRegistries := strings.Split(insecureRegistries, ",")
+	rendered, err := renderDaemonConfig(config)
+	if err != nil {
+		return err
+	}
 
-	quotedRegistries := make([]string, len(splitRegistries))
+	mode := clabernetesutil.GetEnvStrOrDefault(
+		clabernetesconstants.LauncherDaemonConfigMode,
+		daemonConfigModeWrite,
+	)
 
-	for idx, elem := range splitRegistries {
-		quotedRegistries[idx] = fmt.Sprintf("%q", elem)
+	switch mode {
+	case daemonConfigModeWrite:
+		return writeDaemonConfig(logger, dockerDaemonConfigPath(), rendered)
+	case daemonConfigModeAssert:
+		return assertDaemonConfig(logger, dockerDaemonConfigPath(), rendered)
+	default:
+		return validateDaemonConfigMode(mode)
 	}
+}
 
-	templateVars := struct {
-		StorageDriver      string
-		InsecureRegistries string
-	}{
-		StorageDriver:      vfsStorageDriver,
-		InsecureRegistries: strings.Join(quotedRegistries, ","),
+// validateDaemonConfigMode ensures the given daemon config mode is one we know how to handle.
+func validateDaemonConfigMode(mode string) error {
+	switch mode {
+	case daemonConfigModeWrite, daemonConfigModeAssert:
+		return nil
+	default:
+		return fmt.Errorf(
+			"%w: invalid daemon config mode %q, must be one of %q or %q",
+			claberneteserrors.ErrLaunch,
+			mode,
+			daemonConfigModeWrite,
+			daemonConfigModeAssert,
+		)
 	}
+}
 
-	// if the pod is privileged we can run w/ overlayfs instead of vfs which should
-	// be much more efficient size-wise if not also perofrmance-wise; this *does* assume
-	// the hosts kernel supports overlayfs but that *should* be true almost everywhere at
-	// this point in time... i hope :P
-	if !strings.EqualFold(
-		os.Getenv(clabernetesconstants.LauncherPrivilegedEnv),
-		clabernetesconstants.True,
-	) {
-		templateVars.StorageDriver = overlayStorageDriver
+// assertDaemonConfig ensures the (externally managed) daemon config at path matches the rendered
+// config without writing it, returning an error with a diff of the two if they don't match. The
+// configs are compared as json so formatting differences don't count as a mismatch.
+func assertDaemonConfig(
+	logger claberneteslogging.Instance,
+	path string,
+	rendered []byte,
+) error {
+	existing, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return fmt.Errorf(
+			"%w: daemon config mode is %q but failed reading %q, err: %w",
+			claberneteserrors.ErrLaunch,
+			daemonConfigModeAssert,
+			path,
+			err,
+		)
 	}
 
-	t, err := template.ParseFS(Assets, "assets/docker-daemon.json.template")
+	var existingConfig, expectedConfig any
+
+	err = json.Unmarshal(rendered, &expectedConfig)
 	if err != nil {
 		return err
 	}
 
-	var rendered bytes.Buffer
+	err = json.Unmarshal(existing, &existingConfig)
+	if err == nil && reflect.DeepEqual(existingConfig, expectedConfig) {
+		logger.Infof("%q matches expected daemon config", path)
 
-	err = t.Execute(&rendered, templateVars)
+		return nil
+	}
+
+	diff, err := clabernetesutil.UnifiedDiff(existing, rendered)
 	if err != nil {
 		return err
 	}
 
-	err = os.WriteFile(
-		dockerDaemonConfig,
-		rendered.Bytes(),
+	return fmt.Errorf(
+		"%w: %q does not match expected daemon config, diff:\n%s",
+		claberneteserrors.ErrLaunch,
+		path,
+		diff,
+	)
+}
+
+// writeDaemonConfig writes the rendered daemon config to path, unless the file already has the
+// exact same content -- this way the file (and its mtime) is left alone on launcher restarts so
+// nothing watching it restarts docker needlessly. The parent directory of path is created if it
+// does not exist.
+func writeDaemonConfig(logger claberneteslogging.Instance, path string, rendered []byte) error {
+	existing, err := os.ReadFile(path) //nolint:gosec
+	if err == nil && bytes.Equal(existing, rendered) {
+		logger.Infof("%q unchanged, skipping write", path)
+
+		return nil
+	}
+
+	err = os.MkdirAll(
+		filepath.Dir(path),
 		clabernetesconstants.PermissionsEveryoneReadWriteOwnerExecute,
 	)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return os.WriteFile(
+		path,
+		rendered,
+		clabernetesconstants.PermissionsEveryoneReadWriteOwnerExecute,
+	)
 }
 
-func enableLegacyIPTables(ctx context.Context, logger io.Writer) error {
-	updateCmd := exec.CommandContext(
-		ctx,
-		"update-alternatives",
-		"--set",
-		"iptables",
-		"/usr/sbin/iptables-legacy",
+// setDaemonConfigStorageDriver sets the storage driver of the docker daemon config at the given
+// path (creating the config if it does not exist), dropping any storage opts since those are
+// specific to the previously configured driver.
+func setDaemonConfigStorageDriver(path, storageDriver string) error {
+	config := map[string]any{}
+
+	existing, err := os.ReadFile(path) //nolint:gosec
+	switch {
+	case err == nil:
+		err = json.Unmarshal(existing, &config)
+		if err != nil {
+			return fmt.Errorf(
+				"%w: failed parsing docker daemon config %q, err: %w",
+				claberneteserrors.ErrLaunch,
+				path,
+				err,
+			)
+		}
+	case !os.IsNotExist(err):
+		return err
+	}
+
+	config["storage-driver"] = storageDriver
+
+	delete(config, "storage-opts")
+
+	rendered, err := json.MarshalIndent(config, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(
+		filepath.Dir(path),
+		clabernetesconstants.PermissionsEveryoneReadWriteOwnerExecute,
+	)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(
+		path,
+		append(rendered, '\n'),
+		clabernetesconstants.PermissionsEveryoneReadWriteOwnerExecute,
 	)
+}
 
-	updateCmd.Stdout = logger
-	updateCmd.Stderr = logger
+// validateIPTablesBackend ensures the given ip tables backend is one we know how to select.
+func validateIPTablesBackend(backend string) error {
+	switch backend {
+	case ipTablesBackendLegacy, ipTablesBackendNFT, ipTablesBackendAuto:
+		return nil
+	default:
+		return fmt.Errorf(
+			"%w: invalid ip tables backend %q, must be one of %q, %q, or %q",
+			claberneteserrors.ErrLaunch,
+			backend,
+			ipTablesBackendLegacy,
+			ipTablesBackendNFT,
+			ipTablesBackendAuto,
+		)
+	}
+}
 
-	err := updateCmd.Run()
+// setIPTablesBackend switches both iptables and ip6tables to the given backend ("legacy" or "nft")
+// via update-alternatives. The "auto" backend leaves the image's default alternatives alone.
+func setIPTablesBackend(ctx context.Context, logger io.Writer, backend string) error {
+	err := validateIPTablesBackend(backend)
 	if err != nil {
 		return err
 	}
 
+	if backend == ipTablesBackendAuto {
+		return nil
+	}
+
+	for _, binary := range []string{"iptables", "ip6tables"} {
+		updateCmd := exec.CommandContext(
+			ctx,
+			"update-alternatives",
+			"--set",
+			binary,
+			fmt.Sprintf("/usr/sbin/%s-%s", binary, backend),
+		)
+
+		updateCmd.Stdout = logger
+		updateCmd.Stderr = logger
+
+		err = runner.Run(updateCmd)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// dockerHostIsExternal returns true if DOCKER_HOST points at a remote (tcp or ssh) docker daemon
+// -- in which case the launcher should not try to configure or start docker itself. Any unix
+// socket, not just the default one, is considered the local daemon.
+func dockerHostIsExternal() bool {
+	dockerHost := os.Getenv(dockerHostEnv)
+
+	return strings.HasPrefix(dockerHost, "tcp://") || strings.HasPrefix(dockerHost, "ssh://")
+}
+
 func startDocker(ctx context.Context, logger io.Writer) error {
+	externalDockerHost := dockerHostIsExternal()
+
 	var attempts int
 
 	for {
+		psStderr := &bytes.Buffer{}
+
 		psCmd := exec.CommandContext(ctx, "docker", "ps")
 
 		psCmd.Stdout = logger
-		psCmd.Stderr = logger
+		psCmd.Stderr = io.MultiWriter(logger, psStderr)
 
-		err := psCmd.Run()
+		err := runner.Run(psCmd)
 		if err == nil {
 			// exit 0, docker seems happy
 			return nil
 		}
 
-		if attempts > maxDockerLaunchAttempts {
+		nonRetryable := nonRetryableDockerError(psStderr.String())
+		if nonRetryable != "" {
+			return fmt.Errorf(
+				"%w: docker failed with non-retryable error %q, not retrying, stderr: %s",
+				claberneteserrors.ErrLaunch,
+				nonRetryable,
+				strings.TrimSpace(psStderr.String()),
+			)
+		}
+
+		// attempts is the number of times we've *already* run the start command, so once that
+		// reaches the max we're done
+		if attempts >= maxDockerLaunchAttempts {
+			if externalDockerHost {
+				return fmt.Errorf(
+					"%w: failed reaching external docker host %q",
+					claberneteserrors.ErrDockerUnavailable,
+					os.Getenv(dockerHostEnv),
+				)
+			}
+
 			return fmt.Errorf("%w: failed starting docker", claberneteserrors.ErrLaunch)
 		}
 
-		startCmd := exec.CommandContext(ctx, "service", "docker", "start")
+		// when pointed at an external docker daemon its not ours to start, so just keep checking
+		// if it is reachable
+		if !externalDockerHost {
+			startCmd := exec.CommandContext(ctx, "service", "docker", "start")
 
-		startCmd.Stdout = logger
-		startCmd.Stderr = logger
+			startCmd.Stdout = logger
+			startCmd.Stderr = logger
 
-		err = startCmd.Run()
-		if err != nil {
-			return err
+			err = runner.Run(startCmd)
+			if err != nil {
+				return err
+			}
 		}
 
-		time.Sleep(time.Second)
+		time.Sleep(dockerStartRetryInterval)
 
 		attempts++
 	}
 }
 
+// getDockerDaemonLogTail returns the last lineCount lines of the docker daemon log -- when docker
+// is started via the service wrapper this is /var/log/docker.log, otherwise we fall back to trying
+// the journal.
+func getDockerDaemonLogTail(ctx context.Context, lineCount int) ([]byte, error) {
+	content, err := os.ReadFile(dockerDaemonLogFile)
+	if err == nil {
+		lines := bytes.Split(bytes.TrimRight(content, "\n"), []byte("\n"))
+
+		if len(lines) > lineCount {
+			lines = lines[len(lines)-lineCount:]
+		}
+
+		return append(bytes.Join(lines, []byte("\n")), '\n'), nil
+	}
+
+	journalCmd := exec.CommandContext(
+		ctx,
+		"journalctl",
+		"--unit",
+		"docker",
+		"--no-pager",
+		"--lines",
+		strconv.Itoa(lineCount),
+	)
+
+	return runner.Output(journalCmd)
+}
+
+// dockerInfo holds the subset of the docker info output we care about for diagnostics.
+type dockerInfo struct {
+	ServerVersion string   `json:"ServerVersion"`
+	StorageDriver string   `json:"Driver"`
+	CgroupDriver  string   `json:"CgroupDriver"`
+	CgroupVersion string   `json:"CgroupVersion"`
+	Warnings      []string `json:"Warnings"`
+	// LiveRestoreEnabled indicates if node containers survive a restart of the daemon.
+	LiveRestoreEnabled bool `json:"LiveRestoreEnabled"`
+}
+
+func getDockerInfo(ctx context.Context) (*dockerInfo, error) {
+	infoCmd := exec.CommandContext(ctx, "docker", "info", "--format", "{{json .}}")
+
+	output, err := runner.Output(infoCmd)
+	if err != nil {
+		return nil, classifyDockerError(err)
+	}
+
+	info := &dockerInfo{}
+
+	err = json.Unmarshal(output, info)
+	if err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
 func getContainerIDs(ctx context.Context, all bool) ([]string, error) {
 	args := []string{"ps"}
 
@@ -152,9 +1268,9 @@ func getContainerIDs(ctx context.Context, all bool) ([]string, error) {
 
 	psCmd := exec.CommandContext(ctx, "docker", args...)
 
-	output, err := psCmd.Output()
+	output, err := runner.Output(psCmd)
 	if err != nil {
-		return nil, err
+		return nil, classifyDockerError(err)
 	}
 
 	containerIDLines := strings.Split(string(output), "\n")
@@ -172,12 +1288,190 @@ func getContainerIDs(ctx context.Context, all bool) ([]string, error) {
 	return containerIDs, nil
 }
 
+// containerSummary is a single container as listed by docker ps.
+type containerSummary struct {
+	ID     string `json:"ID"`
+	Names  string `json:"Names"`
+	Image  string `json:"Image"`
+	State  string `json:"State"`
+	Status string `json:"Status"`
+}
+
+// listContainers lists the (running, or all if all is true) containers along with their names,
+// image, and state in a single docker ps invocation -- prefer this over getContainerIDs when any
+// detail beyond the id is needed so there is no need for a follow-up inspect. If any label filters
+// ("key" or "key=value") are given only containers matching all of them are listed.
+func listContainers(
+	ctx context.Context,
+	all bool,
+	labelFilters ...string,
+) ([]containerSummary, error) {
+	args := []string{"ps"}
+
+	if all {
+		args = append(args, "-a")
+	}
+
+	for _, labelFilter := range labelFilters {
+		args = append(args, "--filter", "label="+labelFilter)
+	}
+
+	args = append(args, "--no-trunc", "--format", "{{json .}}")
+
+	psCmd := exec.CommandContext(ctx, "docker", args...)
+
+	output, err := runner.Output(psCmd)
+	if err != nil {
+		return nil, classifyDockerError(err)
+	}
+
+	return parseContainerSummaries(output)
+}
+
+// parseContainerSummaries parses the json lines output of docker ps.
+func parseContainerSummaries(output []byte) ([]containerSummary, error) {
+	var summaries []containerSummary
+
+	for _, line := range bytes.Split(output, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		var summary containerSummary
+
+		err := json.Unmarshal(line, &summary)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"%w: failed parsing docker ps output line %q, err: %w",
+				claberneteserrors.ErrLaunch,
+				line,
+				err,
+			)
+		}
+
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, nil
+}
+
+// getContainerIDsWithRetry calls getContainerIDs until at least minCount container ids are
+// returned or the timeout passes, backing off between attempts. Right after docker starts the list
+// can briefly be empty (or error) while docker finishes initializing its state. The last listed ids
+// are always returned, even when the min count was never reached.
+func getContainerIDsWithRetry(
+	ctx context.Context,
+	all bool,
+	minCount int,
+	timeout time.Duration,
+) ([]string, error) {
+	deadline := time.Now().Add(timeout)
+
+	backoff := containerListInitialBackoff
+
+	for {
+		containerIDs, err := getContainerIDs(ctx, all)
+		if err == nil && len(containerIDs) >= minCount {
+			return containerIDs, nil
+		}
+
+		if err != nil && nonRetryableDockerError(err.Error()) != "" {
+			return containerIDs, err
+		}
+
+		if time.Now().Add(backoff).After(deadline) {
+			if err != nil {
+				return containerIDs, err
+			}
+
+			return containerIDs, fmt.Errorf(
+				"%w: expected at least %d containers but found %d after %s",
+				claberneteserrors.ErrLaunch,
+				minCount,
+				len(containerIDs),
+				timeout,
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return containerIDs, ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		backoff = min(backoff*2, containerListMaxBackoff) //nolint:mnd
+	}
+}
+
+// validateRestartPolicy ensures the given restart policy is one docker accepts -- "no", "always",
+// "unless-stopped", or "on-failure" with an optional (positive) max retry count.
+func validateRestartPolicy(restartPolicy string) error {
+	name, maxRetries, hasMaxRetries := strings.Cut(restartPolicy, ":")
+
+	switch name {
+	case restartPolicyNo, restartPolicyAlways, restartPolicyUnlessStopped:
+		if !hasMaxRetries {
+			return nil
+		}
+	case restartPolicyOnFailure:
+		if !hasMaxRetries {
+			return nil
+		}
+
+		count, err := strconv.Atoi(maxRetries)
+		if err == nil && count > 0 {
+			return nil
+		}
+	}
+
+	return fmt.Errorf(
+		"%w: invalid restart policy %q, must be one of %q, %q, %q, or %q[:max-retries]",
+		claberneteserrors.ErrLaunch,
+		restartPolicy,
+		restartPolicyNo,
+		restartPolicyAlways,
+		restartPolicyUnlessStopped,
+		restartPolicyOnFailure,
+	)
+}
+
+// setContainersRestartPolicy updates the restart policy of the given (already created) containers.
+func setContainersRestartPolicy(
+	ctx context.Context,
+	containerIDs []string,
+	restartPolicy string,
+) error {
+	err := validateRestartPolicy(restartPolicy)
+	if err != nil {
+		return err
+	}
+
+	updateCmd := exec.CommandContext(
+		ctx,
+		"docker",
+		append([]string{"update", "--restart", restartPolicy}, containerIDs...)...,
+	)
+
+	_, err = runner.Output(updateCmd)
+	if err != nil {
+		return classifyDockerError(err)
+	}
+
+	return nil
+}
+
+// printContainerLogs prints the logs of the given containers, grouped per node (see
+// containerStreams) and with each line prefixed with the name of its log stream so that the logs of
+// a node's sidecars can be told apart from the node's own.
 func printContainerLogs(
 	ctx context.Context,
 	logger claberneteslogging.Instance,
 	containerIDs []string,
 ) {
-	for _, containerID := range containerIDs {
+	for _, stream := range containerStreams(ctx, containerIDs) {
+		containerID := stream.ID
+
 		args := []string{
 			"logs",
 			containerID,
@@ -185,72 +1479,88 @@ func printContainerLogs(
 
 		cmd := exec.CommandContext(ctx, "docker", args...) //nolint:gosec
 
-		cmd.Stdout = logger
-		cmd.Stderr = logger
+		out := newPrefixWriter(logger, defaultTailNodeLogsPrefixFormat, stream.Name, containerID)
 
-		err := cmd.Run()
-		if err != nil {
-			logger.Warnf(
-				"printing node logs for container id %q failed, err: %s", containerID, err,
+		cmd.Stdout = out
+		cmd.Stderr = out
+
+		err := runner.Run(cmd)
+		if err == nil {
+			continue
+		}
+
+		if logStreamEndedExpectedly(ctx, containerID, err) {
+			logger.Debugf(
+				"container id %q exited or was removed, could not print its logs", containerID,
 			)
+
+			continue
 		}
+
+		logger.Warnf(
+			"printing node logs for container id %q failed, err: %s", containerID, err,
+		)
 	}
 }
 
-func tailContainerLogs(
+// getContainerIDForNodeName returns the id of the container of the given (exactly matched, by the
+// containerlab node name label) node, or an empty string if there is none. Only running containers
+// are considered unless all is set. If retryFor is non-zero and no container is found, the lookup
+// is retried for up to retryFor -- right after containerlab creates a node its container may not be
+// listed yet. Pure lookups should pass zero.
+func getContainerIDForNodeName(
 	ctx context.Context,
-	logger claberneteslogging.Instance,
-	nodeLogger io.Writer,
-	containerIDs []string,
-) error {
-	nodeLogFile, err := os.Create("node.log")
-	if err != nil {
-		return err
+	nodeName string,
+	all bool,
+	retryFor time.Duration,
+) (string, error) {
+	args := []string{"ps"}
+
+	if all {
+		args = append(args, "-a")
 	}
 
-	nodeOutWriter := io.MultiWriter(nodeLogger, nodeLogFile)
+	args = append(
+		args,
+		"--quiet",
+		"--filter",
+		fmt.Sprintf("label=%s=%s", containerlabNodeNameLabel, nodeName),
+	)
 
-	for _, containerID := range containerIDs {
-		go func(containerID string, nodeOutWriter io.Writer) {
-			args := []string{
-				"logs",
-				"-f",
-				containerID,
-			}
+	lookup := func() (string, error) {
+		psCmd := exec.CommandContext(ctx, "docker", args...) //nolint:gosec
 
-			cmd := exec.CommandContext(ctx, "docker", args...) //nolint:gosec
+		output, err := runner.Output(psCmd)
+		if err != nil {
+			return "", classifyDockerError(err)
+		}
 
-			cmd.Stdout = nodeOutWriter
-			cmd.Stderr = nodeOutWriter
+		containerID, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
 
-			err = cmd.Run()
-			if err != nil {
-				logger.Warnf(
-					"tailing node logs for container id %q failed, err: %s", containerID, err,
-				)
-			}
-		}(containerID, nodeOutWriter)
+		return containerID, nil
 	}
 
-	return nil
-}
+	if retryFor <= 0 {
+		return lookup()
+	}
 
-func getContainerIDForNodeName(ctx context.Context, nodeName string) (string, error) {
-	psCmd := exec.CommandContext( //nolint:gosec
-		ctx,
-		"docker",
-		"ps",
-		"--quiet",
-		"--filter",
-		fmt.Sprintf("name=%s", nodeName),
-	)
+	retryCtx, cancel := context.WithTimeout(ctx, retryFor)
+	defer cancel()
 
-	output, err := psCmd.Output()
-	if err != nil {
-		return "", err
+	var containerID string
+
+	err := pollUntil(retryCtx, func() (bool, error) {
+		var err error
+
+		containerID, err = lookup()
+
+		return containerID != "", err
+	})
+	if err != nil && ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded) {
+		return "", nil
 	}
 
-	return strings.TrimSpace(string(output)), nil
+	return containerID, err
 }
 
 func getContainerAddr(ctx context.Context, containerID string) (string, error) {
@@ -263,10 +1573,61 @@ func getContainerAddr(ctx context.Context, containerID string) (string, error) {
 		containerID,
 	)
 
-	output, err := inspectCmd.Output()
+	output, err := runner.Output(inspectCmd)
 	if err != nil {
-		return "", err
+		return "", classifyDockerError(err)
 	}
 
 	return strings.TrimSpace(string(output)), nil
 }
+
+// getContainerAddrs returns the addresses of the given containers keyed by container id, looking
+// them up concurrently (at most maxConcurrentContainerAddrLookups at a time). A failed lookup does
+// not fail the others -- the returned map holds the addresses of all containers that could be
+// looked up and the returned error aggregates the failures.
+func getContainerAddrs(ctx context.Context, containerIDs []string) (map[string]string, error) {
+	addrs := make(map[string]string, len(containerIDs))
+
+	var errs []error
+
+	var lock sync.Mutex
+
+	containerIDsChan := make(chan string)
+
+	wg := &sync.WaitGroup{}
+
+	for range min(maxConcurrentContainerAddrLookups, len(containerIDs)) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for containerID := range containerIDsChan {
+				addr, err := getContainerAddr(ctx, containerID)
+
+				lock.Lock()
+
+				if err != nil {
+					errs = append(
+						errs,
+						fmt.Errorf("failed determining container %q address: %w", containerID, err),
+					)
+				} else {
+					addrs[containerID] = addr
+				}
+
+				lock.Unlock()
+			}
+		}()
+	}
+
+	for _, containerID := range containerIDs {
+		containerIDsChan <- containerID
+	}
+
+	close(containerIDsChan)
+
+	wg.Wait()
+
+	return addrs, errors.Join(errs...)
+}
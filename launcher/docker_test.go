@@ -0,0 +1,1624 @@
+package launcher_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+const renderDaemonConfigTestName = "daemon-config"
+
+var errFakeCommand = errors.New("fake command failed")
+
+type fakeCommandRunner struct {
+	lock    sync.Mutex
+	calls   map[string]int
+	results map[string]error
+	outputs map[string][]byte
+	stderrs map[string][]byte
+}
+
+func newFakeCommandRunner() *fakeCommandRunner {
+	return &fakeCommandRunner{
+		calls:   map[string]int{},
+		results: map[string]error{},
+		outputs: map[string][]byte{},
+		stderrs: map[string][]byte{},
+	}
+}
+
+func (r *fakeCommandRunner) key(cmd *exec.Cmd) string {
+	return strings.Join(cmd.Args, " ")
+}
+
+func (r *fakeCommandRunner) Run(cmd *exec.Cmd) error {
+	k := r.key(cmd)
+
+	r.lock.Lock()
+
+	r.calls[k]++
+
+	output, stderr, result := r.outputs[k], r.stderrs[k], r.results[k]
+
+	// not holding the lock while writing, the writers may well run commands themselves
+	r.lock.Unlock()
+
+	if cmd.Stdout != nil && output != nil {
+		_, _ = cmd.Stdout.Write(output)
+	}
+
+	if cmd.Stderr != nil && stderr != nil {
+		_, _ = cmd.Stderr.Write(stderr)
+	}
+
+	return result
+}
+
+func (r *fakeCommandRunner) Output(cmd *exec.Cmd) ([]byte, error) {
+	k := r.key(cmd)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.calls[k]++
+
+	return r.outputs[k], r.results[k]
+}
+
+func TestDockerHostIsExternal(t *testing.T) {
+	cases := []struct {
+		name       string
+		dockerHost string
+		expected   bool
+	}{
+		{
+			name:       "unset",
+			dockerHost: "",
+			expected:   false,
+		},
+		{
+			name:       "default-socket",
+			dockerHost: "unix:///var/run/docker.sock",
+			expected:   false,
+		},
+		{
+			name:       "other-socket",
+			dockerHost: "unix:///run/user/1000/docker.sock",
+			expected:   false,
+		},
+		{
+			name:       "tcp",
+			dockerHost: "tcp://10.0.0.1:2375",
+			expected:   true,
+		},
+		{
+			name:       "ssh",
+			dockerHost: "ssh://user@10.0.0.1",
+			expected:   true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				t.Setenv("DOCKER_HOST", testCase.dockerHost)
+
+				actual := claberneteslauncher.DockerHostIsExternal()
+				if actual != testCase.expected {
+					clabernetestesthelper.FailOutput(t, actual, testCase.expected)
+				}
+			})
+	}
+}
+
+func TestStartDocker(t *testing.T) {
+	cases := []struct {
+		name               string
+		dockerHost         string
+		psResult           error
+		psStderr           string
+		expectedStartCalls int
+		expectedErr        error
+	}{
+		{
+			name:               "docker-running",
+			psResult:           nil,
+			expectedStartCalls: 0,
+			expectedErr:        nil,
+		},
+		{
+			name:               "docker-never-starts",
+			psResult:           errFakeCommand,
+			expectedStartCalls: claberneteslauncher.MaxDockerLaunchAttempts,
+			expectedErr:        claberneteserrors.ErrLaunch,
+		},
+		{
+			name:               "local-docker-host-never-starts",
+			dockerHost:         "unix:///var/run/docker.sock",
+			psResult:           errFakeCommand,
+			expectedStartCalls: claberneteslauncher.MaxDockerLaunchAttempts,
+			expectedErr:        claberneteserrors.ErrLaunch,
+		},
+		{
+			name:     "docker-retryable-error",
+			psResult: errFakeCommand,
+			psStderr: "Cannot connect to the Docker daemon at unix:///var/run/docker.sock." +
+				" Is the docker daemon running?",
+			expectedStartCalls: claberneteslauncher.MaxDockerLaunchAttempts,
+			expectedErr:        claberneteserrors.ErrLaunch,
+		},
+		{
+			name:     "docker-permission-denied",
+			psResult: errFakeCommand,
+			psStderr: "permission denied while trying to connect to the Docker daemon socket at" +
+				" unix:///var/run/docker.sock",
+			expectedStartCalls: 0,
+			expectedErr:        claberneteserrors.ErrLaunch,
+		},
+		{
+			name:               "docker-no-space-left",
+			psResult:           errFakeCommand,
+			psStderr:           "failed to start daemon: write /var/lib/docker: no space left on device",
+			expectedStartCalls: 0,
+			expectedErr:        claberneteserrors.ErrLaunch,
+		},
+		{
+			name:               "external-docker-host-running",
+			dockerHost:         "tcp://10.0.0.1:2375",
+			psResult:           nil,
+			expectedStartCalls: 0,
+			expectedErr:        nil,
+		},
+		{
+			name:               "external-docker-host-unreachable",
+			dockerHost:         "tcp://10.0.0.1:2375",
+			psResult:           errFakeCommand,
+			expectedStartCalls: 0,
+			expectedErr:        claberneteserrors.ErrDockerUnavailable,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				t.Setenv("DOCKER_HOST", testCase.dockerHost)
+
+				fakeRunner := newFakeCommandRunner()
+				fakeRunner.results["docker ps"] = testCase.psResult
+
+				if testCase.psStderr != "" {
+					fakeRunner.stderrs["docker ps"] = []byte(testCase.psStderr)
+				}
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				err := claberneteslauncher.StartDocker(context.Background(), io.Discard)
+				if !errors.Is(err, testCase.expectedErr) {
+					t.Fatalf("expected error %v, got %v", testCase.expectedErr, err)
+				}
+
+				actualStartCalls := fakeRunner.calls["service docker start"]
+				if actualStartCalls != testCase.expectedStartCalls {
+					t.Fatalf(
+						"expected %d docker start calls, got %d",
+						testCase.expectedStartCalls,
+						actualStartCalls,
+					)
+				}
+			})
+	}
+}
+
+func TestRenderDaemonConfig(t *testing.T) {
+	cases := []struct {
+		name   string
+		config *claberneteslauncher.DaemonConfig
+	}{
+		{
+			name: "simple",
+			config: &claberneteslauncher.DaemonConfig{
+				StorageDriver: "overlay2",
+			},
+		},
+		{
+			name: "insecure-registries",
+			config: &claberneteslauncher.DaemonConfig{
+				StorageDriver:      "vfs",
+				InsecureRegistries: `"1.2.3.4","registry.local:5000"`,
+			},
+		},
+		{
+			name: "bip",
+			config: &claberneteslauncher.DaemonConfig{
+				StorageDriver: "overlay2",
+				Bip:           "192.168.99.1/24",
+			},
+		},
+		{
+			name: "bridge-none",
+			config: &claberneteslauncher.DaemonConfig{
+				StorageDriver: "overlay2",
+				Bridge:        "none",
+			},
+		},
+		{
+			name: "bridge-name",
+			config: &claberneteslauncher.DaemonConfig{
+				StorageDriver: "overlay2",
+				Bridge:        "clab-br0",
+			},
+		},
+		{
+			name: "shm-size",
+			config: &claberneteslauncher.DaemonConfig{
+				StorageDriver:  "overlay2",
+				DefaultShmSize: "256m",
+			},
+		},
+		{
+			name: "cgroup-parent",
+			config: &claberneteslauncher.DaemonConfig{
+				StorageDriver: "overlay2",
+				CgroupParent:  "/clabernetes",
+			},
+		},
+		{
+			name: "tls",
+			config: &claberneteslauncher.DaemonConfig{
+				StorageDriver: "overlay2",
+				TLSCACert:     "/clabernetes/.tls/ca.crt",
+				TLSCert:       "/clabernetes/.tls/tls.crt",
+				TLSKey:        "/clabernetes/.tls/tls.key",
+				LocalHost:     "unix:///var/run/docker.sock",
+				TLSHost:       "tcp://0.0.0.0:2376",
+			},
+		},
+		{
+			name: "features",
+			config: &claberneteslauncher.DaemonConfig{
+				StorageDriver: "overlay2",
+				Features:      `{"buildkit":false,"containerd-snapshotter":true}`,
+			},
+		},
+		{
+			name: "runtimes",
+			config: &claberneteslauncher.DaemonConfig{
+				StorageDriver:  "overlay2",
+				Runtimes:       `{"runsc":{"path":"/usr/local/bin/runsc"}}`,
+				DefaultRuntime: "runsc",
+			},
+		},
+		{
+			name: "storage-opts",
+			config: &claberneteslauncher.DaemonConfig{
+				StorageDriver: "overlay2",
+				StorageOpts:   `["overlay2.size=10G"]`,
+			},
+		},
+		{
+			name: "icc-disabled",
+			config: &claberneteslauncher.DaemonConfig{
+				StorageDriver: "overlay2",
+				ICC:           "false",
+			},
+		},
+		{
+			name: "ip-forward-masq",
+			config: &claberneteslauncher.DaemonConfig{
+				StorageDriver: "overlay2",
+				IPForward:     "false",
+				IPMasq:        "true",
+			},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				actual, err := claberneteslauncher.RenderDaemonConfig(testCase.config)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if !json.Valid(actual) {
+					t.Fatalf("rendered daemon config is not valid json:\n%s", actual)
+				}
+
+				goldenFileName := fmt.Sprintf(
+					"golden/%s/%s.json",
+					renderDaemonConfigTestName,
+					testCase.name,
+				)
+
+				if *clabernetestesthelper.Update {
+					clabernetestesthelper.WriteTestFixtureFile(t, goldenFileName, actual)
+				}
+
+				expected := clabernetestesthelper.ReadTestFixtureFile(t, goldenFileName)
+
+				if string(actual) != string(expected) {
+					clabernetestesthelper.FailOutput(t, actual, expected)
+				}
+			})
+	}
+}
+
+func TestParseDaemonFeatures(t *testing.T) {
+	cases := []struct {
+		name        string
+		in          string
+		expected    map[string]bool
+		expectedErr error
+	}{
+		{
+			name: "simple",
+			in:   "containerd-snapshotter=true",
+			expected: map[string]bool{
+				"containerd-snapshotter": true,
+			},
+		},
+		{
+			name: "multiple",
+			in:   "containerd-snapshotter=true, buildkit=false",
+			expected: map[string]bool{
+				"containerd-snapshotter": true,
+				"buildkit":               false,
+			},
+		},
+		{
+			name:        "invalid-value",
+			in:          "buildkit=yes",
+			expectedErr: claberneteserrors.ErrLaunch,
+		},
+		{
+			name:        "missing-value",
+			in:          "buildkit",
+			expectedErr: claberneteserrors.ErrLaunch,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				actual, err := claberneteslauncher.ParseDaemonFeatures(testCase.in)
+				if !errors.Is(err, testCase.expectedErr) {
+					t.Fatalf("expected error %v, got %v", testCase.expectedErr, err)
+				}
+
+				if testCase.expectedErr != nil {
+					return
+				}
+
+				clabernetestesthelper.MarshaledEqual(t, actual, testCase.expected)
+			})
+	}
+}
+
+func TestParseInsecureRegistries(t *testing.T) {
+	cases := []struct {
+		name     string
+		in       string
+		expected []string
+	}{
+		{
+			name:     "no-scheme",
+			in:       "1.2.3.4,registry.local:5000",
+			expected: []string{"1.2.3.4", "registry.local:5000"},
+		},
+		{
+			name:     "http-scheme",
+			in:       "http://registry.local:5000",
+			expected: []string{"registry.local:5000"},
+		},
+		{
+			name:     "https-scheme-trailing-slash",
+			in:       "HTTPS://registry.local:5000/",
+			expected: []string{"registry.local:5000"},
+		},
+		{
+			name:     "mixed",
+			in:       "http://1.2.3.4, registry.local:5000,,",
+			expected: []string{"1.2.3.4", "registry.local:5000"},
+		},
+		{
+			name:     "empty",
+			in:       "",
+			expected: nil,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				actual := claberneteslauncher.ParseInsecureRegistries(testCase.in)
+
+				clabernetestesthelper.MarshaledEqual(t, actual, testCase.expected)
+			})
+	}
+}
+
+func TestLoadInsecureRegistries(t *testing.T) {
+	cases := []struct {
+		name      string
+		env       string
+		file      string
+		expected  []string
+		expectErr bool
+	}{
+		{
+			name:     "env-only",
+			env:      "1.2.3.4,registry.local:5000",
+			expected: []string{"1.2.3.4", "registry.local:5000"},
+		},
+		{
+			name: "file-only",
+			file: "# corporate registries\n\nregistry.local:5000\n" +
+				"  http://10.0.0.1:5000  # lab mirror\n",
+			expected: []string{"registry.local:5000", "10.0.0.1:5000"},
+		},
+		{
+			name:     "merged-deduplicated",
+			env:      "registry.local:5000,1.2.3.4",
+			file:     "Registry.local:5000\n1.2.3.4\nother.local\n",
+			expected: []string{"registry.local:5000", "1.2.3.4", "other.local"},
+		},
+		{
+			name:      "missing-file",
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				t.Setenv(clabernetesconstants.LauncherInsecureRegistries, testCase.env)
+
+				path := filepath.Join(t.TempDir(), "registries")
+
+				if testCase.file != "" {
+					err := os.WriteFile(path, []byte(testCase.file), 0o644) //nolint:gosec
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				if testCase.file != "" || testCase.expectErr {
+					t.Setenv(clabernetesconstants.LauncherInsecureRegistriesFile, path)
+				}
+
+				actual, err := claberneteslauncher.LoadInsecureRegistries()
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+
+				clabernetestesthelper.MarshaledEqual(t, actual, testCase.expected)
+			})
+	}
+}
+
+func TestRegistryConfigIssues(t *testing.T) {
+	cases := []struct {
+		name               string
+		insecureRegistries []string
+		mirror             string
+		expected           []string
+	}{
+		{
+			name:               "consistent",
+			insecureRegistries: []string{"registry.local:5000", "10.0.0.0/8"},
+			mirror:             "mirror.local:5000",
+			expected:           nil,
+		},
+		{
+			name:               "http-mirror-insecure",
+			insecureRegistries: []string{"mirror.local:5000"},
+			mirror:             "http://mirror.local:5000",
+			expected: []string{
+				"image pull mirror \"http://mirror.local:5000\" includes a scheme, it must be " +
+					"host[:port] such as \"mirror.local:5000\"",
+			},
+		},
+		{
+			name:   "http-mirror-not-insecure",
+			mirror: "http://mirror.local:5000/",
+			expected: []string{
+				"image pull mirror \"http://mirror.local:5000/\" includes a scheme, it must be " +
+					"host[:port] such as \"mirror.local:5000\"",
+				"image pull mirror \"http://mirror.local:5000/\" is plain http but " +
+					"\"mirror.local:5000\" is not an insecure registry",
+			},
+		},
+		{
+			name:               "http-mirror-insecure-cidr",
+			insecureRegistries: []string{"10.0.0.0/8"},
+			mirror:             "http://10.1.2.3:5000",
+			expected: []string{
+				"image pull mirror \"http://10.1.2.3:5000\" includes a scheme, it must be " +
+					"host[:port] such as \"10.1.2.3:5000\"",
+			},
+		},
+		{
+			name:   "http-mirror-loopback",
+			mirror: "http://127.0.0.1:5000",
+			expected: []string{
+				"image pull mirror \"http://127.0.0.1:5000\" includes a scheme, it must be " +
+					"host[:port] such as \"127.0.0.1:5000\"",
+			},
+		},
+		{
+			name:   "https-mirror",
+			mirror: "https://mirror.local",
+			expected: []string{
+				"image pull mirror \"https://mirror.local\" includes a scheme, it must be " +
+					"host[:port] such as \"mirror.local\"",
+			},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				actual := claberneteslauncher.RegistryConfigIssues(
+					testCase.insecureRegistries,
+					testCase.mirror,
+				)
+
+				clabernetestesthelper.MarshaledEqual(t, actual, testCase.expected)
+			})
+	}
+}
+
+func TestBuildDaemonConfigRegistryConfigStrict(t *testing.T) {
+	t.Setenv(clabernetesconstants.LauncherInsecureRegistries, "registry.local:5000")
+	t.Setenv(clabernetesconstants.LauncherImagePullMirror, "http://mirror.local:5000")
+
+	_, err := claberneteslauncher.BuildDaemonConfig(context.Background())
+	if err != nil {
+		t.Fatalf("expected registry config issues to only be warned about, err: %s", err)
+	}
+
+	t.Setenv(clabernetesconstants.LauncherRegistryConfigStrict, "true")
+
+	_, err = claberneteslauncher.BuildDaemonConfig(context.Background())
+	if !errors.Is(err, claberneteserrors.ErrLaunch) {
+		t.Fatalf("expected launch error, got: %v", err)
+	}
+}
+
+func TestParseStorageOpts(t *testing.T) {
+	cases := []struct {
+		name          string
+		storageDriver string
+		in            string
+		expected      []string
+	}{
+		{
+			name:          "overlay2",
+			storageDriver: "overlay2",
+			in:            "overlay2.size=10G",
+			expected:      []string{"overlay2.size=10G"},
+		},
+		{
+			name:          "vfs",
+			storageDriver: "vfs",
+			in:            "size=10G",
+			expected:      []string{"size=10G"},
+		},
+		{
+			name:          "incompatible",
+			storageDriver: "overlay2",
+			in:            "dm.basesize=10G, overlay2.size=10G",
+			expected:      []string{"overlay2.size=10G"},
+		},
+		{
+			name:          "malformed",
+			storageDriver: "vfs",
+			in:            "size,,",
+			expected:      nil,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				actual := claberneteslauncher.ParseStorageOpts(
+					testCase.storageDriver,
+					testCase.in,
+				)
+
+				clabernetestesthelper.MarshaledEqual(t, actual, testCase.expected)
+			})
+	}
+}
+
+func TestValidateRestartPolicy(t *testing.T) {
+	cases := []struct {
+		name          string
+		restartPolicy string
+		expectErr     bool
+	}{
+		{
+			name:          "no",
+			restartPolicy: "no",
+		},
+		{
+			name:          "unless-stopped",
+			restartPolicy: "unless-stopped",
+		},
+		{
+			name:          "on-failure",
+			restartPolicy: "on-failure",
+		},
+		{
+			name:          "on-failure-max-retries",
+			restartPolicy: "on-failure:5",
+		},
+		{
+			name:          "on-failure-bad-max-retries",
+			restartPolicy: "on-failure:five",
+			expectErr:     true,
+		},
+		{
+			name:          "always-max-retries",
+			restartPolicy: "always:5",
+			expectErr:     true,
+		},
+		{
+			name:          "unknown",
+			restartPolicy: "sometimes",
+			expectErr:     true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				err := claberneteslauncher.ValidateRestartPolicy(testCase.restartPolicy)
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+			})
+	}
+}
+
+func TestWriteDaemonConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daemon.json")
+
+	rendered := []byte(`{"storage-driver": "overlay2"}`)
+
+	err := claberneteslauncher.WriteDaemonConfig(path, rendered)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// push the mtime back so we can tell if the file gets written again
+	past := time.Now().Add(-time.Hour)
+
+	err = os.Chtimes(path, past, past)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = claberneteslauncher.WriteDaemonConfig(path, rendered)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !info.ModTime().Equal(past) {
+		t.Fatalf("expected unchanged daemon config to not be rewritten")
+	}
+
+	updated := []byte(`{"storage-driver": "vfs"}`)
+
+	err = claberneteslauncher.WriteDaemonConfig(path, updated)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(actual) != string(updated) {
+		clabernetestesthelper.FailOutput(t, actual, updated)
+	}
+}
+
+func TestWriteDaemonConfigCustomPath(t *testing.T) {
+	if actual := claberneteslauncher.DockerDaemonConfigPath(); actual != "/etc/docker/daemon.json" {
+		clabernetestesthelper.FailOutput(t, actual, "/etc/docker/daemon.json")
+	}
+
+	path := filepath.Join(t.TempDir(), "rootless", "docker", "daemon.json")
+
+	t.Setenv(clabernetesconstants.LauncherDockerDaemonConfigPath, path)
+
+	if actual := claberneteslauncher.DockerDaemonConfigPath(); actual != path {
+		clabernetestesthelper.FailOutput(t, actual, path)
+	}
+
+	if claberneteslauncher.DaemonConfigExists() {
+		t.Fatal("expected daemon config at custom path to not exist yet")
+	}
+
+	rendered := []byte(`{"storage-driver": "overlay2"}`)
+
+	err := claberneteslauncher.WriteDaemonConfig(path, rendered)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !claberneteslauncher.DaemonConfigExists() {
+		t.Fatal("expected daemon config at custom path to exist")
+	}
+}
+
+func TestAssertDaemonConfig(t *testing.T) {
+	rendered := []byte("{\n    \"bip\": \"192.168.99.1/24\",\n    \"storage-driver\": \"overlay2\"\n}")
+
+	cases := []struct {
+		name      string
+		existing  string
+		missing   bool
+		expectErr bool
+	}{
+		{
+			name:     "identical",
+			existing: string(rendered),
+		},
+		{
+			name:     "equivalent",
+			existing: `{"storage-driver":"overlay2","bip":"192.168.99.1/24"}`,
+		},
+		{
+			name:      "different",
+			existing:  `{"storage-driver":"vfs","bip":"192.168.99.1/24"}`,
+			expectErr: true,
+		},
+		{
+			name:      "invalid-json",
+			existing:  `not json`,
+			expectErr: true,
+		},
+		{
+			name:      "missing",
+			missing:   true,
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				path := filepath.Join(t.TempDir(), "daemon.json")
+
+				if !testCase.missing {
+					err := os.WriteFile(path, []byte(testCase.existing), 0o644) //nolint:gosec
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				err := claberneteslauncher.AssertDaemonConfig(path, rendered)
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+
+				if testCase.expectErr && !testCase.missing &&
+					!strings.Contains(err.Error(), "+     \"storage-driver\": \"overlay2\"") {
+					t.Fatalf("expected error to contain a diff, got: %s", err)
+				}
+
+				if testCase.missing {
+					return
+				}
+
+				actual, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if string(actual) != testCase.existing {
+					t.Fatalf("expected daemon config to not be written")
+				}
+			})
+	}
+}
+
+func TestParseDaemonRuntimes(t *testing.T) {
+	runtimePath := filepath.Join(t.TempDir(), "runsc")
+
+	err := os.WriteFile(runtimePath, nil, 0o755) //nolint:gosec
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name      string
+		in        string
+		expected  map[string]string
+		expectErr bool
+	}{
+		{
+			name:     "simple",
+			in:       "runsc=" + runtimePath,
+			expected: map[string]string{"runsc": runtimePath},
+		},
+		{
+			name:     "empty",
+			in:       "",
+			expected: map[string]string{},
+		},
+		{
+			name:      "missing-binary",
+			in:        "kata=/not/a/real/kata-runtime",
+			expectErr: true,
+		},
+		{
+			name:      "malformed",
+			in:        "runsc",
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				actual, err := claberneteslauncher.ParseDaemonRuntimes(testCase.in)
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+
+				if testCase.expectErr {
+					return
+				}
+
+				clabernetestesthelper.MarshaledEqual(t, actual, testCase.expected)
+			})
+	}
+}
+
+func TestBuildDaemonConfigQuoting(t *testing.T) {
+	cases := []struct {
+		name                       string
+		insecureRegistries         string
+		cgroupParent               string
+		expectedInsecureRegistries []string
+	}{
+		{
+			name:                       "quotes",
+			insecureRegistries:         `evil"registry:5000`,
+			expectedInsecureRegistries: []string{`evil"registry:5000`},
+		},
+		{
+			name:                       "spaces-and-injection",
+			insecureRegistries:         `registry with spaces, "], "debug": true, "x": ["`,
+			cgroupParent:               "/clabernetes\n\"oops",
+			expectedInsecureRegistries: []string{"registry with spaces", `"]`, `"debug": true`, `"x": ["`},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				t.Setenv(clabernetesconstants.LauncherInsecureRegistries, testCase.insecureRegistries)
+				t.Setenv(clabernetesconstants.LauncherDockerCgroupParent, testCase.cgroupParent)
+
+				config, err := claberneteslauncher.BuildDaemonConfig(context.Background())
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				actual, err := claberneteslauncher.RenderDaemonConfig(config)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				var rendered struct {
+					InsecureRegistries []string `json:"insecure-registries"`
+					CgroupParent       string   `json:"cgroup-parent"`
+					Debug              bool     `json:"debug"`
+				}
+
+				err = json.Unmarshal(actual, &rendered)
+				if err != nil {
+					t.Fatalf("rendered daemon config is not valid json: %s\n%s", err, actual)
+				}
+
+				clabernetestesthelper.MarshaledEqual(
+					t,
+					rendered.InsecureRegistries,
+					testCase.expectedInsecureRegistries,
+				)
+
+				if rendered.CgroupParent != testCase.cgroupParent || rendered.Debug {
+					t.Fatalf("unexpected rendered daemon config:\n%s", actual)
+				}
+			})
+	}
+}
+
+func TestBuildDaemonConfigBooleans(t *testing.T) {
+	cases := []struct {
+		name      string
+		icc       string
+		ipForward string
+		ipMasq    string
+		expected  *claberneteslauncher.DaemonConfig
+		expectErr bool
+	}{
+		{
+			name:     "unset",
+			expected: &claberneteslauncher.DaemonConfig{},
+		},
+		{
+			name:     "icc-disabled",
+			icc:      "false",
+			expected: &claberneteslauncher.DaemonConfig{ICC: "false"},
+		},
+		{
+			name:     "icc-enabled",
+			icc:      "TRUE",
+			expected: &claberneteslauncher.DaemonConfig{ICC: "true"},
+		},
+		{
+			name:      "icc-invalid",
+			icc:       "nope",
+			expectErr: true,
+		},
+		{
+			name:      "ip-forward-masq",
+			ipForward: "0",
+			ipMasq:    "false",
+			expected:  &claberneteslauncher.DaemonConfig{IPForward: "false", IPMasq: "false"},
+		},
+		{
+			name:      "ip-masq-invalid",
+			ipMasq:    "off",
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				t.Setenv(clabernetesconstants.LauncherDockerICC, testCase.icc)
+				t.Setenv(clabernetesconstants.LauncherDockerIPForward, testCase.ipForward)
+				t.Setenv(clabernetesconstants.LauncherDockerIPMasq, testCase.ipMasq)
+
+				config, err := claberneteslauncher.BuildDaemonConfig(context.Background())
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+
+				if testCase.expectErr {
+					return
+				}
+
+				clabernetestesthelper.MarshaledEqual(
+					t,
+					[]string{config.ICC, config.IPForward, config.IPMasq},
+					[]string{
+						testCase.expected.ICC,
+						testCase.expected.IPForward,
+						testCase.expected.IPMasq,
+					},
+				)
+			})
+	}
+}
+
+func TestBuildDaemonConfigBridgeName(t *testing.T) {
+	cases := []struct {
+		name          string
+		bridgeName    string
+		bip           string
+		disableBridge string
+		expected      string
+		expectErr     bool
+	}{
+		{
+			name:     "unset",
+			expected: "",
+		},
+		{
+			name:       "simple",
+			bridgeName: "clab-br0",
+			expected:   "clab-br0",
+		},
+		{
+			name:       "max-length",
+			bridgeName: "abcdefghijklmno",
+			expected:   "abcdefghijklmno",
+		},
+		{
+			name:       "too-long",
+			bridgeName: "abcdefghijklmnop",
+			expectErr:  true,
+		},
+		{
+			name:       "slash",
+			bridgeName: "br/0",
+			expectErr:  true,
+		},
+		{
+			name:       "whitespace",
+			bridgeName: "br 0",
+			expectErr:  true,
+		},
+		{
+			name:       "dot-dot",
+			bridgeName: "..",
+			expectErr:  true,
+		},
+		{
+			name:       "none",
+			bridgeName: "none",
+			expectErr:  true,
+		},
+		{
+			name:       "with-bip",
+			bridgeName: "clab-br0",
+			bip:        "192.168.99.1/24",
+			expectErr:  true,
+		},
+		{
+			name:          "with-disable-bridge",
+			bridgeName:    "clab-br0",
+			disableBridge: "true",
+			expectErr:     true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				t.Setenv(clabernetesconstants.LauncherDockerBridgeName, testCase.bridgeName)
+				t.Setenv(clabernetesconstants.LauncherDockerBIP, testCase.bip)
+				t.Setenv(clabernetesconstants.LauncherDockerDisableBridge, testCase.disableBridge)
+
+				config, err := claberneteslauncher.BuildDaemonConfig(context.Background())
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+
+				if testCase.expectErr {
+					return
+				}
+
+				if config.Bridge != testCase.expected {
+					clabernetestesthelper.FailOutput(t, config.Bridge, testCase.expected)
+				}
+			})
+	}
+}
+
+func TestListContainers(t *testing.T) {
+	cases := []struct {
+		name      string
+		fixture   string
+		output    string
+		cmdErr    error
+		expected  []claberneteslauncher.ContainerSummary
+		expectErr bool
+	}{
+		{
+			name:    "all",
+			fixture: "docker-ps/all.jsonl",
+			expected: []claberneteslauncher.ContainerSummary{
+				{
+					ID:     "4f66ad9a0b2e5c1d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f9a0b1c2d3e4f",
+					Names:  "clab-clabernetes-srl1-srl1",
+					Image:  "ghcr.io/nokia/srlinux",
+					State:  "running",
+					Status: "Up 2 hours",
+				},
+				{
+					ID:     "9a8b7c6d5e4f3a2b1c0d9e8f7a6b5c4d3e2f1a0b9c8d7e6f5a4b3c2d1e0f9a8b",
+					Names:  "sidecar",
+					Image:  "alpine:3",
+					State:  "exited",
+					Status: "Exited (0) 1 hour ago",
+				},
+			},
+		},
+		{
+			name:     "empty",
+			output:   "\n",
+			expected: nil,
+		},
+		{
+			name:      "malformed",
+			output:    "not json\n",
+			expectErr: true,
+		},
+		{
+			name:      "command-failed",
+			cmdErr:    errFakeCommand,
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				fakeRunner := newFakeCommandRunner()
+
+				fakeKey := "docker ps -a --filter label=clabernetes/app=clabernetes" +
+					" --no-trunc --format {{json .}}"
+
+				fakeRunner.outputs[fakeKey] = []byte(testCase.output)
+				if testCase.fixture != "" {
+					fakeRunner.outputs[fakeKey] = clabernetestesthelper.ReadTestFixtureFile(
+						t,
+						testCase.fixture,
+					)
+				}
+
+				fakeRunner.results[fakeKey] = testCase.cmdErr
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				actual, err := claberneteslauncher.ListContainers(
+					context.Background(),
+					true,
+					"clabernetes/app=clabernetes",
+				)
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+
+				clabernetestesthelper.MarshaledEqual(t, actual, testCase.expected)
+			})
+	}
+}
+
+func TestBuildDaemonConfigBIP(t *testing.T) {
+	cases := []struct {
+		name      string
+		bip       string
+		expected  string
+		expectErr bool
+	}{
+		{
+			name:     "unset",
+			expected: "",
+		},
+		{
+			name:     "cidr",
+			bip:      "192.168.99.1/24",
+			expected: "192.168.99.1/24",
+		},
+		{
+			name:     "ipv6-cidr",
+			bip:      "fd00:99::1/64",
+			expected: "fd00:99::1/64",
+		},
+		{
+			name:      "missing-prefix-length",
+			bip:       "192.168.99.1",
+			expectErr: true,
+		},
+		{
+			name:      "not-an-address",
+			bip:       "docker0",
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				t.Setenv(clabernetesconstants.LauncherDockerBIP, testCase.bip)
+
+				config, err := claberneteslauncher.BuildDaemonConfig(context.Background())
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+
+				if testCase.expectErr {
+					if !errors.Is(err, claberneteserrors.ErrLaunch) {
+						clabernetestesthelper.FailOutput(t, err, claberneteserrors.ErrLaunch)
+					}
+
+					return
+				}
+
+				if config.Bip != testCase.expected {
+					clabernetestesthelper.FailOutput(t, config.Bip, testCase.expected)
+				}
+			})
+	}
+}
+
+func TestBuildDaemonConfigShmSize(t *testing.T) {
+	cases := []struct {
+		name      string
+		shmSize   string
+		expected  string
+		expectErr bool
+	}{
+		{
+			name:     "unset",
+			expected: "",
+		},
+		{
+			name:     "megabytes",
+			shmSize:  "256m",
+			expected: "256m",
+		},
+		{
+			name:     "gibibytes",
+			shmSize:  "1GiB",
+			expected: "1GiB",
+		},
+		{
+			name:     "bytes",
+			shmSize:  "67108864",
+			expected: "67108864",
+		},
+		{
+			name:      "unknown-unit",
+			shmSize:   "256x",
+			expectErr: true,
+		},
+		{
+			name:      "negative",
+			shmSize:   "-1m",
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				t.Setenv(clabernetesconstants.LauncherDockerShmSize, testCase.shmSize)
+
+				config, err := claberneteslauncher.BuildDaemonConfig(context.Background())
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+
+				if testCase.expectErr {
+					return
+				}
+
+				if config.DefaultShmSize != testCase.expected {
+					clabernetestesthelper.FailOutput(t, config.DefaultShmSize, testCase.expected)
+				}
+			})
+	}
+}
+
+func TestSetIPTablesBackend(t *testing.T) {
+	cases := []struct {
+		name          string
+		backend       string
+		expectErr     bool
+		expectedCalls map[string]int
+	}{
+		{
+			name:    "legacy",
+			backend: "legacy",
+			expectedCalls: map[string]int{
+				"update-alternatives --set iptables /usr/sbin/iptables-legacy":   1,
+				"update-alternatives --set ip6tables /usr/sbin/ip6tables-legacy": 1,
+			},
+		},
+		{
+			name:    "nft",
+			backend: "nft",
+			expectedCalls: map[string]int{
+				"update-alternatives --set iptables /usr/sbin/iptables-nft":   1,
+				"update-alternatives --set ip6tables /usr/sbin/ip6tables-nft": 1,
+			},
+		},
+		{
+			name:          "auto",
+			backend:       "auto",
+			expectedCalls: map[string]int{},
+		},
+		{
+			name:          "invalid",
+			backend:       "bpf",
+			expectErr:     true,
+			expectedCalls: map[string]int{},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				fakeRunner := newFakeCommandRunner()
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				err := claberneteslauncher.SetIPTablesBackend(
+					context.Background(),
+					testCase.backend,
+				)
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+
+				clabernetestesthelper.MarshaledEqual(t, fakeRunner.calls, testCase.expectedCalls)
+			})
+	}
+}
+
+func TestGetContainerAddrs(t *testing.T) {
+	fake := newFakeCommandRunner()
+
+	inspect := "docker inspect --format {{range.NetworkSettings.Networks}}{{.IPAddress}}{{end}} "
+
+	containerIDs := make([]string, 20)
+
+	for idx := range containerIDs {
+		containerIDs[idx] = fmt.Sprintf("container%d", idx)
+		fake.outputs[inspect+containerIDs[idx]] = []byte(fmt.Sprintf("172.20.20.%d\n", idx))
+	}
+
+	fake.results[inspect+"container7"] = &exec.ExitError{
+		Stderr: []byte("Error: No such object: container7"),
+	}
+
+	restore := claberneteslauncher.SetCommandRunner(fake)
+	defer restore()
+
+	addrs, err := claberneteslauncher.GetContainerAddrs(context.Background(), containerIDs)
+	if !errors.Is(err, claberneteserrors.ErrContainerNotFound) {
+		t.Fatalf("expected container not found error, got: %v", err)
+	}
+
+	if len(addrs) != len(containerIDs)-1 {
+		clabernetestesthelper.FailOutput(t, len(addrs), len(containerIDs)-1)
+	}
+
+	if _, ok := addrs["container7"]; ok {
+		t.Fatal("expected no address for failed container")
+	}
+
+	if addrs["container12"] != "172.20.20.12" {
+		clabernetestesthelper.FailOutput(t, addrs["container12"], "172.20.20.12")
+	}
+
+	for _, containerID := range containerIDs {
+		if fake.calls[inspect+containerID] != 1 {
+			t.Fatalf("expected container %q to be inspected once", containerID)
+		}
+	}
+}
+
+func TestSetDaemonConfigStorageDriver(t *testing.T) {
+	cases := []struct {
+		name     string
+		existing string
+		expected string
+	}{
+		{
+			name: "existing",
+			existing: `{
+    "storage-opts": ["overlay2.size=10G"],
+    "storage-driver": "overlay2",
+	"insecure-registries": [
+        "registry.local:5000"
+	]
+}`,
+			expected: `{
+    "insecure-registries": [
+        "registry.local:5000"
+    ],
+    "storage-driver": "vfs"
+}
+`,
+		},
+		{
+			name: "missing",
+			expected: `{
+    "storage-driver": "vfs"
+}
+`,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				path := filepath.Join(t.TempDir(), "daemon.json")
+
+				if testCase.existing != "" {
+					err := os.WriteFile(path, []byte(testCase.existing), 0o644) //nolint:gosec
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				err := claberneteslauncher.SetDaemonConfigStorageDriver(path, "vfs")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				actual, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if string(actual) != testCase.expected {
+					clabernetestesthelper.FailOutput(t, string(actual), testCase.expected)
+				}
+			})
+	}
+}
+
+func TestGetContainerIDForNodeName(t *testing.T) {
+	const psKey = "docker ps --quiet --filter label=clab-node-name=srl1"
+
+	cases := []struct {
+		name        string
+		output      string
+		delay       time.Duration
+		retryFor    time.Duration
+		expectedID  string
+		expectedMin time.Duration
+	}{
+		{
+			name:       "found",
+			output:     "abc123\n",
+			expectedID: "abc123",
+		},
+		{
+			name:       "multiple-takes-first",
+			output:     "abc123\ndef456\n",
+			expectedID: "abc123",
+		},
+		{
+			name:       "not-found-no-retry",
+			expectedID: "",
+		},
+		{
+			name:        "not-found-with-retry",
+			retryFor:    100 * time.Millisecond,
+			expectedID:  "",
+			expectedMin: 100 * time.Millisecond,
+		},
+		{
+			name:       "found-after-retry",
+			output:     "abc123\n",
+			delay:      50 * time.Millisecond,
+			retryFor:   5 * time.Second,
+			expectedID: "abc123",
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				t.Setenv(clabernetesconstants.LauncherWaitPollInterval, "10ms")
+
+				fakeRunner := newFakeCommandRunner()
+
+				if testCase.delay == 0 {
+					fakeRunner.outputs[psKey] = []byte(testCase.output)
+				} else {
+					go func() {
+						time.Sleep(testCase.delay)
+
+						fakeRunner.lock.Lock()
+						defer fakeRunner.lock.Unlock()
+
+						fakeRunner.outputs[psKey] = []byte(testCase.output)
+					}()
+				}
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				start := time.Now()
+
+				actual, err := claberneteslauncher.GetContainerIDForNodeName(
+					context.Background(),
+					"srl1",
+					testCase.retryFor,
+				)
+				if err != nil {
+					t.Fatalf("expected no error, got %s", err)
+				}
+
+				if actual != testCase.expectedID {
+					clabernetestesthelper.FailOutput(t, actual, testCase.expectedID)
+				}
+
+				if time.Since(start) < testCase.expectedMin {
+					t.Fatalf(
+						"expected retrying for at least %s, took %s",
+						testCase.expectedMin,
+						time.Since(start),
+					)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,133 @@
+package launcher
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+)
+
+//nolint:gochecknoglobals
+var dockerErrorPatterns = []struct {
+	err      error
+	patterns []string
+}{
+	{
+		err: claberneteserrors.ErrDockerUnavailable,
+		patterns: []string{
+			"cannot connect to the docker daemon",
+			"is the docker daemon running",
+			"error during connect",
+		},
+	},
+	{
+		err: claberneteserrors.ErrContainerNotFound,
+		patterns: []string{
+			"no such container",
+			"no such object",
+			"no such network",
+			"no such image",
+			// newer docker versions report missing networks as "network <name> not found"
+			"not found",
+		},
+	},
+	{
+		err: claberneteserrors.ErrDockerInvalidArgs,
+		patterns: []string{
+			"unknown flag",
+			"unknown shorthand flag",
+			"invalid argument",
+			"invalid reference format",
+			"requires at least",
+			"requires exactly",
+			"template parsing error",
+		},
+	},
+}
+
+// nonRetryableDockerErrorPatterns are (lower case) patterns of docker (or dockerd) stderr output
+// that indicate a failure that retrying will not fix.
+var nonRetryableDockerErrorPatterns = []string{ //nolint:gochecknoglobals
+	"permission denied",
+	"operation not permitted",
+	"no space left on device",
+	"read-only file system",
+	"exec format error",
+	"disk quota exceeded",
+}
+
+// nonRetryableDockerError returns the non-retryable error pattern found in the given docker stderr
+// output, or an empty string if the failure may well succeed when retried.
+func nonRetryableDockerError(stderr string) string {
+	lowerStderr := strings.ToLower(stderr)
+
+	for _, pattern := range nonRetryableDockerErrorPatterns {
+		if strings.Contains(lowerStderr, pattern) {
+			return pattern
+		}
+	}
+
+	return ""
+}
+
+// overlayStorageDriverErrorPatterns are (lower case) patterns of dockerd output that indicate the
+// daemon failed to start because the overlay2 storage driver is not usable on the host (kernel).
+var overlayStorageDriverErrorPatterns = []string{ //nolint:gochecknoglobals
+	"driver not supported: overlay2",
+	"'overlay' not found as a supported filesystem",
+	"overlay2 is not supported over",
+	"backing file system is unsupported for this graph driver",
+	"failed to mount overlay",
+	"error initializing graphdriver",
+}
+
+// overlayStorageDriverError returns the overlay2 storage driver failure pattern found in the given
+// dockerd output, or an empty string if the output shows no overlay2 specific failure.
+func overlayStorageDriverError(output string) string {
+	lowerOutput := strings.ToLower(output)
+
+	for _, pattern := range overlayStorageDriverErrorPatterns {
+		if strings.Contains(lowerOutput, pattern) {
+			return pattern
+		}
+	}
+
+	return ""
+}
+
+// classifyDockerError wraps the given (exec) error of a docker invocation in one of the typed
+// docker errors based on the stderr output of the command, so callers can errors.Is on the failure
+// kind. Errors that don't match any known pattern are returned as is.
+func classifyDockerError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, exec.ErrNotFound) {
+		return fmt.Errorf("%w: %w", claberneteserrors.ErrDockerUnavailable, err)
+	}
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return err
+	}
+
+	stderr := strings.TrimSpace(string(exitErr.Stderr))
+	lowerStderr := strings.ToLower(stderr)
+
+	for _, dockerErrorPattern := range dockerErrorPatterns {
+		for _, pattern := range dockerErrorPattern.patterns {
+			if strings.Contains(lowerStderr, pattern) {
+				return fmt.Errorf("%w: %s", dockerErrorPattern.err, stderr)
+			}
+		}
+	}
+
+	if stderr != "" {
+		return fmt.Errorf("%w, stderr: %s", err, stderr)
+	}
+
+	return err
+}
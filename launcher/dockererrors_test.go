@@ -0,0 +1,178 @@
+package launcher_test
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+func TestClassifyDockerError(t *testing.T) {
+	cases := []struct {
+		name     string
+		in       error
+		expected error
+	}{
+		{
+			name: "daemon-not-running",
+			in: &exec.ExitError{
+				Stderr: []byte(
+					"Cannot connect to the Docker daemon at unix:///var/run/docker.sock." +
+						" Is the docker daemon running?",
+				),
+			},
+			expected: claberneteserrors.ErrDockerUnavailable,
+		},
+		{
+			name:     "cli-missing",
+			in:       exec.ErrNotFound,
+			expected: claberneteserrors.ErrDockerUnavailable,
+		},
+		{
+			name: "container-gone",
+			in: &exec.ExitError{
+				Stderr: []byte("Error: No such container: deadbeef"),
+			},
+			expected: claberneteserrors.ErrContainerNotFound,
+		},
+		{
+			name: "bad-args",
+			in: &exec.ExitError{
+				Stderr: []byte("unknown flag: --bad"),
+			},
+			expected: claberneteserrors.ErrDockerInvalidArgs,
+		},
+		{
+			name: "unknown",
+			in: &exec.ExitError{
+				Stderr: []byte("something else entirely"),
+			},
+			expected: nil,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				actual := claberneteslauncher.ClassifyDockerError(testCase.in)
+
+				if testCase.expected == nil {
+					if errors.Is(actual, claberneteserrors.ErrLaunch) {
+						t.Fatalf("expected unclassified error, got %v", actual)
+					}
+
+					return
+				}
+
+				if !errors.Is(actual, testCase.expected) {
+					t.Fatalf("expected error %v, got %v", testCase.expected, actual)
+				}
+
+				if !errors.Is(actual, claberneteserrors.ErrLaunch) {
+					t.Fatalf("expected error %v to wrap ErrLaunch", actual)
+				}
+			})
+	}
+}
+
+func TestNonRetryableDockerError(t *testing.T) {
+	cases := []struct {
+		name     string
+		stderr   string
+		expected string
+	}{
+		{
+			name: "daemon-not-running",
+			stderr: "Cannot connect to the Docker daemon at unix:///var/run/docker.sock." +
+				" Is the docker daemon running?",
+			expected: "",
+		},
+		{
+			name: "socket-permission-denied",
+			stderr: "permission denied while trying to connect to the Docker daemon socket at" +
+				" unix:///var/run/docker.sock: Get \"http://%2Fvar%2Frun%2Fdocker.sock/v1.24/" +
+				"containers/json\": dial unix /var/run/docker.sock: connect: permission denied",
+			expected: "permission denied",
+		},
+		{
+			name:     "no-space-left",
+			stderr:   "Error response from daemon: mkdir /var/lib/docker/tmp: no space left on device",
+			expected: "no space left on device",
+		},
+		{
+			name:     "read-only",
+			stderr:   "failed to start daemon: mkdir /var/lib/docker: Read-only file system",
+			expected: "read-only file system",
+		},
+		{
+			name:     "timeout",
+			stderr:   "Error response from daemon: i/o timeout",
+			expected: "",
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				actual := claberneteslauncher.NonRetryableDockerError(testCase.stderr)
+				if actual != testCase.expected {
+					clabernetestesthelper.FailOutput(t, actual, testCase.expected)
+				}
+			})
+	}
+}
+
+func TestOverlayStorageDriverError(t *testing.T) {
+	cases := []struct {
+		name     string
+		output   string
+		expected string
+	}{
+		{
+			name: "driver-not-supported",
+			output: "level=info msg=\"Starting up\"\n" +
+				"failed to start daemon: error initializing graphdriver: " +
+				"driver not supported: overlay2\n",
+			expected: "driver not supported: overlay2",
+		},
+		{
+			name: "overlay-over-overlay",
+			output: "failed to start daemon: error initializing graphdriver: overlay2 is not " +
+				"supported over overlayfs, a mount_program is required",
+			expected: "overlay2 is not supported over",
+		},
+		{
+			name: "module-missing",
+			output: "level=error msg=\"'overlay' not found as a supported filesystem on this " +
+				"host. Please ensure kernel is new enough and has overlay support loaded.\"",
+			expected: "'overlay' not found as a supported filesystem",
+		},
+		{
+			name:     "unrelated",
+			output:   "failed to start daemon: Error initializing network controller",
+			expected: "",
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				actual := claberneteslauncher.OverlayStorageDriverError(testCase.output)
+				if actual != testCase.expected {
+					clabernetestesthelper.FailOutput(t, actual, testCase.expected)
+				}
+			})
+	}
+}
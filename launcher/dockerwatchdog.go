@@ -0,0 +1,244 @@
+package launcher
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"time"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+	clabernetesutil "github.com/srl-labs/clabernetes/util"
+)
+
+const (
+	defaultDockerWatchdogInterval         = 15 * time.Second
+	defaultDockerWatchdogFailureThreshold = 3
+	dockerPingTimeout                     = 5 * time.Second
+
+	dockerWatchdogPolicyLog     = "log"
+	dockerWatchdogPolicyExit    = "exit"
+	dockerWatchdogPolicyRestart = "restart"
+)
+
+// validateDockerWatchdogPolicy ensures the given docker watchdog policy is one we know how to
+// handle.
+func validateDockerWatchdogPolicy(policy string) error {
+	switch policy {
+	case dockerWatchdogPolicyLog, dockerWatchdogPolicyExit, dockerWatchdogPolicyRestart:
+		return nil
+	default:
+		return fmt.Errorf(
+			"%w: invalid docker watchdog policy %q, must be one of %q, %q or %q",
+			claberneteserrors.ErrLaunch,
+			policy,
+			dockerWatchdogPolicyLog,
+			dockerWatchdogPolicyExit,
+			dockerWatchdogPolicyRestart,
+		)
+	}
+}
+
+// pingDocker probes the docker daemon socket at the given host (in DOCKER_HOST form). For unix
+// sockets this hits the daemon's _ping endpoint, for tcp hosts (which may well be tls) a successful
+// dial is considered good enough. Any other host (i.e. ssh, where only the docker cli knows how to
+// connect) is probed by asking the docker cli for the server version.
+func pingDocker(ctx context.Context, dockerHost string) error {
+	ctx, cancel := context.WithTimeout(ctx, dockerPingTimeout)
+	defer cancel()
+
+	hostURL, err := url.Parse(dockerHost)
+	if err != nil {
+		return err
+	}
+
+	switch hostURL.Scheme {
+	case "unix":
+	case "tcp":
+		conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", hostURL.Host)
+		if err != nil {
+			return fmt.Errorf("%w: %w", claberneteserrors.ErrDockerUnavailable, err)
+		}
+
+		return conn.Close()
+	default:
+		return pingDockerCLI(ctx, dockerHost)
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", hostURL.Path)
+			},
+		},
+	}
+
+	defer client.CloseIdleConnections()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://docker/_ping", http.NoBody)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", claberneteserrors.ErrDockerUnavailable, err)
+	}
+
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf(
+			"%w: docker ping returned status %d",
+			claberneteserrors.ErrDockerUnavailable,
+			resp.StatusCode,
+		)
+	}
+
+	return nil
+}
+
+// pingDockerCLI probes the docker daemon at the given host via the docker cli.
+func pingDockerCLI(ctx context.Context, dockerHost string) error {
+	versionCmd := exec.CommandContext(
+		ctx,
+		"docker",
+		"--host",
+		dockerHost,
+		"version",
+		"--format",
+		"{{.Server.Version}}",
+	)
+
+	_, err := runner.Output(versionCmd)
+	if err != nil {
+		return fmt.Errorf(
+			"%w: %w",
+			claberneteserrors.ErrDockerUnavailable,
+			classifyDockerError(err),
+		)
+	}
+
+	return nil
+}
+
+// dockerWatchdog counts consecutive failed docker pings.
+type dockerWatchdog struct {
+	failureThreshold    int
+	consecutiveFailures int
+}
+
+// observe records the result of a docker ping, returning true once failureThreshold consecutive
+// pings have failed -- at which point the count starts over.
+func (w *dockerWatchdog) observe(err error) bool {
+	if err == nil {
+		w.consecutiveFailures = 0
+
+		return false
+	}
+
+	w.consecutiveFailures++
+
+	if w.consecutiveFailures < w.failureThreshold {
+		return false
+	}
+
+	w.consecutiveFailures = 0
+
+	return true
+}
+
+// watchDocker periodically pings the docker daemon and, once it has failed to answer
+// LauncherDockerWatchdogFailureThreshold times in a row, either just reports it, restarts it via
+// the usual start flow, or crashes the launcher so that kubernetes restarts the pod (per
+// LauncherDockerWatchdogPolicy). A zero interval disables the watchdog.
+func (c *clabernetes) watchDocker() {
+	interval := clabernetesutil.GetEnvDurationOrDefault(
+		clabernetesconstants.LauncherDockerWatchdogInterval,
+		defaultDockerWatchdogInterval,
+	)
+	if interval <= 0 {
+		return
+	}
+
+	policy := clabernetesutil.GetEnvStrOrDefault(
+		clabernetesconstants.LauncherDockerWatchdogPolicy,
+		dockerWatchdogPolicyLog,
+	)
+
+	if policy == dockerWatchdogPolicyRestart && dockerHostIsExternal() {
+		c.logger.Warn(
+			"docker watchdog cannot restart an external docker host, will exit instead",
+		)
+
+		policy = dockerWatchdogPolicyExit
+	}
+
+	watchdog := &dockerWatchdog{
+		failureThreshold: clabernetesutil.GetEnvIntOrDefault(
+			clabernetesconstants.LauncherDockerWatchdogFailureThreshold,
+			defaultDockerWatchdogFailureThreshold,
+		),
+	}
+
+	dockerHost := clabernetesutil.GetEnvStrOrDefault(dockerHostEnv, defaultDockerHost)
+
+	c.logger.Debugf(
+		"starting docker watchdog every %s with policy %q...", interval, policy,
+	)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := pingDocker(c.ctx, dockerHost)
+		if err != nil && c.ctx.Err() == nil {
+			c.logger.Warnf("docker watchdog ping failed, err: %s", err)
+		}
+
+		if !watchdog.observe(err) {
+			continue
+		}
+
+		if policy == dockerWatchdogPolicyLog {
+			c.logger.Criticalf(
+				"docker unresponsive for %d consecutive pings",
+				watchdog.failureThreshold,
+			)
+
+			continue
+		}
+
+		if policy == dockerWatchdogPolicyRestart {
+			c.logger.Criticalf(
+				"docker unresponsive for %d consecutive pings, restarting docker...",
+				watchdog.failureThreshold,
+			)
+
+			err = startDocker(c.ctx, c.logger)
+			if err == nil {
+				c.logger.Info("docker restarted by watchdog")
+
+				continue
+			}
+
+			c.logger.Criticalf("docker watchdog failed restarting docker, err: %s", err)
+		}
+
+		c.cancel()
+
+		c.logger.Fatalf(
+			"docker unresponsive for %d consecutive pings, exiting",
+			watchdog.failureThreshold,
+		)
+	}
+}
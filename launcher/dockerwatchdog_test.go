@@ -0,0 +1,145 @@
+package launcher_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+var errPingFailed = errors.New("ping failed")
+
+func TestPingDocker(t *testing.T) {
+	cases := []struct {
+		name       string
+		statusCode int
+		noServer   bool
+		expectErr  bool
+	}{
+		{
+			name:       "healthy",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "unhealthy",
+			statusCode: http.StatusInternalServerError,
+			expectErr:  true,
+		},
+		{
+			name:      "no-daemon",
+			noServer:  true,
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				socketPath := filepath.Join(t.TempDir(), "docker.sock")
+
+				if !testCase.noServer {
+					listener, err := net.Listen("unix", socketPath)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					server := &http.Server{ //nolint:gosec
+						Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+							if r.URL.Path != "/_ping" {
+								w.WriteHeader(http.StatusNotFound)
+
+								return
+							}
+
+							w.WriteHeader(testCase.statusCode)
+						}),
+					}
+
+					go func() {
+						_ = server.Serve(listener)
+					}()
+
+					defer func() {
+						_ = server.Close()
+					}()
+				}
+
+				err := claberneteslauncher.PingDocker(
+					context.Background(),
+					"unix://"+socketPath,
+				)
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+			})
+	}
+}
+
+func TestPingDockerSSH(t *testing.T) {
+	cases := []struct {
+		name      string
+		cmdErr    error
+		expectErr bool
+	}{
+		{
+			name: "healthy",
+		},
+		{
+			name:      "unreachable",
+			cmdErr:    errFakeCommand,
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				fakeRunner := newFakeCommandRunner()
+
+				fakeKey := "docker --host ssh://user@docker-host version" +
+					" --format {{.Server.Version}}"
+
+				fakeRunner.outputs[fakeKey] = []byte("28.3.0\n")
+				fakeRunner.results[fakeKey] = testCase.cmdErr
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				err := claberneteslauncher.PingDocker(
+					context.Background(),
+					"ssh://user@docker-host",
+				)
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+
+				if fakeRunner.calls[fakeKey] != 1 {
+					t.Fatalf("expected one docker version call, got %d", fakeRunner.calls[fakeKey])
+				}
+			})
+	}
+}
+
+func TestDockerWatchdogObserve(t *testing.T) {
+	actual := claberneteslauncher.DockerWatchdogObserve(
+		2,
+		[]error{errPingFailed, nil, errPingFailed, errPingFailed, errPingFailed, errPingFailed},
+	)
+
+	clabernetestesthelper.MarshaledEqual(
+		t,
+		actual,
+		[]bool{false, false, false, true, false, true},
+	)
+}
@@ -0,0 +1,123 @@
+package launcher
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+)
+
+const (
+	// drainLookupTimeout bounds resolving, snapshotting the logs of, and inspecting the drained
+	// node -- stopping it has its own budget, see shutdownStopBudget.
+	drainLookupTimeout = 30 * time.Second
+
+	drainedLogsDirName     = "drained"
+	drainedLogsTimeFormat  = "20060102T150405Z"
+	drainedLogsFileNameExt = ".log"
+)
+
+// DrainNode stops the container of the given (exactly matched) node with its configured stop
+// signal and timeout (see LauncherNodeStopConfig and LauncherContainerStopTimeout), leaving all
+// other nodes running. Unless skipLogs is true the node's logs are snapshotted to the "drained"
+// directory of the launcher work directory first. The outcome is written to stdout.
+func DrainNode(nodeName string, skipLogs bool) error {
+	return drainNode(context.Background(), os.Stdout, launcherWorkDir(), nodeName, skipLogs)
+}
+
+func drainNode(
+	ctx context.Context,
+	w io.Writer,
+	workDir, nodeName string,
+	skipLogs bool,
+) error {
+	nodeStopConfigs, err := loadNodeStopConfig()
+	if err != nil {
+		return err
+	}
+
+	defaultTimeout, err := loadContainerStopTimeout()
+	if err != nil {
+		return err
+	}
+
+	stopConfig := shutdownStopConfigs(nodeStopConfigs, []string{nodeName}, defaultTimeout)[nodeName]
+
+	lookupCtx, lookupCancel := context.WithTimeout(ctx, drainLookupTimeout)
+	defer lookupCancel()
+
+	index, err := newNodeContainerIndex(lookupCtx)
+	if err != nil {
+		return err
+	}
+
+	containerID, err := index.resolve(lookupCtx, nodeName)
+	if err != nil {
+		return err
+	}
+
+	if containerID == "" {
+		return fmt.Errorf(
+			"%w: no container found for node %q",
+			claberneteserrors.ErrContainerNotFound,
+			nodeName,
+		)
+	}
+
+	if !skipLogs {
+		logsPath := filepath.Join(
+			workDir,
+			drainedLogsDirName,
+			nodeName+"-"+time.Now().UTC().Format(drainedLogsTimeFormat)+drainedLogsFileNameExt,
+		)
+
+		// a missing snapshot should not keep an operator from taking the node down
+		err = writeCommandOutput(lookupCtx, logsPath, "logs", "--timestamps", containerID)
+		if err != nil {
+			_, _ = fmt.Fprintf(w, "failed snapshotting node %q logs, err: %s\n", nodeName, err)
+		} else {
+			_, _ = fmt.Fprintf(w, "node %q logs snapshotted to %q\n", nodeName, logsPath)
+		}
+	}
+
+	stopCtx, stopCancel := context.WithTimeout(
+		ctx,
+		shutdownStopBudget(map[string]nodeStopConfig{nodeName: stopConfig}),
+	)
+	defer stopCancel()
+
+	start := time.Now()
+
+	err = stopContainer(stopCtx, containerID, stopConfig)
+	if err != nil {
+		return fmt.Errorf("failed stopping node %q, err: %w", nodeName, err)
+	}
+
+	stopped := time.Since(start).Round(time.Millisecond)
+
+	// the container may well have been removed on stop, in which case there is no state to report
+	inspected, err := inspectContainers(lookupCtx, []string{containerID})
+
+	result, ok := inspected[containerID]
+	if err != nil || !ok {
+		_, err = fmt.Fprintf(w, "node %q drained in %s\n", nodeName, stopped)
+
+		return err
+	}
+
+	_, err = fmt.Fprintf(
+		w,
+		"node %q drained in %s, container %q %s (exit code %d)\n",
+		nodeName,
+		stopped,
+		containerID,
+		result.State.Status,
+		result.State.ExitCode,
+	)
+
+	return err
+}
@@ -0,0 +1,128 @@
+package launcher_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+func TestDrainNode(t *testing.T) {
+	const (
+		logsKey = "docker logs --timestamps 4f66ad9a0b2e"
+		stopKey = "docker stop --signal SIGINT --time 30 4f66ad9a0b2e"
+	)
+
+	cases := []struct {
+		name             string
+		skipLogs         bool
+		expectedLogFiles int
+	}{
+		{
+			name:             "with-logs",
+			expectedLogFiles: 1,
+		},
+		{
+			name:     "skip-logs",
+			skipLogs: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				t.Setenv(
+					clabernetesconstants.LauncherNodeStopConfig,
+					`{"srl1": {"signal": "SIGINT", "timeout": "30s"}}`,
+				)
+
+				workDir := t.TempDir()
+
+				fakeRunner := newFakeCommandRunner()
+
+				fakeRunner.outputs[`docker ps --all --filter label=containerlab`+
+					` --format {{.Label "clab-node-name"}} {{.ID}}`] = []byte(
+					"srl2 deadbeef\nsrl1 4f66ad9a0b2e\n",
+				)
+				fakeRunner.outputs[logsKey] = []byte("2024-01-01T00:00:00Z booted\n")
+				fakeRunner.outputs["docker inspect 4f66ad9a0b2e"] = clabernetestesthelper.
+					ReadTestFixtureFile(t, "docker-inspect/partial.json")
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				actual, err := claberneteslauncher.DrainNodeOutput(
+					context.Background(),
+					workDir,
+					"srl1",
+					testCase.skipLogs,
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if fakeRunner.calls[stopKey] != 1 {
+					t.Fatalf("expected node to be stopped once, calls: %v", fakeRunner.calls)
+				}
+
+				for call := range fakeRunner.calls {
+					if strings.Contains(call, "deadbeef") {
+						t.Fatalf("expected other nodes to be left alone, got call %q", call)
+					}
+				}
+
+				if !strings.Contains(string(actual), `node "srl1" drained in`) {
+					clabernetestesthelper.FailOutput(t, actual, `node "srl1" drained in`)
+				}
+
+				logFiles, _ := filepath.Glob(filepath.Join(workDir, "drained", "srl1-*.log"))
+				if len(logFiles) != testCase.expectedLogFiles {
+					t.Fatalf(
+						"expected %d log snapshot(s), got %v",
+						testCase.expectedLogFiles,
+						logFiles,
+					)
+				}
+
+				if testCase.expectedLogFiles == 0 {
+					return
+				}
+
+				content, err := os.ReadFile(logFiles[0])
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if string(content) != string(fakeRunner.outputs[logsKey]) {
+					clabernetestesthelper.FailOutput(t, content, fakeRunner.outputs[logsKey])
+				}
+			})
+	}
+}
+
+func TestDrainNodeNotFound(t *testing.T) {
+	fakeRunner := newFakeCommandRunner()
+
+	restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+	defer restore()
+
+	_, err := claberneteslauncher.DrainNodeOutput(
+		context.Background(),
+		t.TempDir(),
+		"srl1",
+		false,
+	)
+	if !errors.Is(err, claberneteserrors.ErrContainerNotFound) {
+		t.Fatalf("expected container not found error, got %v", err)
+	}
+}
@@ -0,0 +1,308 @@
+package launcher
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+	claberneteslogging "github.com/srl-labs/clabernetes/logging"
+	clabernetesutil "github.com/srl-labs/clabernetes/util"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	existingNodePolicyReuse    = "reuse"
+	existingNodePolicyRecreate = "recreate"
+	existingNodePolicyError    = "error"
+)
+
+// existingNodeAction is what the launcher does about node containers left over from a previous
+// run.
+type existingNodeAction int
+
+const (
+	// existingNodeActionDeploy means there is nothing left over, deploy as usual.
+	existingNodeActionDeploy existingNodeAction = iota
+	// existingNodeActionReuse means all nodes have an (up to date) container, skip the deploy and
+	// (re)start the existing containers.
+	existingNodeActionReuse
+	// existingNodeActionRecreate means the existing containers are removed before deploying.
+	existingNodeActionRecreate
+)
+
+// validateExistingNodePolicy ensures the given existing node policy is one we know how to handle.
+func validateExistingNodePolicy(policy string) error {
+	switch policy {
+	case existingNodePolicyReuse, existingNodePolicyRecreate, existingNodePolicyError:
+		return nil
+	default:
+		return fmt.Errorf(
+			"%w: invalid existing node policy %q, must be one of %q, %q, or %q",
+			claberneteserrors.ErrLaunch,
+			policy,
+			existingNodePolicyReuse,
+			existingNodePolicyRecreate,
+			existingNodePolicyError,
+		)
+	}
+}
+
+// topologyConfigHash returns the hex encoded sha256 of the canonical form of the given topology
+// content. The topology is hashed as (key sorted) json rather than as is, so equivalent topologies
+// that only differ in formatting, key order, quoting, or comments hash the same.
+func topologyConfigHash(content []byte) (string, error) {
+	topology := map[string]any{}
+
+	err := yaml.Unmarshal(content, &topology)
+	if err != nil {
+		return "", err
+	}
+
+	canonical, err := json.Marshal(topology)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(canonical)
+
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// containerConfigHash returns the config hash label of the given container, empty if the container
+// was not created with one.
+func containerConfigHash(ctx context.Context, containerID string) (string, error) {
+	return getContainerLabel(ctx, containerID, clabernetesconstants.LabelLauncherNodeConfigHash)
+}
+
+// applyNodeConfigHashLabel sets the config hash label in the labels of the given containerlab node
+// definition, containerlab sets node labels as container labels.
+func applyNodeConfigHashLabel(node map[string]any, configHash string) {
+	nodeLabels, ok := node["labels"].(map[string]any)
+	if !ok || nodeLabels == nil {
+		nodeLabels = map[string]any{}
+	}
+
+	nodeLabels[clabernetesconstants.LabelLauncherNodeConfigHash] = configHash
+
+	node["labels"] = nodeLabels
+}
+
+// decideExistingNodeAction decides what to do about the given existing node containers (a map of
+// node name to the config hash label of that node's container) per the given policy. Existing
+// containers are always recreated if only some of the nodes have one, and with the "recreate"
+// policy also if any of them was created from a different config -- otherwise they are reused.
+func decideExistingNodeAction(
+	policy, configHash string,
+	nodeNames []string,
+	existingConfigHashes map[string]string,
+) (existingNodeAction, error) {
+	if len(existingConfigHashes) == 0 {
+		return existingNodeActionDeploy, nil
+	}
+
+	switch policy {
+	case existingNodePolicyError:
+		existingNodeNames := make([]string, 0, len(existingConfigHashes))
+
+		for nodeName := range existingConfigHashes {
+			existingNodeNames = append(existingNodeNames, nodeName)
+		}
+
+		slices.Sort(existingNodeNames)
+
+		return existingNodeActionDeploy, fmt.Errorf(
+			"%w: containers already exist for nodes %q",
+			claberneteserrors.ErrLaunch,
+			existingNodeNames,
+		)
+	}
+
+	// a partial set of containers can't be reused, containerlab has to deploy the missing nodes
+	if len(existingConfigHashes) != len(nodeNames) {
+		return existingNodeActionRecreate, nil
+	}
+
+	for _, nodeName := range nodeNames {
+		existingConfigHash, ok := existingConfigHashes[nodeName]
+		if !ok {
+			return existingNodeActionRecreate, nil
+		}
+
+		if policy == existingNodePolicyRecreate && existingConfigHash != configHash {
+			return existingNodeActionRecreate, nil
+		}
+	}
+
+	return existingNodeActionReuse, nil
+}
+
+// handleExistingNodes labels the nodes in the topology with the hash of the topology and, if an
+// existing node policy is configured, checks for node containers left over from a previous run,
+// handling them per that policy. It returns true if the existing containers are reused, meaning
+// containerlab should not deploy the topology.
+func (c *clabernetes) handleExistingNodes() bool {
+	policy := os.Getenv(clabernetesconstants.LauncherExistingNodePolicy)
+	if policy == "" {
+		return false
+	}
+
+	content, err := os.ReadFile(c.topologyPath())
+	if err != nil {
+		c.logger.Fatalf("failed reading containerlab topology, err: %s", err)
+	}
+
+	configHash, err := topologyConfigHash(content)
+	if err != nil {
+		c.logger.Fatalf("failed hashing containerlab topology, err: %s", err)
+	}
+
+	var nodeNames []string
+
+	err = patchTopologyNodes(c.topologyPath(), func(nodeName string, node map[string]any) error {
+		nodeNames = append(nodeNames, nodeName)
+
+		applyNodeConfigHashLabel(node, configHash)
+
+		return nil
+	})
+	if err != nil {
+		c.logger.Fatalf("failed labeling topology nodes with config hash, err: %s", err)
+	}
+
+	index, err := newNodeContainerIndex(c.ctx)
+	if err != nil {
+		c.logger.Fatalf("failed listing existing node containers, err: %s", err)
+	}
+
+	var existingContainerIDs []string
+
+	existingConfigHashes := map[string]string{}
+
+	for _, nodeName := range nodeNames {
+		containerID, ok := index.lookup(nodeName)
+		if !ok {
+			continue
+		}
+
+		existingContainerIDs = append(existingContainerIDs, containerID)
+
+		existingConfigHashes[nodeName], err = containerConfigHash(c.ctx, containerID)
+		if err != nil {
+			c.logger.Fatalf(
+				"failed checking config hash of existing container for node %q, err: %s",
+				nodeName,
+				err,
+			)
+		}
+	}
+
+	action, err := decideExistingNodeAction(
+		policy,
+		configHash,
+		nodeNames,
+		existingConfigHashes,
+	)
+	if err != nil {
+		c.logger.Fatalf("existing node policy %q check failed, err: %s", policy, err)
+	}
+
+	switch action {
+	case existingNodeActionReuse:
+		c.logger.Infof("reusing existing containers for nodes %q", nodeNames)
+
+		err = runDockerContainerCmd(
+			c.ctx,
+			c.logger,
+			append([]string{"start"}, existingContainerIDs...)...,
+		)
+		if err != nil {
+			c.logger.Fatalf("failed starting existing node containers, err: %s", err)
+		}
+
+		return true
+	case existingNodeActionRecreate:
+		c.logger.Infof("node config changed, recreating existing node containers")
+
+		err = removeNodeContainers(
+			c.ctx,
+			c.logger,
+			c.nodeLogger,
+			existingContainerIDs,
+			clabernetesutil.GetEnvDurationOrDefault(
+				clabernetesconstants.LauncherNodeRemoveGrace,
+				0,
+			),
+			strings.EqualFold(
+				os.Getenv(clabernetesconstants.LauncherNodeRemoveSnapshotLogs),
+				clabernetesconstants.True,
+			),
+		)
+		if err != nil {
+			c.logger.Fatalf("failed removing existing node containers, err: %s", err)
+		}
+	case existingNodeActionDeploy:
+	}
+
+	return false
+}
+
+// removeNodeContainers removes the given containers. If grace is non-zero the containers are first
+// stopped with that grace period so the nodes can shut down cleanly, and if snapshotLogs is set
+// their logs are printed to nodeLogger before removal so the final logs of the previous run are not
+// lost along with the containers.
+func removeNodeContainers(
+	ctx context.Context,
+	logger, nodeLogger claberneteslogging.Instance,
+	containerIDs []string,
+	grace time.Duration,
+	snapshotLogs bool,
+) error {
+	if grace > 0 {
+		logger.Infof("stopping containers %q with a %s grace period...", containerIDs, grace)
+
+		err := runDockerContainerCmd(
+			ctx,
+			logger,
+			append(
+				[]string{"stop", "--time", strconv.Itoa(int(math.Ceil(grace.Seconds())))},
+				containerIDs...,
+			)...,
+		)
+		if err != nil {
+			logger.Warnf("failed stopping containers, will force remove them, err: %s", err)
+		}
+	}
+
+	if snapshotLogs {
+		logger.Debugf("snapshotting logs of containers %q before removal", containerIDs)
+
+		printContainerLogs(ctx, nodeLogger, containerIDs)
+	}
+
+	return runDockerContainerCmd(
+		ctx,
+		logger,
+		append([]string{"rm", "--force"}, containerIDs...)...,
+	)
+}
+
+func runDockerContainerCmd(ctx context.Context, logger io.Writer, args ...string) error {
+	cmd := exec.CommandContext(ctx, "docker", append([]string{"container"}, args...)...)
+
+	cmd.Stdout = logger
+	cmd.Stderr = logger
+
+	return classifyDockerError(runner.Run(cmd))
+}
@@ -0,0 +1,294 @@
+package launcher_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+func TestDecideExistingNodeAction(t *testing.T) {
+	cases := []struct {
+		name                 string
+		policy               string
+		existingConfigHashes map[string]string
+		expected             string
+		expectErr            bool
+	}{
+		{
+			name:     "nothing-existing",
+			policy:   "error",
+			expected: "deploy",
+		},
+		{
+			name:                 "error",
+			policy:               "error",
+			existingConfigHashes: map[string]string{"srl1": "abc"},
+			expected:             "deploy",
+			expectErr:            true,
+		},
+		{
+			name:                 "reuse-changed",
+			policy:               "reuse",
+			existingConfigHashes: map[string]string{"srl1": "old"},
+			expected:             "reuse",
+		},
+		{
+			name:                 "recreate-unchanged",
+			policy:               "recreate",
+			existingConfigHashes: map[string]string{"srl1": "abc"},
+			expected:             "reuse",
+		},
+		{
+			name:                 "recreate-changed",
+			policy:               "recreate",
+			existingConfigHashes: map[string]string{"srl1": "old"},
+			expected:             "recreate",
+		},
+		{
+			name:                 "recreate-unlabeled",
+			policy:               "recreate",
+			existingConfigHashes: map[string]string{"srl1": ""},
+			expected:             "recreate",
+		},
+		{
+			name:   "reuse-node-set-changed",
+			policy: "reuse",
+			existingConfigHashes: map[string]string{
+				"srl2": "abc",
+			},
+			expected: "recreate",
+		},
+		{
+			name:   "recreate-node-set-changed",
+			policy: "recreate",
+			existingConfigHashes: map[string]string{
+				"srl1": "abc",
+				"srl2": "abc",
+			},
+			expected: "recreate",
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				actual, err := claberneteslauncher.DecideExistingNodeAction(
+					testCase.policy,
+					"abc",
+					[]string{"srl1"},
+					testCase.existingConfigHashes,
+				)
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+
+				if actual != testCase.expected {
+					clabernetestesthelper.FailOutput(t, actual, testCase.expected)
+				}
+			})
+	}
+}
+
+func TestTopologyConfigHash(t *testing.T) {
+	baseTopology := `name: clabernetes-srl1
+topology:
+  nodes:
+    srl1:
+      kind: nokia_srlinux
+      image: ghcr.io/nokia/srlinux
+      env:
+        A: "1"
+        B: "2"
+`
+
+	cases := []struct {
+		name          string
+		topology      string
+		expectedEqual bool
+	}{
+		{
+			name:          "identical",
+			topology:      baseTopology,
+			expectedEqual: true,
+		},
+		{
+			name: "reordered-requoted-and-commented",
+			topology: `# deployed by clabernetes
+topology:
+    nodes:
+        srl1:
+            env: {B: '2', A: "1"}
+            image: "ghcr.io/nokia/srlinux"
+            kind: nokia_srlinux
+name: clabernetes-srl1
+`,
+			expectedEqual: true,
+		},
+		{
+			name: "json",
+			topology: `{"name": "clabernetes-srl1", "topology": {"nodes": {"srl1": ` +
+				`{"kind": "nokia_srlinux", "image": "ghcr.io/nokia/srlinux",` +
+				` "env": {"A": "1", "B": "2"}}}}}`,
+			expectedEqual: true,
+		},
+		{
+			name: "changed-value",
+			topology: `name: clabernetes-srl1
+topology:
+  nodes:
+    srl1:
+      kind: nokia_srlinux
+      image: ghcr.io/nokia/srlinux:24.3.1
+      env:
+        A: "1"
+        B: "2"
+`,
+			expectedEqual: false,
+		},
+	}
+
+	baseHash, err := claberneteslauncher.TopologyConfigHash([]byte(baseTopology))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				actual, err := claberneteslauncher.TopologyConfigHash([]byte(testCase.topology))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if (actual == baseHash) != testCase.expectedEqual {
+					clabernetestesthelper.FailOutput(t, actual, baseHash)
+				}
+			})
+	}
+}
+
+func TestContainerConfigHash(t *testing.T) {
+	fakeRunner := newFakeCommandRunner()
+
+	fakeRunner.outputs[`docker inspect --format {{index .Config.Labels `+
+		`"clabernetes/launcherNodeConfigHash"}} abc123`] = []byte("deadbeef\n")
+	fakeRunner.outputs[`docker inspect --format {{index .Config.Labels `+
+		`"clabernetes/launcherNodeConfigHash"}} def456`] = []byte("<no value>\n")
+
+	restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+	defer restore()
+
+	for containerID, expected := range map[string]string{
+		"abc123": "deadbeef",
+		"def456": "",
+	} {
+		actual, err := claberneteslauncher.ContainerConfigHash(context.Background(), containerID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if actual != expected {
+			clabernetestesthelper.FailOutput(t, actual, expected)
+		}
+	}
+}
+
+func TestRemoveNodeContainers(t *testing.T) {
+	stopKey := "docker container stop --time 3 abc def"
+	logsKeys := []string{"docker logs abc", "docker logs def"}
+	rmKey := "docker container rm --force abc def"
+
+	cases := []struct {
+		name              string
+		grace             time.Duration
+		snapshotLogs      bool
+		stopErr           error
+		rmErr             error
+		expectedStopCalls int
+		expectedLogsCalls int
+		expectErr         bool
+	}{
+		{
+			name: "force-remove",
+		},
+		{
+			name:              "grace",
+			grace:             2500 * time.Millisecond,
+			expectedStopCalls: 1,
+		},
+		{
+			name:              "grace-and-snapshot",
+			grace:             3 * time.Second,
+			snapshotLogs:      true,
+			expectedStopCalls: 1,
+			expectedLogsCalls: 1,
+		},
+		{
+			name:              "stop-fails-still-removes",
+			grace:             3 * time.Second,
+			stopErr:           errFakeCommand,
+			expectedStopCalls: 1,
+		},
+		{
+			name:      "remove-fails",
+			rmErr:     errFakeCommand,
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				fakeRunner := newFakeCommandRunner()
+				fakeRunner.results[stopKey] = testCase.stopErr
+				fakeRunner.results[rmKey] = testCase.rmErr
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				err := claberneteslauncher.RemoveNodeContainers(
+					context.Background(),
+					[]string{"abc", "def"},
+					testCase.grace,
+					testCase.snapshotLogs,
+				)
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+
+				if fakeRunner.calls[stopKey] != testCase.expectedStopCalls {
+					clabernetestesthelper.FailOutput(
+						t,
+						fakeRunner.calls[stopKey],
+						testCase.expectedStopCalls,
+					)
+				}
+
+				for _, logsKey := range logsKeys {
+					if fakeRunner.calls[logsKey] != testCase.expectedLogsCalls {
+						clabernetestesthelper.FailOutput(
+							t,
+							fakeRunner.calls[logsKey],
+							testCase.expectedLogsCalls,
+						)
+					}
+				}
+
+				if fakeRunner.calls[rmKey] != 1 {
+					clabernetestesthelper.FailOutput(t, fakeRunner.calls[rmKey], 1)
+				}
+			})
+	}
+}
@@ -0,0 +1,157 @@
+package launcher
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// nodeExitCodes tracks the last exit code of the node containers that exited, keyed by node name.
+type nodeExitCodes struct {
+	lock  sync.Mutex
+	codes map[string]int
+}
+
+func newNodeExitCodes() *nodeExitCodes {
+	return &nodeExitCodes{
+		codes: map[string]int{},
+	}
+}
+
+func (e *nodeExitCodes) record(nodeName string, exitCode int) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.codes[nodeName] = exitCode
+}
+
+// snapshot returns a copy of the recorded exit codes.
+func (e *nodeExitCodes) snapshot() map[string]int {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	return maps.Clone(e.codes)
+}
+
+// formatExitCodes formats the given node exit codes for logging, sorted by node name.
+func formatExitCodes(exitCodes map[string]int) string {
+	formatted := make([]string, 0, len(exitCodes))
+
+	for _, nodeName := range slices.Sorted(maps.Keys(exitCodes)) {
+		formatted = append(formatted, fmt.Sprintf("%s=%d", nodeName, exitCodes[nodeName]))
+	}
+
+	return strings.Join(formatted, ", ")
+}
+
+// inspectedNodeName returns the (containerlab) node name of the given inspected container,
+// falling back to the container name if it is not a node container.
+func inspectedNodeName(result *containerInspect) string {
+	nodeName := result.Config.Labels[containerlabNodeNameLabel]
+	if nodeName == "" {
+		nodeName = strings.TrimPrefix(result.Name, "/")
+	}
+
+	return nodeName
+}
+
+// containerExitCode returns the node name of the given container, whether it exited, and if so
+// its exit code.
+func containerExitCode(ctx context.Context, containerID string) (string, bool, int, error) {
+	inspected, err := inspectContainers(ctx, []string{containerID})
+	if err != nil {
+		return "", false, 0, err
+	}
+
+	result, ok := inspected[containerID]
+	if !ok {
+		return "", false, 0, fmt.Errorf("container %q not found", containerID)
+	}
+
+	exited := result.State.Status == containerStateExited ||
+		result.State.Status == containerStateDead
+
+	return inspectedNodeName(result), exited, result.State.ExitCode, nil
+}
+
+// checkContainerExitCode records the exit code of the given (stopped) container so that it is
+// reported by the readiness endpoint and the shutdown summary, warning if it is non-zero.
+func (c *clabernetes) checkContainerExitCode(containerID string) {
+	nodeName, exited, exitCode, err := containerExitCode(c.ctx, containerID)
+	if err != nil {
+		c.logger.Debugf("failed checking exit code of container %q, err: %s", containerID, err)
+
+		return
+	}
+
+	if !exited {
+		return
+	}
+
+	c.nodeExitCodes.record(nodeName, exitCode)
+
+	if exitCode != 0 {
+		c.logger.Warnf(
+			"node %q (container %q) exited with code %d",
+			nodeName,
+			containerID,
+			exitCode,
+		)
+
+		return
+	}
+
+	c.logger.Infof("node %q (container %q) exited cleanly", nodeName, containerID)
+}
+
+// reportNodeExitCodes records the exit codes of all exited node containers and logs them as a
+// summary, at warn level if any node exited non-zero. This is meant to be called during shutdown
+// (after stopping nodes) so it uses its own context rather than the clabernetes context.
+func (c *clabernetes) reportNodeExitCodes() {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultDockerStopTimeout)
+	defer cancel()
+
+	summaries, err := listContainers(ctx, true, containerlabLabLabel)
+	if err != nil {
+		c.logger.Warnf("failed listing node containers, not reporting exit codes, err: %s", err)
+
+		return
+	}
+
+	var exitedContainerIDs []string
+
+	for _, summary := range summaries {
+		if summary.State == containerStateExited || summary.State == containerStateDead {
+			exitedContainerIDs = append(exitedContainerIDs, summary.ID)
+		}
+	}
+
+	inspected, err := inspectContainers(ctx, exitedContainerIDs)
+	if err != nil {
+		c.logger.Warnf("failed inspecting node containers, not reporting exit codes, err: %s", err)
+
+		return
+	}
+
+	for _, result := range inspected {
+		c.nodeExitCodes.record(inspectedNodeName(result), result.State.ExitCode)
+	}
+
+	exitCodes := c.nodeExitCodes.snapshot()
+	if len(exitCodes) == 0 {
+		return
+	}
+
+	for _, exitCode := range exitCodes {
+		if exitCode != 0 {
+			c.logger.Warnf("node exit codes: %s", formatExitCodes(exitCodes))
+
+			return
+		}
+	}
+
+	c.logger.Infof("node exit codes: %s", formatExitCodes(exitCodes))
+}
@@ -0,0 +1,87 @@
+package launcher_test
+
+import (
+	"context"
+	"testing"
+
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+func TestContainerExitCode(t *testing.T) {
+	cases := []struct {
+		name             string
+		output           string
+		cmdErr           error
+		expectedNodeName string
+		expectedExited   bool
+		expectedExitCode int
+		expectErr        bool
+	}{
+		{
+			name: "exited-cleanly",
+			output: `[{"Id":"abc123def","Name":"/clab-clabernetes-srl1-srl1",` +
+				`"State":{"Status":"exited","ExitCode":0},` +
+				`"Config":{"Labels":{"clab-node-name":"srl1"}}}]`,
+			expectedNodeName: "srl1",
+			expectedExited:   true,
+		},
+		{
+			name: "crashed",
+			output: `[{"Id":"abc123def","Name":"/clab-clabernetes-srl1-srl1",` +
+				`"State":{"Status":"exited","ExitCode":139},` +
+				`"Config":{"Labels":{"clab-node-name":"srl1"}}}]`,
+			expectedNodeName: "srl1",
+			expectedExited:   true,
+			expectedExitCode: 139,
+		},
+		{
+			name: "running",
+			output: `[{"Id":"abc123def","Name":"/clab-clabernetes-srl1-srl1",` +
+				`"State":{"Status":"running","Running":true,"ExitCode":0},` +
+				`"Config":{"Labels":{"clab-node-name":"srl1"}}}]`,
+			expectedNodeName: "srl1",
+		},
+		{
+			name:      "docker-error",
+			cmdErr:    errFakeCommand,
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				fakeRunner := newFakeCommandRunner()
+
+				fakeRunner.outputs["docker inspect abc123"] = []byte(testCase.output)
+				fakeRunner.results["docker inspect abc123"] = testCase.cmdErr
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				nodeName, exited, exitCode, err := claberneteslauncher.ContainerExitCode(
+					context.Background(),
+					"abc123",
+				)
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+
+				if nodeName != testCase.expectedNodeName {
+					clabernetestesthelper.FailOutput(t, nodeName, testCase.expectedNodeName)
+				}
+
+				if exited != testCase.expectedExited {
+					clabernetestesthelper.FailOutput(t, exited, testCase.expectedExited)
+				}
+
+				if exitCode != testCase.expectedExitCode {
+					clabernetestesthelper.FailOutput(t, exitCode, testCase.expectedExitCode)
+				}
+			})
+	}
+}
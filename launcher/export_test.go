@@ -0,0 +1,1133 @@
+package launcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"regexp"
+	"slices"
+	"time"
+
+	claberneteslogging "github.com/srl-labs/clabernetes/logging"
+)
+
+// CommandRunner exposes the commandRunner interface for tests.
+type CommandRunner = commandRunner
+
+// SetCommandRunner swaps the package command runner for the given runner, returning a func that
+// restores the original runner.
+func SetCommandRunner(r CommandRunner) func() {
+	original := runner
+	originalRetryInterval := dockerStartRetryInterval
+
+	runner = r
+	dockerStartRetryInterval = time.Duration(0)
+
+	return func() {
+		runner = original
+		dockerStartRetryInterval = originalRetryInterval
+	}
+}
+
+// MaxDockerLaunchAttempts exposes maxDockerLaunchAttempts for tests.
+const MaxDockerLaunchAttempts = maxDockerLaunchAttempts
+
+// StartDocker exposes startDocker for tests.
+func StartDocker(ctx context.Context, logger io.Writer) error {
+	return startDocker(ctx, logger)
+}
+
+// DaemonConfig exposes daemonConfig for tests.
+type DaemonConfig = daemonConfig
+
+// RenderDaemonConfig exposes renderDaemonConfig for tests.
+func RenderDaemonConfig(config *DaemonConfig) ([]byte, error) {
+	return renderDaemonConfig(config)
+}
+
+// ParseDaemonFeatures exposes parseDaemonFeatures for tests.
+func ParseDaemonFeatures(features string) (map[string]bool, error) {
+	return parseDaemonFeatures(features)
+}
+
+// NewLogRingBuffer exposes newLogRingBuffer for tests.
+func NewLogRingBuffer(size int) interface {
+	Write(p []byte) (int, error)
+	Lines(n int) []string
+} {
+	return newLogRingBuffer(size)
+}
+
+// ParseInsecureRegistries exposes parseInsecureRegistries for tests.
+func ParseInsecureRegistries(insecureRegistries string) []string {
+	return parseInsecureRegistries(&claberneteslogging.FakeInstance{}, insecureRegistries)
+}
+
+// LoadInsecureRegistries exposes loadInsecureRegistries for tests.
+func LoadInsecureRegistries() ([]string, error) {
+	return loadInsecureRegistries(&claberneteslogging.FakeInstance{})
+}
+
+// RegistryConfigIssues exposes registryConfigIssues for tests.
+func RegistryConfigIssues(insecureRegistries []string, mirror string) []string {
+	return registryConfigIssues(insecureRegistries, mirror)
+}
+
+// ParseStorageOpts exposes parseStorageOpts for tests.
+func ParseStorageOpts(storageDriver, storageOpts string) []string {
+	return parseStorageOpts(&claberneteslogging.FakeInstance{}, storageDriver, storageOpts)
+}
+
+// RedactDaemonConfig exposes redactDaemonConfig for tests.
+func RedactDaemonConfig(content []byte) ([]byte, error) {
+	return redactDaemonConfig(content)
+}
+
+// ValidateRestartPolicy exposes validateRestartPolicy for tests.
+func ValidateRestartPolicy(restartPolicy string) error {
+	return validateRestartPolicy(restartPolicy)
+}
+
+// WriteDaemonConfig exposes writeDaemonConfig for tests.
+func WriteDaemonConfig(path string, rendered []byte) error {
+	return writeDaemonConfig(&claberneteslogging.FakeInstance{}, path, rendered)
+}
+
+// AssertDaemonConfig exposes assertDaemonConfig for tests.
+func AssertDaemonConfig(path string, rendered []byte) error {
+	return assertDaemonConfig(&claberneteslogging.FakeInstance{}, path, rendered)
+}
+
+// ParseNodeLogDestinations exposes parseNodeLogDestinations for tests.
+func ParseNodeLogDestinations(destinations string) ([]string, error) {
+	return parseNodeLogDestinations(destinations)
+}
+
+// ParseDaemonRuntimes exposes parseDaemonRuntimes for tests, returning the runtime paths by name.
+func ParseDaemonRuntimes(runtimes string) (map[string]string, error) {
+	parsedRuntimes, err := parseDaemonRuntimes(runtimes)
+	if err != nil {
+		return nil, err
+	}
+
+	runtimePaths := make(map[string]string, len(parsedRuntimes))
+
+	for name, runtime := range parsedRuntimes {
+		runtimePaths[name] = runtime.Path
+	}
+
+	return runtimePaths, nil
+}
+
+// CheckRunMode exposes checkRunMode for tests.
+func CheckRunMode(privileged, rootless bool, euid int) error {
+	return checkRunMode(privileged, rootless, euid)
+}
+
+// TailTimestampedLog writes the given "docker logs --timestamps" output through a timestamp
+// stripping writer backed by the log tail state at statePath, saves the state, and returns what
+// was written along with the resulting resume timestamp of the container.
+func TailTimestampedLog(
+	statePath, containerName string,
+	logs []byte,
+) (written []byte, since string, err error) {
+	state, err := loadLogTailState(statePath)
+	if err != nil {
+		return nil, "", err
+	}
+
+	out := &bytes.Buffer{}
+
+	_, err = newTimestampStrippingWriter(out, state, containerName).Write(logs)
+	if err != nil {
+		return nil, "", err
+	}
+
+	err = state.save()
+	if err != nil {
+		return nil, "", err
+	}
+
+	reloadedState, err := loadLogTailState(statePath)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return out.Bytes(), reloadedState.since(containerName), nil
+}
+
+// ListNodeAddresses exposes listNodeAddresses (and writeNodeAddresses) for tests, returning the
+// rendered output.
+func ListNodeAddresses(ctx context.Context, asJSON bool) ([]byte, error) {
+	nodeAddresses, err := listNodeAddresses(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &bytes.Buffer{}
+
+	err = writeNodeAddresses(out, nodeAddresses, asJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+// ImagePullCandidates exposes imagePullCandidates for tests.
+func ImagePullCandidates(image, mirror string) []string {
+	return imagePullCandidates(image, mirror)
+}
+
+// PullImage exposes pullImage for tests.
+func PullImage(ctx context.Context, image, mirror string) error {
+	return pullImage(ctx, &claberneteslogging.FakeInstance{}, image, mirror)
+}
+
+// StartupTimingsSummary runs the given (no-op) finished phases and then returns the summary from
+// within the still running phase.
+func StartupTimingsSummary(finishedPhases []string, runningPhase string) string {
+	timings := newStartupTimings()
+
+	for _, phase := range finishedPhases {
+		timings.run(phase, func() {})
+	}
+
+	var summary string
+
+	timings.run(runningPhase, func() {
+		summary = timings.summary()
+	})
+
+	return summary
+}
+
+// StartupTimingsSubPhases runs the given sub phases with runE within the given phase, the sub
+// phase named failingSubPhase returning ErrFailingSubPhase, and returns the sub phase errors and
+// the summary once the phase finished.
+func StartupTimingsSubPhases(
+	phase string,
+	subPhases []string,
+	failingSubPhase string,
+) ([]error, string) {
+	timings := newStartupTimings()
+
+	errs := make([]error, len(subPhases))
+
+	timings.run(phase, func() {
+		for idx, subPhase := range subPhases {
+			errs[idx] = timings.runE(subPhase, func() error {
+				if subPhase == failingSubPhase {
+					return ErrFailingSubPhase
+				}
+
+				return nil
+			})
+		}
+	})
+
+	return errs, timings.summary()
+}
+
+// ErrFailingSubPhase is returned by the failing sub phase in StartupTimingsSubPhases.
+var ErrFailingSubPhase = errors.New("sub phase failed") //nolint:gochecknoglobals
+
+// BuildDaemonConfig exposes buildDaemonConfig for tests.
+func BuildDaemonConfig(ctx context.Context) (*daemonConfig, error) {
+	return buildDaemonConfig(ctx, &claberneteslogging.FakeInstance{})
+}
+
+// WriteJSONFileLogRecord exposes writeJSONFileLogRecord for tests.
+func WriteJSONFileLogRecord(w io.Writer, line []byte, since string) (string, error) {
+	return writeJSONFileLogRecord(w, line, since)
+}
+
+// InjectNodeEnv loads the per node env and applies it to the topology at path, returning the
+// redacted (loggable) env of each node that env was injected into.
+func InjectNodeEnv(path string) (map[string][]string, error) {
+	nodeEnv, err := loadNodeEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	injected := map[string][]string{}
+
+	err = patchTopologyNodes(path, func(nodeName string, node map[string]any) error {
+		env, ok := nodeEnv[nodeName]
+		if !ok {
+			return nil
+		}
+
+		injected[nodeName] = redactedEnv(env)
+
+		applyNodeEnv(node, env)
+
+		return nil
+	})
+
+	return injected, err
+}
+
+// InjectNodeDNS loads the node dns config and applies it to all nodes in the topology at path.
+func InjectNodeDNS(path string) error {
+	searchDomains, options, err := loadNodeDNS()
+	if err != nil {
+		return err
+	}
+
+	return patchTopologyNodes(path, func(_ string, node map[string]any) error {
+		applyNodeDNS(node, searchDomains, options)
+
+		return nil
+	})
+}
+
+// PingDocker exposes pingDocker for tests.
+func PingDocker(ctx context.Context, dockerHost string) error {
+	return pingDocker(ctx, dockerHost)
+}
+
+// DockerWatchdogObserve feeds the given ping results to a docker watchdog with the given failure
+// threshold, returning the result of each observation.
+func DockerWatchdogObserve(failureThreshold int, pingErrs []error) []bool {
+	watchdog := &dockerWatchdog{failureThreshold: failureThreshold}
+
+	acted := make([]bool, len(pingErrs))
+
+	for idx, pingErr := range pingErrs {
+		acted[idx] = watchdog.observe(pingErr)
+	}
+
+	return acted
+}
+
+// StopNodeContainer loads the node stop config and stops the given container with the config of
+// the given node.
+func StopNodeContainer(ctx context.Context, nodeName, containerID string) error {
+	nodeStopConfigs, err := loadNodeStopConfig()
+	if err != nil {
+		return err
+	}
+
+	return stopContainer(ctx, containerID, nodeStopConfigs[nodeName])
+}
+
+// WaitForLogLine exposes waitForLogLine for tests.
+func WaitForLogLine(
+	ctx context.Context,
+	containerID string,
+	pattern *regexp.Regexp,
+	timeout time.Duration,
+) error {
+	return waitForLogLine(ctx, containerID, pattern, timeout)
+}
+
+// ServeLogFile exposes serveLogFile for tests, resolving node names with the given mapping of node
+// name to log file.
+func ServeLogFile(
+	w http.ResponseWriter,
+	r *http.Request,
+	workDir string,
+	nodeLogFiles map[string]string,
+	pollInterval time.Duration,
+) error {
+	return serveLogFile(w, r, workDir, mapNodeLogFileResolver(nodeLogFiles), pollInterval)
+}
+
+func mapNodeLogFileResolver(nodeLogFiles map[string]string) nodeLogFileResolver {
+	return func(nodeName string) (string, bool) {
+		path, ok := nodeLogFiles[nodeName]
+
+		return path, ok
+	}
+}
+
+// TopologyConfigHash exposes topologyConfigHash for tests.
+func TopologyConfigHash(content []byte) (string, error) {
+	return topologyConfigHash(content)
+}
+
+// ContainerConfigHash exposes containerConfigHash for tests.
+func ContainerConfigHash(ctx context.Context, containerID string) (string, error) {
+	return containerConfigHash(ctx, containerID)
+}
+
+// DecideExistingNodeAction exposes decideExistingNodeAction for tests, returning the action as
+// "deploy", "reuse", or "recreate".
+func DecideExistingNodeAction(
+	policy, configHash string,
+	nodeNames []string,
+	existingConfigHashes map[string]string,
+) (string, error) {
+	action, err := decideExistingNodeAction(policy, configHash, nodeNames, existingConfigHashes)
+
+	return map[existingNodeAction]string{
+		existingNodeActionDeploy:   "deploy",
+		existingNodeActionReuse:    "reuse",
+		existingNodeActionRecreate: "recreate",
+	}[action], err
+}
+
+// TopologyNodeImages exposes topologyNodeImages for tests.
+func TopologyNodeImages(path string) ([]string, error) {
+	return topologyNodeImages(path)
+}
+
+// LogStreamEndedExpectedly exposes logStreamEndedExpectedly for tests.
+func LogStreamEndedExpectedly(ctx context.Context, containerID string, err error) bool {
+	return logStreamEndedExpectedly(ctx, containerID, err)
+}
+
+// ExpectedNodes exposes expectedNodes for tests.
+func ExpectedNodes(nodeName string) ([]string, int, error) {
+	return expectedNodes(nodeName)
+}
+
+// ReadyzStatus exposes readyzStatus for tests.
+func ReadyzStatus(
+	ctx context.Context,
+	degradedReason string,
+	startupComplete bool,
+	expectedCount int,
+	oomKilledNodes []string,
+	exitCodes map[string]int,
+) (int, string) {
+	return readyzStatus(
+		ctx,
+		degradedReason,
+		startupComplete,
+		expectedCount,
+		oomKilledNodes,
+		exitCodes,
+		countRunningNodeContainers,
+	)
+}
+
+// ContainerExitCode exposes containerExitCode for tests.
+func ContainerExitCode(ctx context.Context, containerID string) (string, bool, int, error) {
+	return containerExitCode(ctx, containerID)
+}
+
+// ContainerOOMKilled exposes containerOOMKilled for tests.
+func ContainerOOMKilled(ctx context.Context, containerID string) (string, bool, error) {
+	return containerOOMKilled(ctx, containerID)
+}
+
+// NewRateLimitedWriter exposes newRateLimitedWriter for tests.
+func NewRateLimitedWriter(
+	w io.Writer,
+	linesPerSecond int,
+	now func() time.Time,
+	report func(suppressed int),
+) io.Writer {
+	return newRateLimitedWriter(w, linesPerSecond, now, report)
+}
+
+// RestartDockerTo exposes restartDocker for tests.
+func RestartDockerTo(ctx context.Context, w io.Writer) error {
+	return restartDocker(ctx, w)
+}
+
+// NewReceiveTimestampWriter exposes newReceiveTimestampWriter for tests.
+func NewReceiveTimestampWriter(w io.Writer, now func() time.Time) io.Writer {
+	return newReceiveTimestampWriter(w, now)
+}
+
+// ParseDockerSubcommands exposes parseDockerSubcommands for tests.
+func ParseDockerSubcommands(help []byte) map[string]bool {
+	return parseDockerSubcommands(help)
+}
+
+// MissingDockerSubcommands exposes missingDockerSubcommands for tests, checking the requirements
+// of the current configuration and returning the missing subcommands and whether each is optional.
+func MissingDockerSubcommands(available map[string]bool) map[string]bool {
+	missing := map[string]bool{}
+
+	for _, requirement := range missingDockerSubcommands(
+		available,
+		dockerSubcommandRequirements(),
+	) {
+		missing[requirement.subcommand] = requirement.optional
+	}
+
+	return missing
+}
+
+// ContainerSummary is an alias to containerSummary for tests.
+type ContainerSummary = containerSummary
+
+// ListContainers exposes listContainers for tests.
+func ListContainers(
+	ctx context.Context,
+	all bool,
+	labelFilters ...string,
+) ([]ContainerSummary, error) {
+	return listContainers(ctx, all, labelFilters...)
+}
+
+// RemoveNodeContainers exposes removeNodeContainers for tests.
+func RemoveNodeContainers(
+	ctx context.Context,
+	containerIDs []string,
+	grace time.Duration,
+	snapshotLogs bool,
+) error {
+	return removeNodeContainers(
+		ctx,
+		&claberneteslogging.FakeInstance{},
+		&claberneteslogging.FakeInstance{},
+		containerIDs,
+		grace,
+		snapshotLogs,
+	)
+}
+
+// OverlayNetworkCreateArgs loads the overlay network config and returns its docker network create
+// arguments for tests.
+func OverlayNetworkCreateArgs() ([]string, error) {
+	config, err := loadOverlayNetworkConfig()
+	if err != nil || config == nil {
+		return nil, err
+	}
+
+	return config.createArgs(), nil
+}
+
+// EnsureNetwork loads the overlay network config and exposes ensureNetwork for tests.
+func EnsureNetwork(ctx context.Context) error {
+	config, err := loadOverlayNetworkConfig()
+	if err != nil {
+		return err
+	}
+
+	return ensureNetwork(ctx, &claberneteslogging.FakeInstance{}, config)
+}
+
+// ConnectNetwork exposes connectNetwork for tests.
+func ConnectNetwork(ctx context.Context, name string, containerIDs []string) error {
+	return connectNetwork(ctx, &claberneteslogging.FakeInstance{}, name, containerIDs)
+}
+
+// DescribeNodeOutput exposes describeNode for tests, returning the rendered output.
+func DescribeNodeOutput(
+	ctx context.Context,
+	nodeName string,
+	asJSON bool,
+	format string,
+) ([]byte, error) {
+	out := &bytes.Buffer{}
+
+	err := describeNode(ctx, out, nodeName, asJSON, format)
+
+	return out.Bytes(), err
+}
+
+// NewSharedLogWriterSources returns count sources of a single shared log writer wrapping w for
+// tests.
+func NewSharedLogWriterSources(w io.Writer, count int) []io.Writer {
+	shared := newSharedLogWriter(w)
+
+	sources := make([]io.Writer, count)
+
+	for idx := range sources {
+		sources[idx] = shared.source()
+	}
+
+	return sources
+}
+
+// ParseNodeLabels exposes parseNodeLabels for tests.
+func ParseNodeLabels(rawLabels string) (map[string]string, error) {
+	return parseNodeLabels(rawLabels)
+}
+
+// PatchNodeLabels exposes patchNodeLabels for tests.
+func PatchNodeLabels(path, appName string) error {
+	return patchNodeLabels(path, appName)
+}
+
+// ImagePullProgressSummary feeds the given docker pull output lines to an image pull progress and
+// returns its summary for tests.
+func ImagePullProgressSummary(lines []string) string {
+	progress := newImagePullProgress()
+
+	for _, line := range lines {
+		progress.observe(line)
+	}
+
+	return progress.summary()
+}
+
+// SetIPTablesBackend exposes setIPTablesBackend for tests.
+func SetIPTablesBackend(ctx context.Context, backend string) error {
+	return setIPTablesBackend(ctx, io.Discard, backend)
+}
+
+// ServeLogSearch exposes serveLogSearch for tests.
+func ServeLogSearch(w http.ResponseWriter, r *http.Request, workDir string) error {
+	return serveLogSearch(w, r, workDir, nil)
+}
+
+// ExecPostConvergenceHook exposes execPostConvergenceHook for tests.
+func ExecPostConvergenceHook(
+	ctx context.Context,
+	logger io.Writer,
+	path string,
+	nodeAddrs map[string]string,
+) error {
+	return execPostConvergenceHook(ctx, logger, path, nodeAddrs)
+}
+
+// ValidatePostConvergenceHookPolicy exposes validatePostConvergenceHookPolicy for tests.
+func ValidatePostConvergenceHookPolicy(policy string) error {
+	return validatePostConvergenceHookPolicy(policy)
+}
+
+// GetContainerAddrs exposes getContainerAddrs for tests.
+func GetContainerAddrs(ctx context.Context, containerIDs []string) (map[string]string, error) {
+	return getContainerAddrs(ctx, containerIDs)
+}
+
+// WaitPollInterval exposes waitPollInterval for tests.
+func WaitPollInterval() time.Duration {
+	return waitPollInterval()
+}
+
+// JitteredPollInterval exposes jitteredPollInterval for tests.
+func JitteredPollInterval(interval time.Duration) time.Duration {
+	return jitteredPollInterval(interval)
+}
+
+// ValidateWaitPollInterval exposes validateWaitPollInterval for tests.
+func ValidateWaitPollInterval(interval string) error {
+	return validateWaitPollInterval(interval)
+}
+
+// CopyFromContainer exposes copyFromContainer for tests.
+func CopyFromContainer(ctx context.Context, containerID, src, dst string) error {
+	return copyFromContainer(ctx, containerID, src, dst)
+}
+
+// CopyFromContainerTarGz exposes copyFromContainerTarGz for tests.
+func CopyFromContainerTarGz(ctx context.Context, containerID, src, outputPath string) error {
+	return copyFromContainerTarGz(ctx, containerID, src, outputPath)
+}
+
+// NodeStatesAfterEvents seeds node states with the given summaries then applies the given json
+// docker events to it, returning the resulting "state/health" of each container and the running
+// count.
+func NodeStatesAfterEvents(
+	summaries []ContainerSummary,
+	events []string,
+) (map[string]string, int, error) {
+	states := newNodeStates()
+
+	states.reset(summaries)
+
+	for _, rawEvent := range events {
+		var event dockerEvent
+
+		err := json.Unmarshal([]byte(rawEvent), &event)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		states.apply(event)
+	}
+
+	result := map[string]string{}
+
+	for containerID, state := range states.containers {
+		result[containerID] = state.State + "/" + state.Health
+	}
+
+	return result, states.runningCount(), nil
+}
+
+// ShutdownStopTimeouts loads the node stop config and container stop timeout and returns the
+// stop timeout of each node that would be stopped during shutdown, along with the total budget.
+func ShutdownStopTimeouts(nodeNames []string) (map[string]time.Duration, time.Duration, error) {
+	nodeStopConfigs, err := loadNodeStopConfig()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defaultTimeout, err := loadContainerStopTimeout()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	stopConfigs := shutdownStopConfigs(nodeStopConfigs, nodeNames, defaultTimeout)
+
+	timeouts := make(map[string]time.Duration, len(stopConfigs))
+
+	for nodeName, stopConfig := range stopConfigs {
+		timeouts[nodeName] = stopConfig.timeout
+	}
+
+	return timeouts, shutdownStopBudget(stopConfigs), nil
+}
+
+// ValidateDaemonConfig exposes validateDaemonConfig for tests.
+func ValidateDaemonConfig(content []byte) error {
+	return validateDaemonConfig(content)
+}
+
+// WaitContainerReachable exposes waitContainerReachable for tests.
+func WaitContainerReachable(ctx context.Context, addr string, timeout time.Duration) error {
+	return waitContainerReachable(ctx, addr, timeout)
+}
+
+// InjectNodeResources loads the per node resources and applies them to the topology at path.
+func InjectNodeResources(path string) error {
+	allNodeResources, err := loadNodeResources()
+	if err != nil {
+		return err
+	}
+
+	return patchTopologyNodes(path, func(nodeName string, node map[string]any) error {
+		resources, ok := allNodeResources[nodeName]
+		if ok {
+			applyNodeResources(node, resources)
+		}
+
+		return nil
+	})
+}
+
+// InjectNodeSysctls loads the per node sysctls and applies them to the topology at path.
+func InjectNodeSysctls(path string) error {
+	allNodeSysctls, err := loadNodeSysctls()
+	if err != nil {
+		return err
+	}
+
+	return patchTopologyNodes(path, func(nodeName string, node map[string]any) error {
+		sysctls, ok := allNodeSysctls[nodeName]
+		if ok {
+			return applyNodeSysctls(node, sysctls)
+		}
+
+		return nil
+	})
+}
+
+// SetContainerPidsLimit exposes setContainerPidsLimit for tests.
+func SetContainerPidsLimit(ctx context.Context, containerID string, pidsLimit int) error {
+	return setContainerPidsLimit(ctx, containerID, pidsLimit)
+}
+
+// AppliedResources exposes appliedResources for tests.
+func AppliedResources(ctx context.Context, containerID string) (string, error) {
+	return appliedResources(ctx, containerID)
+}
+
+// TailNodeLogsTo exposes tailNodeLogs for tests.
+func TailNodeLogsTo(
+	ctx context.Context,
+	w io.Writer,
+	nodeNames []string,
+	prefixFormat string,
+	colorize bool,
+) error {
+	return tailNodeLogs(ctx, w, nodeNames, prefixFormat, colorize)
+}
+
+// WaitAllNodesReady exposes waitAllNodesReady for tests, returning the outcome of each node.
+func WaitAllNodesReady(
+	ctx context.Context,
+	nodeNames []string,
+	timeout time.Duration,
+	failFast bool,
+) (map[string]string, error) {
+	statuses, err := waitAllNodesReady(ctx, nodeNames, timeout, failFast)
+
+	outcomes := make(map[string]string, len(statuses))
+
+	for nodeName, status := range statuses {
+		outcomes[nodeName] = status.outcome()
+	}
+
+	return outcomes, err
+}
+
+// ValidatePartialFailurePolicy exposes validatePartialFailurePolicy for tests.
+func ValidatePartialFailurePolicy(policy string) error {
+	return validatePartialFailurePolicy(policy)
+}
+
+// BuildImage exposes buildImage for tests.
+func BuildImage(ctx context.Context, contextDir, dockerfile, tag string) (string, error) {
+	return buildImage(ctx, &claberneteslogging.FakeInstance{}, contextDir, dockerfile, tag)
+}
+
+// TailContainerLogsInWorkDir starts tailing the logs of the given containers with a minimal
+// launcher using the given work directory, returning the per container log files.
+func TailContainerLogsInWorkDir(
+	ctx context.Context,
+	workDir string,
+	containerIDs []string,
+) (map[string]string, error) {
+	c := &clabernetes{
+		ctx:               ctx,
+		logger:            &claberneteslogging.FakeInstance{},
+		nodeLogger:        &claberneteslogging.FakeInstance{},
+		workDir:           workDir,
+		nodeLogBuffers:    newLogRingBuffers(defaultNodeLogBufferLines),
+		containerLogTails: newContainerLogTails(),
+	}
+
+	return c.tailContainerLogs(containerIDs)
+}
+
+// ContainerStreamNames returns the id and log stream name of the given containers, in the order
+// containerStreams groups them.
+func ContainerStreamNames(ctx context.Context, containerIDs []string) [][2]string {
+	streams := containerStreams(ctx, containerIDs)
+
+	names := make([][2]string, len(streams))
+
+	for idx, stream := range streams {
+		names[idx] = [2]string{stream.ID, stream.Name}
+	}
+
+	return names
+}
+
+// GetContainerIDForNodeName exposes getContainerIDForNodeName for testing.
+func GetContainerIDForNodeName(
+	ctx context.Context,
+	nodeName string,
+	retryFor time.Duration,
+) (string, error) {
+	return getContainerIDForNodeName(ctx, nodeName, false, retryFor)
+}
+
+// DaemonConfigExists exposes daemonConfigExists for testing.
+func DaemonConfigExists() bool {
+	return daemonConfigExists()
+}
+
+// DockerDaemonConfigPath exposes dockerDaemonConfigPath for testing.
+func DockerDaemonConfigPath() string {
+	return dockerDaemonConfigPath()
+}
+
+// DrainNodeOutput runs drainNode, returning what it wrote.
+func DrainNodeOutput(
+	ctx context.Context,
+	workDir, nodeName string,
+	skipLogs bool,
+) ([]byte, error) {
+	out := &bytes.Buffer{}
+
+	err := drainNode(ctx, out, workDir, nodeName, skipLogs)
+
+	return out.Bytes(), err
+}
+
+// RunSelfTest exposes runSelfTest for tests.
+func RunSelfTest(ctx context.Context, image string) error {
+	return runSelfTest(ctx, &claberneteslogging.FakeInstance{}, image)
+}
+
+// LoadNodeLaunchWorkers exposes loadNodeLaunchWorkers for tests.
+func LoadNodeLaunchWorkers() (int, error) {
+	return loadNodeLaunchWorkers()
+}
+
+// Degrade runs degrade on a bare launcher, returning whether it degraded and the resulting
+// degraded reason.
+func Degrade(reason string) (bool, string) {
+	c := &clabernetes{
+		logger: &claberneteslogging.FakeInstance{},
+	}
+
+	degraded := c.degrade(reason)
+
+	return degraded, c.degraded()
+}
+
+// S3URIEncode exposes s3URIEncode for tests.
+func S3URIEncode(s string) string {
+	return s3URIEncode(s)
+}
+
+// S3PutObject puts the file at filePath at the given key with an s3 client configured from the
+// environment.
+func S3PutObject(ctx context.Context, key, filePath string) error {
+	client, err := newS3ClientFromEnv()
+	if err != nil {
+		return err
+	}
+
+	return client.putObject(ctx, key, filePath)
+}
+
+// UploadLogs runs uploadLogs for a launcher of the given node with the given work directory and
+// container log files.
+func UploadLogs(workDir, nodeName string, containerLogFiles map[string]string) {
+	c := &clabernetes{
+		logger:            &claberneteslogging.FakeInstance{},
+		nodeName:          nodeName,
+		workDir:           workDir,
+		containerLogFiles: containerLogFiles,
+	}
+
+	c.uploadLogs()
+}
+
+// LastContainerState exposes lastContainerState for tests.
+func LastContainerState(ctx context.Context, containerID string) (state, health string) {
+	return lastContainerState(ctx, containerID)
+}
+
+// ServeLauncherHTTP serves the given request with the launcher http server mux of a minimal
+// launcher using the given work directory, with a node log buffer holding the given lines for each
+// node.
+func ServeLauncherHTTP(
+	w http.ResponseWriter,
+	r *http.Request,
+	workDir string,
+	nodeLogLines map[string][]string,
+) {
+	c := &clabernetes{
+		logger:         &claberneteslogging.FakeInstance{},
+		workDir:        workDir,
+		nodeLogBuffers: newLogRingBuffers(defaultNodeLogBufferLines),
+	}
+
+	for nodeName, lines := range nodeLogLines {
+		buffer := c.nodeLogBuffers.add(nodeName)
+
+		for _, line := range lines {
+			_, _ = buffer.Write([]byte(line + "\n"))
+		}
+	}
+
+	c.newHTTPMux().ServeHTTP(w, r)
+}
+
+// DockerHostIsExternal exposes dockerHostIsExternal for tests.
+func DockerHostIsExternal() bool {
+	return dockerHostIsExternal()
+}
+
+// NodeContainerIndex is nodeContainerIndex exposed for tests.
+type NodeContainerIndex = nodeContainerIndex
+
+// NewNodeContainerIndex exposes newNodeContainerIndex for tests.
+func NewNodeContainerIndex(ctx context.Context) (*NodeContainerIndex, error) {
+	return newNodeContainerIndex(ctx)
+}
+
+// ResolveNodeContainer exposes nodeContainerIndex.resolve for tests.
+func ResolveNodeContainer(
+	ctx context.Context,
+	index *NodeContainerIndex,
+	nodeName string,
+) (string, error) {
+	return index.resolve(ctx, nodeName)
+}
+
+// NodeContainerIndexNodeNames exposes nodeContainerIndex.nodeNames for tests, sorted.
+func NodeContainerIndexNodeNames(index *NodeContainerIndex) []string {
+	return slices.Sorted(slices.Values(index.nodeNames()))
+}
+
+// ContainerLogTails is containerLogTails exposed for tests.
+type ContainerLogTails = containerLogTails
+
+// NewContainerLogTails exposes newContainerLogTails for tests.
+func NewContainerLogTails() *ContainerLogTails {
+	return newContainerLogTails()
+}
+
+// AddContainerLogTail exposes containerLogTails.add for tests, the returned channel receives the
+// context of each (re)start of the tail.
+func AddContainerLogTail(
+	ctx context.Context,
+	tails *ContainerLogTails,
+	containerID string,
+) <-chan context.Context {
+	started := make(chan context.Context, 8)
+
+	tails.add(ctx, containerID, func(tailCtx context.Context) {
+		started <- tailCtx
+	})
+
+	return started
+}
+
+// StopContainerLogTail exposes containerLogTails.stop for tests.
+func StopContainerLogTail(tails *ContainerLogTails, containerID string) bool {
+	return tails.stop(containerID)
+}
+
+// ResumeContainerLogTail exposes containerLogTails.resume for tests.
+func ResumeContainerLogTail(tails *ContainerLogTails, containerID string) bool {
+	return tails.resume(containerID)
+}
+
+// UpdateTailsOnContainerEvents runs updateTailsOnContainerEvents with a minimal launcher updating
+// the given container log tails.
+func UpdateTailsOnContainerEvents(ctx context.Context, tails *ContainerLogTails) {
+	c := &clabernetes{
+		ctx:               ctx,
+		logger:            &claberneteslogging.FakeInstance{},
+		containerLogTails: tails,
+		oomKills:          newOOMKills(),
+		nodeExitCodes:     newNodeExitCodes(),
+	}
+
+	c.updateTailsOnContainerEvents()
+}
+
+// RunDockerHooks runs the pre and post docker hooks with a minimal launcher using the given
+// logger.
+func RunDockerHooks(ctx context.Context, logger claberneteslogging.Instance) {
+	c := &clabernetes{
+		ctx:    ctx,
+		logger: logger,
+	}
+
+	c.runPreDockerHook()
+	c.runPostDockerHook()
+}
+
+// PrepareWorkDir prepares the work directory of a minimal launcher using the configured work
+// directory (see LauncherWorkDir), returning the work directory and the paths the combined node
+// log, the log file of the given container, and the node image tarball resolve to.
+func PrepareWorkDir(containerLogFileName string) (string, map[string]string) {
+	c := &clabernetes{
+		logger:  &claberneteslogging.FakeInstance{},
+		workDir: launcherWorkDir(),
+	}
+
+	c.prepareWorkDir()
+
+	return c.workDir, map[string]string{
+		"node-log":      c.workPath(nodeLogFileName),
+		"container-log": c.workPath(nodeLogsDirectory, containerLogFileName),
+		"image":         c.workPath(imageDirectory, imageFileName),
+	}
+}
+
+// Heartbeat runs heartbeat with a minimal launcher using the given logger.
+func Heartbeat(ctx context.Context, logger claberneteslogging.Instance) {
+	c := &clabernetes{
+		ctx:    ctx,
+		logger: logger,
+	}
+
+	c.heartbeat()
+}
+
+// LogHeartbeat runs logHeartbeat with a minimal launcher using the given logger.
+func LogHeartbeat(ctx context.Context, logger claberneteslogging.Instance) {
+	c := &clabernetes{
+		ctx:    ctx,
+		logger: logger,
+	}
+
+	c.logHeartbeat()
+}
+
+// PrepareTopology runs prepareTopology with a minimal launcher using the given work directory,
+// returning the path of the launcher's topology copy.
+func PrepareTopology(workDir string) string {
+	c := &clabernetes{
+		logger:  &claberneteslogging.FakeInstance{},
+		workDir: workDir,
+	}
+
+	c.prepareTopology()
+
+	return c.topologyPath()
+}
+
+// InjectNodeLabels runs injectNodeLabels with a minimal launcher using the given work directory
+// and logger.
+func InjectNodeLabels(workDir string, logger claberneteslogging.Instance) {
+	c := &clabernetes{
+		logger:  logger,
+		appName: "clabernetes",
+		workDir: workDir,
+	}
+
+	c.injectNodeLabels()
+}
+
+// RemoveHelperContainers exposes removeHelperContainers for tests.
+func RemoveHelperContainers(ctx context.Context) error {
+	return removeHelperContainers(ctx, &claberneteslogging.FakeInstance{})
+}
+
+// EnsureImage exposes ensureImage for tests.
+func EnsureImage(ctx context.Context, image, mirror, imagePullPolicy string) error {
+	return ensureImage(ctx, &claberneteslogging.FakeInstance{}, image, mirror, imagePullPolicy)
+}
+
+// InspectContainers exposes inspectContainers for tests.
+func InspectContainers(
+	ctx context.Context,
+	containerIDs []string,
+) (map[string]*containerInspect, error) {
+	return inspectContainers(ctx, containerIDs)
+}
+
+// InspectNetwork exposes inspectNetwork for tests.
+func InspectNetwork(ctx context.Context, name string) (*networkInspect, error) {
+	return inspectNetwork(ctx, name)
+}
+
+// GetContainerLabel exposes getContainerLabel for tests.
+func GetContainerLabel(ctx context.Context, containerID, key string) (string, error) {
+	return getContainerLabel(ctx, containerID, key)
+}
+
+// GetContainerLabels exposes getContainerLabels for tests.
+func GetContainerLabels(ctx context.Context, containerID string) (map[string]string, error) {
+	return getContainerLabels(ctx, containerID)
+}
+
+// PortMapping exposes portMapping for tests.
+type PortMapping = portMapping
+
+// GetContainerPorts exposes getContainerPorts for tests.
+func GetContainerPorts(ctx context.Context, containerID string) ([]PortMapping, error) {
+	return getContainerPorts(ctx, containerID)
+}
+
+// NonRetryableDockerError exposes nonRetryableDockerError for tests.
+func NonRetryableDockerError(stderr string) string {
+	return nonRetryableDockerError(stderr)
+}
+
+// OverlayStorageDriverError exposes overlayStorageDriverError for tests.
+func OverlayStorageDriverError(output string) string {
+	return overlayStorageDriverError(output)
+}
+
+// SetDaemonConfigStorageDriver exposes setDaemonConfigStorageDriver for tests.
+func SetDaemonConfigStorageDriver(path, storageDriver string) error {
+	return setDaemonConfigStorageDriver(path, storageDriver)
+}
+
+// ClassifyDockerError exposes classifyDockerError for tests.
+func ClassifyDockerError(err error) error {
+	return classifyDockerError(err)
+}
+
+// ValidateNodeLogPrefixFormat exposes validateNodeLogPrefixFormat for tests.
+func ValidateNodeLogPrefixFormat(format string) error {
+	return validateNodeLogPrefixFormat(format)
+}
+
+// NewPrefixWriter exposes newPrefixWriter for tests.
+func NewPrefixWriter(w io.Writer, format, nodeName, containerID string) io.Writer {
+	return newPrefixWriter(w, format, nodeName, containerID)
+}
@@ -0,0 +1,82 @@
+package launcher
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+)
+
+// heartbeat periodically logs a one line summary of the containers running in the launcher, if a
+// heartbeat interval is configured.
+func (c *clabernetes) heartbeat() {
+	rawInterval := os.Getenv(clabernetesconstants.LauncherHeartbeatInterval)
+	if rawInterval == "" {
+		return
+	}
+
+	interval, err := time.ParseDuration(rawInterval)
+	if err != nil {
+		c.logger.Warnf(
+			"failed parsing heartbeat interval %q, heartbeat disabled, err: %s",
+			rawInterval,
+			err,
+		)
+
+		return
+	}
+
+	if interval <= 0 {
+		return
+	}
+
+	c.logger.Debugf("starting container heartbeat every %s...", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+			c.logHeartbeat()
+		}
+	}
+}
+
+func (c *clabernetes) logHeartbeat() {
+	containers, err := listContainers(c.ctx, true)
+	if err != nil {
+		c.logger.Warnf("heartbeat failed listing containers, err: %s", err)
+
+		return
+	}
+
+	var runningCount int
+
+	summaries := make([]string, 0, len(containers))
+
+	for _, container := range containers {
+		if container.State == containerStateRunning {
+			runningCount++
+		}
+
+		summaries = append(
+			summaries,
+			fmt.Sprintf("%s=%s", container.Names, container.State),
+		)
+	}
+
+	sort.Strings(summaries)
+
+	c.logger.Infof(
+		"heartbeat: %d/%d containers running [%s]",
+		runningCount,
+		len(containers),
+		strings.Join(summaries, ", "),
+	)
+}
@@ -0,0 +1,143 @@
+package launcher_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+const heartbeatPsKey = "docker ps -a --no-trunc --format {{json .}}"
+
+func TestLogHeartbeat(t *testing.T) {
+	cases := []struct {
+		name             string
+		psOutput         string
+		psErr            error
+		expectedInfos    []string
+		expectedWarnings int
+	}{
+		{
+			name: "simple",
+			psOutput: `{"ID":"def456","Names":"clab-topo-srl2","State":"exited"}` + "\n" +
+				`{"ID":"abc123","Names":"clab-topo-srl1","State":"running"}` + "\n",
+			expectedInfos: []string{
+				"heartbeat: 1/2 containers running" +
+					" [clab-topo-srl1=running, clab-topo-srl2=exited]",
+			},
+		},
+		{
+			name:          "no-containers",
+			psOutput:      "",
+			expectedInfos: []string{"heartbeat: 0/0 containers running []"},
+		},
+		{
+			name:             "list-failed",
+			psErr:            errFakeCommand,
+			expectedWarnings: 1,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				fakeRunner := newFakeCommandRunner()
+
+				fakeRunner.outputs[heartbeatPsKey] = []byte(testCase.psOutput)
+				fakeRunner.results[heartbeatPsKey] = testCase.psErr
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				logger := &recordingLogger{}
+
+				claberneteslauncher.LogHeartbeat(context.Background(), logger)
+
+				clabernetestesthelper.MarshaledEqual(t, logger.infos, testCase.expectedInfos)
+
+				if len(logger.warnings) != testCase.expectedWarnings {
+					clabernetestesthelper.FailOutput(
+						t,
+						logger.warnings,
+						testCase.expectedWarnings,
+					)
+				}
+			})
+	}
+}
+
+func TestHeartbeatDisabled(t *testing.T) {
+	cases := []struct {
+		name             string
+		interval         string
+		expectedWarnings int
+	}{
+		{
+			name:     "unset",
+			interval: "",
+		},
+		{
+			name:     "zero",
+			interval: "0s",
+		},
+		{
+			name:     "negative",
+			interval: "-1s",
+		},
+		{
+			name:             "invalid",
+			interval:         "often",
+			expectedWarnings: 1,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				t.Setenv(clabernetesconstants.LauncherHeartbeatInterval, testCase.interval)
+
+				fakeRunner := newFakeCommandRunner()
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				logger := &recordingLogger{}
+
+				done := make(chan struct{})
+
+				// the context is never cancelled, a disabled heartbeat must return on its own
+				go func() {
+					defer close(done)
+
+					claberneteslauncher.Heartbeat(context.Background(), logger)
+				}()
+
+				select {
+				case <-done:
+				case <-time.After(5 * time.Second):
+					t.Fatal("expected disabled heartbeat to return")
+				}
+
+				if fakeRunner.calls[heartbeatPsKey] != 0 {
+					clabernetestesthelper.FailOutput(t, fakeRunner.calls[heartbeatPsKey], 0)
+				}
+
+				if len(logger.warnings) != testCase.expectedWarnings {
+					clabernetestesthelper.FailOutput(
+						t,
+						logger.warnings,
+						testCase.expectedWarnings,
+					)
+				}
+			})
+	}
+}
@@ -0,0 +1,81 @@
+package launcher
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteslogging "github.com/srl-labs/clabernetes/logging"
+)
+
+// helperContainerCmd returns a "docker run" command for a launcher helper container with the given
+// name and run args (image and command). Helpers are run with --rm so they remove themselves once
+// they exit, and are labeled with LabelLauncherHelper so removeHelperContainers can clean up any
+// that didn't get to exit.
+func helperContainerCmd(ctx context.Context, name string, args ...string) *exec.Cmd {
+	return exec.CommandContext( //nolint:gosec
+		ctx,
+		"docker",
+		append(
+			[]string{
+				"run",
+				"--rm",
+				"--label",
+				fmt.Sprintf(
+					"%s=%s",
+					clabernetesconstants.LabelLauncherHelper,
+					clabernetesconstants.True,
+				),
+				"--name",
+				name,
+			},
+			args...,
+		)...,
+	)
+}
+
+// removeHelperContainers force removes any launcher helper containers, running or not.
+func removeHelperContainers(ctx context.Context, logger claberneteslogging.Instance) error {
+	psCmd := exec.CommandContext(
+		ctx,
+		"docker",
+		"ps",
+		"--all",
+		"--quiet",
+		"--filter",
+		"label="+clabernetesconstants.LabelLauncherHelper,
+	)
+
+	output, err := runner.Output(psCmd)
+	if err != nil {
+		return classifyDockerError(err)
+	}
+
+	containerIDs := strings.Fields(string(bytes.TrimSpace(output)))
+	if len(containerIDs) == 0 {
+		return nil
+	}
+
+	logger.Infof("removing leftover helper containers %q...", containerIDs)
+
+	return runDockerContainerCmd(
+		ctx,
+		logger,
+		append([]string{"rm", "--force"}, containerIDs...)...,
+	)
+}
+
+// cleanupHelperContainers removes any launcher helper containers, this is best effort and runs
+// with its own deadline as it is also used on the way out, when the launcher context is done.
+func (c *clabernetes) cleanupHelperContainers() {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultDockerStopTimeout)
+	defer cancel()
+
+	err := removeHelperContainers(ctx, c.logger)
+	if err != nil {
+		c.logger.Warnf("failed removing helper containers, err: %s", err)
+	}
+}
@@ -0,0 +1,74 @@
+package launcher_test
+
+import (
+	"context"
+	"testing"
+
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+func TestRemoveHelperContainers(t *testing.T) {
+	const psKey = "docker ps --all --quiet --filter label=clabernetes/launcherHelper"
+
+	cases := []struct {
+		name          string
+		psOutput      string
+		psErr         error
+		expectedRmKey string
+		expectErr     bool
+	}{
+		{
+			name: "none",
+		},
+		{
+			name:          "leftovers",
+			psOutput:      "abc123\ndef456\n",
+			expectedRmKey: "docker container rm --force abc123 def456",
+		},
+		{
+			name:      "list-fails",
+			psErr:     errFakeCommand,
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				fakeRunner := newFakeCommandRunner()
+
+				fakeRunner.outputs[psKey] = []byte(testCase.psOutput)
+				fakeRunner.results[psKey] = testCase.psErr
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				err := claberneteslauncher.RemoveHelperContainers(context.Background())
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+
+				rmCalls := 0
+
+				for key, count := range fakeRunner.calls {
+					if key != psKey {
+						rmCalls += count
+					}
+				}
+
+				expectedRmCalls := 0
+				if testCase.expectedRmKey != "" {
+					expectedRmCalls = 1
+				}
+
+				if rmCalls != expectedRmCalls ||
+					(expectedRmCalls == 1 && fakeRunner.calls[testCase.expectedRmKey] != 1) {
+					clabernetestesthelper.FailOutput(t, fakeRunner.calls, testCase.expectedRmKey)
+				}
+			})
+	}
+}
@@ -0,0 +1,70 @@
+package launcher
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteslogging "github.com/srl-labs/clabernetes/logging"
+)
+
+// degrade marks the launcher as degraded for the given reason (reported by the readiness
+// endpoint) if LauncherHoldOnFailure is set, returning whether it did -- if it did the caller must
+// keep the launcher running rather than tearing it down.
+func (c *clabernetes) degrade(reason string) bool {
+	if !strings.EqualFold(
+		os.Getenv(clabernetesconstants.LauncherHoldOnFailure),
+		clabernetesconstants.True,
+	) {
+		return false
+	}
+
+	c.degradedReason.Store(&reason)
+
+	c.logger.Criticalf(
+		"node failure, %s is set so the launcher is held for debugging until it is shut down,"+
+			" failure: %s",
+		clabernetesconstants.LauncherHoldOnFailure,
+		reason,
+	)
+
+	return true
+}
+
+// degraded returns the reason the launcher is degraded, or an empty string if it is not.
+func (c *clabernetes) degraded() string {
+	reason := c.degradedReason.Load()
+	if reason == nil {
+		return ""
+	}
+
+	return *reason
+}
+
+// holdOnFailure holds the launcher (degraded, see degrade) on the given startup node failure until
+// it is shut down and then exits, so that the broken state can be inspected. If
+// LauncherHoldOnFailure is not set it returns right away and the caller fails as it always has.
+func (c *clabernetes) holdOnFailure(reason string) {
+	if !c.degrade(reason) {
+		return
+	}
+
+	<-c.ctx.Done()
+
+	c.logger.Criticalf("shutting down held launcher, failure: %s", reason)
+
+	claberneteslogging.GetManager().Flush()
+
+	os.Exit(clabernetesconstants.ExitCodeError)
+}
+
+// nodeFailure fails startup due to a node failure, holding the launcher first if
+// LauncherHoldOnFailure is set (see holdOnFailure).
+func (c *clabernetes) nodeFailure(format string, args ...any) {
+	reason := fmt.Sprintf(format, args...)
+
+	c.holdOnFailure(reason)
+
+	c.logger.Fatal(reason)
+}
@@ -0,0 +1,51 @@
+package launcher_test
+
+import (
+	"testing"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+func TestDegrade(t *testing.T) {
+	cases := []struct {
+		name             string
+		holdOnFailure    string
+		expectedDegraded bool
+		expectedReason   string
+	}{
+		{
+			name: "unset",
+		},
+		{
+			name:          "false",
+			holdOnFailure: "false",
+		},
+		{
+			name:             "hold",
+			holdOnFailure:    "true",
+			expectedDegraded: true,
+			expectedReason:   "node srl1 crashed",
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				t.Setenv(clabernetesconstants.LauncherHoldOnFailure, testCase.holdOnFailure)
+
+				degraded, reason := claberneteslauncher.Degrade("node srl1 crashed")
+				if degraded != testCase.expectedDegraded {
+					clabernetestesthelper.FailOutput(t, degraded, testCase.expectedDegraded)
+				}
+
+				if reason != testCase.expectedReason {
+					clabernetestesthelper.FailOutput(t, reason, testCase.expectedReason)
+				}
+			})
+	}
+}
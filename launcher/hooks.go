@@ -0,0 +1,230 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+	clabernetesutil "github.com/srl-labs/clabernetes/util"
+)
+
+const (
+	// postConvergenceHookAddressesEnv is the env var the post convergence hook receives the node
+	// addresses in, formatted as comma separated "node=address" pairs sorted by node name.
+	postConvergenceHookAddressesEnv = "CLABERNETES_NODE_ADDRESSES"
+
+	postConvergenceHookPolicyWarn   = "warn"
+	postConvergenceHookPolicyFatal  = "fatal"
+	postConvergenceHookPolicyIgnore = "ignore"
+)
+
+// runHook execs the hook script at the given path with the given extra env, writing its output
+// to the logger. An empty path is a no-op.
+func runHook(ctx context.Context, logger io.Writer, kind, path string, env ...string) error {
+	if path == "" {
+		return nil
+	}
+
+	hookCmd := exec.CommandContext(ctx, path)
+
+	if len(env) > 0 {
+		hookCmd.Env = append(os.Environ(), env...)
+	}
+
+	hookCmd.Stdout = logger
+	hookCmd.Stderr = logger
+
+	err := runner.Run(hookCmd)
+	if err != nil {
+		return fmt.Errorf(
+			"%w: failed running %s hook %q, err: %w",
+			claberneteserrors.ErrLaunch,
+			kind,
+			path,
+			err,
+		)
+	}
+
+	return nil
+}
+
+// runDockerHook execs the docker hook script at the given path, writing its output to the logger.
+// An empty path is a no-op.
+func runDockerHook(ctx context.Context, logger io.Writer, path string) error {
+	return runHook(ctx, logger, "docker", path)
+}
+
+// runPreDockerHook runs the user provided pre docker hook (if any), any failure is fatal.
+func (c *clabernetes) runPreDockerHook() {
+	hookPath := os.Getenv(clabernetesconstants.LauncherPreDockerHook)
+	if hookPath == "" {
+		return
+	}
+
+	c.logger.Infof("running pre docker hook %q...", hookPath)
+
+	err := runDockerHook(c.ctx, c.logger, hookPath)
+	if err != nil {
+		c.logger.Fatalf("failed running pre docker hook, err: %s", err)
+	}
+}
+
+// runPostDockerHook runs the user provided post docker hook (if any). Failures are only logged
+// unless LauncherPostDockerHookFatal is set.
+func (c *clabernetes) runPostDockerHook() {
+	hookPath := os.Getenv(clabernetesconstants.LauncherPostDockerHook)
+	if hookPath == "" {
+		return
+	}
+
+	c.logger.Infof("running post docker hook %q...", hookPath)
+
+	err := runDockerHook(c.ctx, c.logger, hookPath)
+	if err == nil {
+		return
+	}
+
+	if strings.EqualFold(
+		os.Getenv(clabernetesconstants.LauncherPostDockerHookFatal),
+		clabernetesconstants.True,
+	) {
+		c.logger.Fatalf("failed running post docker hook, err: %s", err)
+	}
+
+	c.logger.Warnf("failed running post docker hook, continuing, err: %s", err)
+}
+
+// validatePostConvergenceHookPolicy ensures the given post convergence hook failure policy is one
+// of "warn", "fatal", or "ignore".
+func validatePostConvergenceHookPolicy(policy string) error {
+	switch policy {
+	case postConvergenceHookPolicyWarn,
+		postConvergenceHookPolicyFatal,
+		postConvergenceHookPolicyIgnore:
+		return nil
+	default:
+		return fmt.Errorf(
+			"%w: invalid post convergence hook policy %q, must be one of %q, %q, or %q",
+			claberneteserrors.ErrLaunch,
+			policy,
+			postConvergenceHookPolicyWarn,
+			postConvergenceHookPolicyFatal,
+			postConvergenceHookPolicyIgnore,
+		)
+	}
+}
+
+// nodeAddressesEnvValue formats the given node name to address mapping as comma separated
+// "node=address" pairs sorted by node name.
+func nodeAddressesEnvValue(nodeAddrs map[string]string) string {
+	nodeNames := make([]string, 0, len(nodeAddrs))
+
+	for nodeName := range nodeAddrs {
+		nodeNames = append(nodeNames, nodeName)
+	}
+
+	sort.Strings(nodeNames)
+
+	pairs := make([]string, len(nodeNames))
+
+	for idx, nodeName := range nodeNames {
+		pairs[idx] = nodeName + "=" + nodeAddrs[nodeName]
+	}
+
+	return strings.Join(pairs, ",")
+}
+
+// execPostConvergenceHook execs the post convergence hook script at the given path with the given
+// node addresses in its env (see postConvergenceHookAddressesEnv), writing its output to the
+// logger.
+func execPostConvergenceHook(
+	ctx context.Context,
+	logger io.Writer,
+	path string,
+	nodeAddrs map[string]string,
+) error {
+	return runHook(
+		ctx,
+		logger,
+		"post convergence",
+		path,
+		postConvergenceHookAddressesEnv+"="+nodeAddressesEnvValue(nodeAddrs),
+	)
+}
+
+// runPostConvergenceHook runs the user provided post convergence hook (if any) once all nodes
+// reported ready, the readyErr being the (aggregated) error of the wait all nodes ready gate. If
+// the gate did not pass the hook is not run, which, like the hook failing, is handled according to
+// LauncherPostConvergenceHookPolicy.
+func (c *clabernetes) runPostConvergenceHook(
+	nodeStatuses map[string]*nodeReadyStatus,
+	readyErr error,
+) {
+	hookPath := os.Getenv(clabernetesconstants.LauncherPostConvergenceHook)
+	if hookPath == "" {
+		return
+	}
+
+	err := readyErr
+	if err != nil {
+		err = errors.Join(
+			fmt.Errorf(
+				"%w: not all nodes reported ready, not running post convergence hook",
+				claberneteserrors.ErrLaunch,
+			),
+			err,
+		)
+	} else {
+		containerIDs := make([]string, 0, len(nodeStatuses))
+
+		for _, status := range nodeStatuses {
+			containerIDs = append(containerIDs, status.ContainerID)
+		}
+
+		containerAddrs, addrErr := getContainerAddrs(c.ctx, containerIDs)
+		if addrErr != nil {
+			c.logger.Warnf(
+				"failed determining some node addresses for post convergence hook, err: %s",
+				addrErr,
+			)
+		}
+
+		nodeAddrs := make(map[string]string, len(nodeStatuses))
+
+		for nodeName, status := range nodeStatuses {
+			nodeAddr, ok := containerAddrs[status.ContainerID]
+			if ok {
+				nodeAddrs[nodeName] = nodeAddr
+			}
+		}
+
+		c.logger.Infof("running post convergence hook %q...", hookPath)
+
+		err = c.startupTimings.runE("launch/post-convergence-hook", func() error {
+			return execPostConvergenceHook(c.ctx, c.logger, hookPath, nodeAddrs)
+		})
+	}
+
+	if err == nil {
+		return
+	}
+
+	switch clabernetesutil.GetEnvStrOrDefault(
+		clabernetesconstants.LauncherPostConvergenceHookPolicy,
+		postConvergenceHookPolicyWarn,
+	) {
+	case postConvergenceHookPolicyFatal:
+		c.logger.Fatalf("post convergence hook failed, err: %s", err)
+	case postConvergenceHookPolicyIgnore:
+		c.logger.Debugf("post convergence hook failed, ignoring, err: %s", err)
+	default:
+		c.logger.Warnf("post convergence hook failed, continuing, err: %s", err)
+	}
+}
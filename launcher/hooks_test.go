@@ -0,0 +1,225 @@
+package launcher_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	claberneteslogging "github.com/srl-labs/clabernetes/logging"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+// recordingLogger is a fake logging instance that records the (formatted) info, warning, and fatal
+// messages logged to it -- Fatalf does not exit.
+type recordingLogger struct {
+	claberneteslogging.FakeInstance
+
+	lock     sync.Mutex
+	infos    []string
+	warnings []string
+	fatals   []string
+}
+
+func (l *recordingLogger) Infof(f string, a ...any) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.infos = append(l.infos, fmt.Sprintf(f, a...))
+}
+
+func (l *recordingLogger) Warnf(f string, a ...any) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.warnings = append(l.warnings, fmt.Sprintf(f, a...))
+}
+
+func (l *recordingLogger) Fatalf(f string, a ...any) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.fatals = append(l.fatals, fmt.Sprintf(f, a...))
+}
+
+func TestExecPostConvergenceHook(t *testing.T) {
+	cases := []struct {
+		name           string
+		script         string
+		nodeAddrs      map[string]string
+		expectedOutput string
+		expectedErr    bool
+	}{
+		{
+			name:   "addresses",
+			script: "#!/bin/sh\necho \"$CLABERNETES_NODE_ADDRESSES\"\n",
+			nodeAddrs: map[string]string{
+				"srl2": "172.20.20.3",
+				"srl1": "172.20.20.2",
+			},
+			expectedOutput: "srl1=172.20.20.2,srl2=172.20.20.3\n",
+		},
+		{
+			name:           "no-addresses",
+			script:         "#!/bin/sh\necho \"[$CLABERNETES_NODE_ADDRESSES]\"\n",
+			expectedOutput: "[]\n",
+		},
+		{
+			name:           "failing",
+			script:         "#!/bin/sh\necho failing\nexit 1\n",
+			nodeAddrs:      map[string]string{"srl1": "172.20.20.2"},
+			expectedOutput: "failing\n",
+			expectedErr:    true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				hookPath := filepath.Join(t.TempDir(), "hook.sh")
+
+				err := os.WriteFile(hookPath, []byte(testCase.script), 0o755) //nolint:gosec
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				output := &bytes.Buffer{}
+
+				err = claberneteslauncher.ExecPostConvergenceHook(
+					context.Background(),
+					output,
+					hookPath,
+					testCase.nodeAddrs,
+				)
+				if testCase.expectedErr {
+					if !errors.Is(err, claberneteserrors.ErrLaunch) {
+						t.Fatalf("expected launch error, got: %v", err)
+					}
+				} else if err != nil {
+					t.Fatal(err)
+				}
+
+				if output.String() != testCase.expectedOutput {
+					clabernetestesthelper.FailOutput(t, output.String(), testCase.expectedOutput)
+				}
+			})
+	}
+}
+
+func TestValidatePostConvergenceHookPolicy(t *testing.T) {
+	for _, policy := range []string{"warn", "fatal", "ignore"} {
+		err := claberneteslauncher.ValidatePostConvergenceHookPolicy(policy)
+		if err != nil {
+			t.Fatalf("expected policy %q to be valid, err: %s", policy, err)
+		}
+	}
+
+	err := claberneteslauncher.ValidatePostConvergenceHookPolicy("retry")
+	if !errors.Is(err, claberneteserrors.ErrLaunch) {
+		t.Fatalf("expected launch error, got: %v", err)
+	}
+}
+
+func TestRunDockerHooks(t *testing.T) {
+	preHook := "/hooks/pre-docker.sh"
+	postHook := "/hooks/post-docker.sh"
+
+	cases := []struct {
+		name             string
+		preHook          string
+		postHook         string
+		postHookFatal    string
+		failing          []string
+		expectedCalls    map[string]int
+		expectedWarnings int
+		expectedFatals   int
+	}{
+		{
+			name:          "unset",
+			expectedCalls: map[string]int{preHook: 0, postHook: 0},
+		},
+		{
+			name:          "succeeded",
+			preHook:       preHook,
+			postHook:      postHook,
+			expectedCalls: map[string]int{preHook: 1, postHook: 1},
+		},
+		{
+			name:           "pre-hook-failed",
+			preHook:        preHook,
+			failing:        []string{preHook},
+			expectedCalls:  map[string]int{preHook: 1},
+			expectedFatals: 1,
+		},
+		{
+			name:             "post-hook-failed",
+			postHook:         postHook,
+			failing:          []string{postHook},
+			expectedCalls:    map[string]int{postHook: 1},
+			expectedWarnings: 1,
+		},
+		{
+			name:          "post-hook-failed-fatal",
+			postHook:      postHook,
+			postHookFatal: "true",
+			failing:       []string{postHook},
+			expectedCalls: map[string]int{postHook: 1},
+			// the recording logger does not exit on fatal, so the launcher carries on to warn
+			expectedWarnings: 1,
+			expectedFatals:   1,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				t.Setenv(clabernetesconstants.LauncherPreDockerHook, testCase.preHook)
+				t.Setenv(clabernetesconstants.LauncherPostDockerHook, testCase.postHook)
+				t.Setenv(clabernetesconstants.LauncherPostDockerHookFatal, testCase.postHookFatal)
+
+				fakeRunner := newFakeCommandRunner()
+
+				for _, hook := range testCase.failing {
+					fakeRunner.results[hook] = errFakeCommand
+				}
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				logger := &recordingLogger{}
+
+				claberneteslauncher.RunDockerHooks(context.Background(), logger)
+
+				for hook, expectedCalls := range testCase.expectedCalls {
+					if fakeRunner.calls[hook] != expectedCalls {
+						clabernetestesthelper.FailOutput(t, fakeRunner.calls[hook], expectedCalls)
+					}
+				}
+
+				if len(logger.warnings) != testCase.expectedWarnings {
+					clabernetestesthelper.FailOutput(
+						t,
+						logger.warnings,
+						testCase.expectedWarnings,
+					)
+				}
+
+				if len(logger.fatals) != testCase.expectedFatals {
+					clabernetestesthelper.FailOutput(t, logger.fatals, testCase.expectedFatals)
+				}
+			})
+	}
+}
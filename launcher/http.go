@@ -0,0 +1,116 @@
+package launcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	clabernetesutil "github.com/srl-labs/clabernetes/util"
+)
+
+const (
+	httpTimeout        = 5 * time.Second
+	logsRoute          = "/logs/buffer/"
+	timingsRoute       = "/timings"
+	defaultLogsNLines  = 100
+	logsNLinesQueryKey = "n"
+)
+
+func (c *clabernetes) startHTTPServer() {
+	port := clabernetesutil.GetEnvIntOrDefault(clabernetesconstants.LauncherHTTPPort, 0)
+	if port == 0 {
+		c.logger.Debug("no launcher http port configured, not starting http server...")
+
+		return
+	}
+
+	server := &http.Server{
+		BaseContext: func(_ net.Listener) context.Context {
+			return c.ctx
+		},
+		Addr:              fmt.Sprintf(":%d", port),
+		Handler:           c.newHTTPMux(),
+		ReadTimeout:       httpTimeout,
+		WriteTimeout:      httpTimeout,
+		ReadHeaderTimeout: httpTimeout,
+	}
+
+	go func() {
+		<-c.ctx.Done()
+
+		_ = server.Close()
+	}()
+
+	go func() {
+		c.logger.Infof("starting launcher http server on port %d...", port)
+
+		err := server.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			c.logger.Warnf("launcher http server has failed, error: %s", err)
+		}
+	}()
+}
+
+// newHTTPMux returns the mux serving the launcher http server routes. The in memory node log
+// buffers are served under their own prefix so that no node name can shadow another route.
+func (c *clabernetes) newHTTPMux() *http.ServeMux {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc(logsRoute, c.logsHandler)
+	mux.HandleFunc(logFileRoute, c.logFileHandler)
+	mux.HandleFunc(logSearchRoute, c.logSearchHandler)
+	mux.HandleFunc(timingsRoute, c.timingsHandler)
+	mux.HandleFunc(readyzRoute, c.readyzHandler)
+
+	return mux
+}
+
+func (c *clabernetes) logsHandler(w http.ResponseWriter, r *http.Request) {
+	c.logger.Debugf("received %q on %q endpoint from %q", r.Method, r.RequestURI, r.RemoteAddr)
+
+	nodeName := strings.TrimPrefix(r.URL.Path, logsRoute)
+
+	n := defaultLogsNLines
+
+	if r.URL.Query().Has(logsNLinesQueryKey) {
+		var err error
+
+		n, err = strconv.Atoi(r.URL.Query().Get(logsNLinesQueryKey))
+		if err != nil {
+			http.Error(w, "invalid line count", http.StatusBadRequest)
+
+			return
+		}
+	}
+
+	buffer, ok := c.nodeLogBuffers.get(nodeName)
+	if !ok {
+		http.Error(w, fmt.Sprintf("no logs for node %q", nodeName), http.StatusNotFound)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+
+	for _, line := range buffer.Lines(n) {
+		_, _ = fmt.Fprintln(w, line)
+	}
+}
+
+func (c *clabernetes) timingsHandler(w http.ResponseWriter, r *http.Request) {
+	c.logger.Debugf("received %q on %q endpoint from %q", r.Method, r.RequestURI, r.RemoteAddr)
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(w).Encode(c.startupTimings)
+	if err != nil {
+		c.logger.Warnf("failed writing startup timings response, err: %s", err)
+	}
+}
@@ -0,0 +1,83 @@
+package launcher_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+func TestLauncherHTTPLogRoutes(t *testing.T) {
+	cases := []struct {
+		name           string
+		target         string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "buffer",
+			target:         "/logs/buffer/srl1?n=1",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "buffered two\n",
+		},
+		{
+			name:           "buffer-node-named-search",
+			target:         "/logs/buffer/search",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "search one\n",
+		},
+		{
+			name:           "buffer-unknown-node",
+			target:         "/logs/buffer/srl9",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "no logs for node \"srl9\"\n",
+		},
+		{
+			name:           "search",
+			target:         "/logs/search?node=srl1&q=two",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"line":"two"`,
+		},
+		{
+			name:           "file",
+			target:         "/logs?node=srl1",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "one\ntwo\n",
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				recorder := httptest.NewRecorder()
+
+				claberneteslauncher.ServeLauncherHTTP(
+					recorder,
+					httptest.NewRequest(http.MethodGet, testCase.target, http.NoBody),
+					writeLogFileAPITestFiles(t),
+					map[string][]string{
+						"srl1":   {"buffered one", "buffered two"},
+						"search": {"search one"},
+					},
+				)
+
+				if recorder.Code != testCase.expectedStatus {
+					clabernetestesthelper.FailOutput(t, recorder.Code, testCase.expectedStatus)
+				}
+
+				if !strings.Contains(recorder.Body.String(), testCase.expectedBody) {
+					clabernetestesthelper.FailOutput(
+						t,
+						recorder.Body.String(),
+						testCase.expectedBody,
+					)
+				}
+			})
+	}
+}
@@ -19,7 +19,8 @@ import (
 )
 
 const (
-	imageDestination       = "/clabernetes/.image/node-image.tar"
+	imageDirectory         = ".image"
+	imageFileName          = "node-image.tar"
 	imageCheckPollInterval = 5 * time.Second
 	imageCheckLogCounter   = 6
 )
@@ -91,7 +92,19 @@ func (c *clabernetes) image() {
 }
 
 func (c *clabernetes) copyImageFromCRI(imageManager claberneteslauncherimage.Manager) {
-	err := imageManager.Export(c.ctx, c.imageName, imageDestination)
+	err := os.MkdirAll(
+		c.workPath(imageDirectory),
+		clabernetesconstants.PermissionsEveryoneAllPermissions,
+	)
+	if err != nil {
+		c.logger.Warnf("failed image pull through (create image directory), err: %s", err)
+
+		handleImagePullThroughModeAlwaysPanic(c.imagePullThroughMode)
+
+		return
+	}
+
+	err = imageManager.Export(c.ctx, c.imageName, c.workPath(imageDirectory, imageFileName))
 	if err != nil {
 		c.logger.Warnf("failed image pull through (export), err: %s", err)
 
@@ -344,7 +357,7 @@ func (c *clabernetes) imageImport() error {
 		"image",
 		"load",
 		"-i",
-		"/clabernetes/.image/node-image.tar",
+		c.workPath(imageDirectory, imageFileName),
 	)
 
 	exportCmd.Stdout = c.logger
@@ -359,6 +372,10 @@ func (c *clabernetes) imageImport() error {
 }
 
 func (c *clabernetes) imageCleanup() {
+	if !c.dockerSubcommandAvailable("system") {
+		return
+	}
+
 	c.logger.Debug("running image (docker) cleanup in background...")
 
 	exportCmd := exec.CommandContext(
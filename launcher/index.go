@@ -0,0 +1,117 @@
+package launcher
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+const (
+	// containerlabLabLabel is the label containerlab sets (to the lab name) on all containers it
+	// manages.
+	containerlabLabLabel = "containerlab"
+	// containerlabNodeNameLabel is the label containerlab sets (to the node name) on all node
+	// containers it manages.
+	containerlabNodeNameLabel = "clab-node-name"
+)
+
+// nodeContainerIndex is a cached mapping of (containerlab) node name to container id, so that we
+// can resolve node names without a docker invocation per lookup.
+type nodeContainerIndex struct {
+	lock           sync.RWMutex
+	nodeContainers map[string]string
+}
+
+func newNodeContainerIndex(ctx context.Context) (*nodeContainerIndex, error) {
+	index := &nodeContainerIndex{
+		nodeContainers: map[string]string{},
+	}
+
+	err := index.refresh(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return index, nil
+}
+
+// refresh rebuilds the index from all containerlab managed containers.
+func (i *nodeContainerIndex) refresh(ctx context.Context) error {
+	psCmd := exec.CommandContext( //nolint:gosec
+		ctx,
+		"docker",
+		"ps",
+		"--all",
+		"--filter",
+		fmt.Sprintf("label=%s", containerlabLabLabel),
+		"--format",
+		fmt.Sprintf("{{.Label %q}} {{.ID}}", containerlabNodeNameLabel),
+	)
+
+	output, err := runner.Output(psCmd)
+	if err != nil {
+		return classifyDockerError(err)
+	}
+
+	nodeContainers := map[string]string{}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		nodeName, containerID, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok || nodeName == "" {
+			continue
+		}
+
+		nodeContainers[nodeName] = containerID
+	}
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	i.nodeContainers = nodeContainers
+
+	return nil
+}
+
+// lookup returns the container id for the given node name from the index.
+func (i *nodeContainerIndex) lookup(nodeName string) (string, bool) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	containerID, ok := i.nodeContainers[nodeName]
+
+	return containerID, ok
+}
+
+// resolve returns the container id for the given node name, refreshing the index once if the node
+// is not (yet) in the index.
+func (i *nodeContainerIndex) resolve(ctx context.Context, nodeName string) (string, error) {
+	containerID, ok := i.lookup(nodeName)
+	if ok {
+		return containerID, nil
+	}
+
+	err := i.refresh(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	containerID, _ = i.lookup(nodeName)
+
+	return containerID, nil
+}
+
+// nodeNames returns all node names in the index.
+func (i *nodeContainerIndex) nodeNames() []string {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	nodeNames := make([]string, 0, len(i.nodeContainers))
+
+	for nodeName := range i.nodeContainers {
+		nodeNames = append(nodeNames, nodeName)
+	}
+
+	return nodeNames
+}
@@ -0,0 +1,116 @@
+package launcher_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+const nodeContainerIndexPsKey = "docker ps --all --filter label=containerlab" +
+	` --format {{.Label "clab-node-name"}} {{.ID}}`
+
+func TestNodeContainerIndexResolve(t *testing.T) {
+	cases := []struct {
+		name              string
+		initialOutput     string
+		refreshedOutput   string
+		nodeName          string
+		expectedID        string
+		expectedPsCalls   int
+		expectedNodeNames []string
+	}{
+		{
+			name: "indexed",
+			// containers without a node name label (e.g. not a node) are skipped
+			initialOutput:     "srl1 abc123\nsrl2 def456\n ghi789\n\n",
+			nodeName:          "srl2",
+			expectedID:        "def456",
+			expectedPsCalls:   1,
+			expectedNodeNames: []string{"srl1", "srl2"},
+		},
+		{
+			name:              "refreshed",
+			initialOutput:     "srl1 abc123\n",
+			refreshedOutput:   "srl1 abc123\nsrl2 def456\n",
+			nodeName:          "srl2",
+			expectedID:        "def456",
+			expectedPsCalls:   2,
+			expectedNodeNames: []string{"srl1", "srl2"},
+		},
+		{
+			name:              "unknown",
+			initialOutput:     "srl1 abc123\n",
+			refreshedOutput:   "srl1 abc123\n",
+			nodeName:          "srl9",
+			expectedID:        "",
+			expectedPsCalls:   2,
+			expectedNodeNames: []string{"srl1"},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				fakeRunner := newFakeCommandRunner()
+
+				fakeRunner.outputs[nodeContainerIndexPsKey] = []byte(testCase.initialOutput)
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				index, err := claberneteslauncher.NewNodeContainerIndex(context.Background())
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				fakeRunner.outputs[nodeContainerIndexPsKey] = []byte(testCase.refreshedOutput)
+
+				actualID, err := claberneteslauncher.ResolveNodeContainer(
+					context.Background(),
+					index,
+					testCase.nodeName,
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if actualID != testCase.expectedID {
+					clabernetestesthelper.FailOutput(t, actualID, testCase.expectedID)
+				}
+
+				if fakeRunner.calls[nodeContainerIndexPsKey] != testCase.expectedPsCalls {
+					clabernetestesthelper.FailOutput(
+						t,
+						fakeRunner.calls[nodeContainerIndexPsKey],
+						testCase.expectedPsCalls,
+					)
+				}
+
+				clabernetestesthelper.MarshaledEqual(
+					t,
+					claberneteslauncher.NodeContainerIndexNodeNames(index),
+					testCase.expectedNodeNames,
+				)
+			})
+	}
+}
+
+func TestNodeContainerIndexRefreshError(t *testing.T) {
+	fakeRunner := newFakeCommandRunner()
+
+	fakeRunner.results[nodeContainerIndexPsKey] = errFakeCommand
+
+	restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+	defer restore()
+
+	_, err := claberneteslauncher.NewNodeContainerIndex(context.Background())
+	if !errors.Is(err, errFakeCommand) {
+		clabernetestesthelper.FailOutput(t, err, errFakeCommand)
+	}
+}
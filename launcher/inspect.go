@@ -0,0 +1,315 @@
+package launcher
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+)
+
+// containerInspect is the subset of the docker inspect output of a container that the launcher
+// cares about.
+type containerInspect struct {
+	ID              string                   `json:"Id"`
+	Name            string                   `json:"Name"`
+	State           containerInspectState    `json:"State"`
+	Config          containerInspectConfig   `json:"Config"`
+	HostConfig      containerInspectHost     `json:"HostConfig"`
+	NetworkSettings containerInspectNetworks `json:"NetworkSettings"`
+}
+
+type containerInspectState struct {
+	Status    string                  `json:"Status"`
+	Running   bool                    `json:"Running"`
+	OOMKilled bool                    `json:"OOMKilled"`
+	ExitCode  int                     `json:"ExitCode"`
+	Health    *containerInspectHealth `json:"Health"`
+}
+
+type containerInspectHealth struct {
+	Status string `json:"Status"`
+}
+
+type containerInspectConfig struct {
+	Image  string            `json:"Image"`
+	Labels map[string]string `json:"Labels"`
+}
+
+type containerInspectHost struct {
+	RestartPolicy containerInspectRestartPolicy `json:"RestartPolicy"`
+	Memory        int64                         `json:"Memory"`
+	NanoCPUs      int64                         `json:"NanoCpus"`
+	PidsLimit     *int64                        `json:"PidsLimit"`
+}
+
+type containerInspectRestartPolicy struct {
+	Name              string `json:"Name"`
+	MaximumRetryCount int    `json:"MaximumRetryCount"`
+}
+
+type containerInspectNetworks struct {
+	Networks map[string]containerInspectNetwork `json:"Networks"`
+}
+
+type containerInspectNetwork struct {
+	IPAddress         string `json:"IPAddress"`
+	GlobalIPv6Address string `json:"GlobalIPv6Address"`
+	MacAddress        string `json:"MacAddress"`
+	Gateway           string `json:"Gateway"`
+}
+
+// networkInspect is the subset of the docker network inspect output that the launcher cares about.
+type networkInspect struct {
+	ID         string                             `json:"Id"`
+	Name       string                             `json:"Name"`
+	Driver     string                             `json:"Driver"`
+	IPAM       networkInspectIPAM                 `json:"IPAM"`
+	Containers map[string]networkInspectContainer `json:"Containers"`
+}
+
+type networkInspectIPAM struct {
+	Driver string                     `json:"Driver"`
+	Config []networkInspectIPAMConfig `json:"Config"`
+}
+
+type networkInspectIPAMConfig struct {
+	Subnet  string `json:"Subnet"`
+	Gateway string `json:"Gateway"`
+}
+
+type networkInspectContainer struct {
+	Name        string `json:"Name"`
+	IPv4Address string `json:"IPv4Address"`
+	IPv6Address string `json:"IPv6Address"`
+	MacAddress  string `json:"MacAddress"`
+}
+
+// inspectNetwork inspects the docker network with the given name (or id). If the network does not
+// exist an error wrapping ErrNetworkNotFound is returned.
+func inspectNetwork(ctx context.Context, name string) (*networkInspect, error) {
+	inspectCmd := exec.CommandContext(
+		ctx,
+		"docker",
+		"network",
+		"inspect",
+		"--format",
+		"{{json .}}",
+		name,
+	)
+
+	output, err := runner.Output(inspectCmd)
+	if err != nil {
+		err = classifyDockerError(err)
+
+		if errors.Is(err, claberneteserrors.ErrContainerNotFound) {
+			return nil, fmt.Errorf(
+				"%w: network %q, err: %s",
+				claberneteserrors.ErrNetworkNotFound,
+				name,
+				err,
+			)
+		}
+
+		return nil, err
+	}
+
+	result := &networkInspect{}
+
+	err = json.Unmarshal(output, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// getContainerLabel returns the value of the label key of the given container. A label that is not
+// set on the container is returned as an empty string, not an error.
+func getContainerLabel(ctx context.Context, containerID, key string) (string, error) {
+	inspectCmd := exec.CommandContext(
+		ctx,
+		"docker",
+		"inspect",
+		"--format",
+		fmt.Sprintf("{{index .Config.Labels %q}}", key),
+		containerID,
+	)
+
+	output, err := runner.Output(inspectCmd)
+	if err != nil {
+		return "", classifyDockerError(err)
+	}
+
+	label := strings.TrimSpace(string(output))
+
+	// docker renders a missing map key in a template as "<no value>"
+	if label == "<no value>" {
+		return "", nil
+	}
+
+	return label, nil
+}
+
+// getContainerLabels returns all the labels of the given container.
+func getContainerLabels(ctx context.Context, containerID string) (map[string]string, error) {
+	inspectCmd := exec.CommandContext(
+		ctx,
+		"docker",
+		"inspect",
+		"--format",
+		"{{json .Config.Labels}}",
+		containerID,
+	)
+
+	output, err := runner.Output(inspectCmd)
+	if err != nil {
+		return nil, classifyDockerError(err)
+	}
+
+	var labels map[string]string
+
+	err = json.Unmarshal(output, &labels)
+	if err != nil {
+		return nil, err
+	}
+
+	// a container without labels renders as "null", return an empty map rather than nil
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	return labels, nil
+}
+
+// inspectContainers inspects all the given containers with a single docker inspect invocation,
+// returning the results keyed by the given container ids. Containers that no longer exist are
+// simply omitted from the returned map -- an error is only returned if we got nothing back at all.
+func inspectContainers(
+	ctx context.Context,
+	containerIDs []string,
+) (map[string]*containerInspect, error) {
+	inspected := make(map[string]*containerInspect, len(containerIDs))
+
+	if len(containerIDs) == 0 {
+		return inspected, nil
+	}
+
+	inspectCmd := exec.CommandContext(
+		ctx,
+		"docker",
+		append([]string{"inspect"}, containerIDs...)...,
+	)
+
+	// docker exits non-zero if *any* of the ids are not found, but still prints the ones it did
+	// find to stdout, so only bail if we have no output to work with
+	output, cmdErr := runner.Output(inspectCmd)
+
+	var results []*containerInspect
+
+	err := json.Unmarshal(output, &results)
+	if err != nil {
+		if cmdErr != nil {
+			return nil, classifyDockerError(cmdErr)
+		}
+
+		return nil, err
+	}
+
+	for _, result := range results {
+		for _, containerID := range containerIDs {
+			if strings.HasPrefix(result.ID, containerID) ||
+				strings.TrimPrefix(result.Name, "/") == containerID {
+				inspected[containerID] = result
+			}
+		}
+	}
+
+	return inspected, nil
+}
+
+// portMapping is a published port of a container.
+type portMapping struct {
+	ContainerPort int    `json:"containerPort"`
+	Protocol      string `json:"protocol"`
+	HostIP        string `json:"hostIP"`
+	HostPort      int    `json:"hostPort"`
+}
+
+type containerInspectPortBinding struct {
+	HostIP   string `json:"HostIp"`
+	HostPort string `json:"HostPort"`
+}
+
+// getContainerPorts returns the published ports of the given container, sorted by container port,
+// protocol, and host address. Exposed but unpublished ports are omitted, a container without
+// published ports returns an empty slice.
+func getContainerPorts(ctx context.Context, containerID string) ([]portMapping, error) {
+	inspectCmd := exec.CommandContext(
+		ctx,
+		"docker",
+		"inspect",
+		"--format",
+		"{{json .NetworkSettings.Ports}}",
+		containerID,
+	)
+
+	output, err := runner.Output(inspectCmd)
+	if err != nil {
+		return nil, classifyDockerError(err)
+	}
+
+	var ports map[string][]containerInspectPortBinding
+
+	err = json.Unmarshal(output, &ports)
+	if err != nil {
+		return nil, err
+	}
+
+	portMappings := []portMapping{}
+
+	for port, bindings := range ports {
+		rawContainerPort, protocol, _ := strings.Cut(port, "/")
+
+		containerPort, err := strconv.Atoi(rawContainerPort)
+		if err != nil {
+			return nil, fmt.Errorf("invalid container port %q, err: %w", port, err)
+		}
+
+		for _, binding := range bindings {
+			hostPort, err := strconv.Atoi(binding.HostPort)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"invalid host port %q for container port %q, err: %w",
+					binding.HostPort,
+					port,
+					err,
+				)
+			}
+
+			portMappings = append(portMappings, portMapping{
+				ContainerPort: containerPort,
+				Protocol:      protocol,
+				HostIP:        binding.HostIP,
+				HostPort:      hostPort,
+			})
+		}
+	}
+
+	slices.SortFunc(portMappings, func(a, b portMapping) int {
+		return cmp.Or(
+			cmp.Compare(a.ContainerPort, b.ContainerPort),
+			cmp.Compare(a.Protocol, b.Protocol),
+			cmp.Compare(a.HostIP, b.HostIP),
+			cmp.Compare(a.HostPort, b.HostPort),
+		)
+	})
+
+	return portMappings, nil
+}
@@ -0,0 +1,326 @@
+package launcher_test
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"reflect"
+	"testing"
+
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+func TestInspectContainers(t *testing.T) {
+	cases := []struct {
+		name         string
+		containerIDs []string
+		fixture      string
+		cmdErr       error
+		expectedIDs  []string
+	}{
+		{
+			name:         "partial",
+			containerIDs: []string{"4f66ad9a0b2e", "deadbeef"},
+			fixture:      "docker-inspect/partial.json",
+			cmdErr:       errFakeCommand,
+			expectedIDs:  []string{"4f66ad9a0b2e"},
+		},
+		{
+			name:         "by-name",
+			containerIDs: []string{"srl1"},
+			fixture:      "docker-inspect/partial.json",
+			expectedIDs:  []string{"srl1"},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				fakeRunner := newFakeCommandRunner()
+
+				fakeKey := "docker inspect"
+				for _, containerID := range testCase.containerIDs {
+					fakeKey += " " + containerID
+				}
+
+				fakeRunner.outputs[fakeKey] = clabernetestesthelper.ReadTestFixtureFile(
+					t,
+					testCase.fixture,
+				)
+				fakeRunner.results[fakeKey] = testCase.cmdErr
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				actual, err := claberneteslauncher.InspectContainers(
+					context.Background(),
+					testCase.containerIDs,
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if len(actual) != len(testCase.expectedIDs) {
+					clabernetestesthelper.FailOutput(t, actual, testCase.expectedIDs)
+				}
+
+				for _, expectedID := range testCase.expectedIDs {
+					inspected, ok := actual[expectedID]
+					if !ok {
+						t.Fatalf("expected container %q in inspect results", expectedID)
+					}
+
+					if inspected.NetworkSettings.Networks["clab"].IPAddress != "172.20.20.2" {
+						t.Fatalf("unexpected inspect result %+v", inspected)
+					}
+				}
+			})
+	}
+}
+
+func TestInspectNetwork(t *testing.T) {
+	cases := []struct {
+		name            string
+		fixture         string
+		cmdErr          error
+		expectedSubnet  string
+		expectedGateway string
+		expectedErr     error
+	}{
+		{
+			name:            "simple",
+			fixture:         "docker-network-inspect/clab.json",
+			expectedSubnet:  "172.20.20.0/24",
+			expectedGateway: "172.20.20.1",
+		},
+		{
+			name: "not-found",
+			cmdErr: &exec.ExitError{
+				Stderr: []byte("Error response from daemon: network clab not found"),
+			},
+			expectedErr: claberneteserrors.ErrNetworkNotFound,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				fakeRunner := newFakeCommandRunner()
+
+				fakeKey := "docker network inspect --format {{json .}} clab"
+
+				if testCase.fixture != "" {
+					fakeRunner.outputs[fakeKey] = clabernetestesthelper.ReadTestFixtureFile(
+						t,
+						testCase.fixture,
+					)
+				}
+
+				fakeRunner.results[fakeKey] = testCase.cmdErr
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				actual, err := claberneteslauncher.InspectNetwork(context.Background(), "clab")
+				if testCase.expectedErr != nil {
+					if !errors.Is(err, testCase.expectedErr) {
+						clabernetestesthelper.FailOutput(t, err, testCase.expectedErr)
+					}
+
+					// a missing network must not be mistaken for an already gone container
+					if errors.Is(err, claberneteserrors.ErrContainerNotFound) {
+						t.Fatalf("expected error not to wrap ErrContainerNotFound, got %v", err)
+					}
+
+					return
+				}
+
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if len(actual.IPAM.Config) != 1 ||
+					actual.IPAM.Config[0].Subnet != testCase.expectedSubnet ||
+					actual.IPAM.Config[0].Gateway != testCase.expectedGateway {
+					clabernetestesthelper.FailOutput(t, actual.IPAM, testCase.expectedSubnet)
+				}
+
+				if len(actual.Containers) != 1 {
+					t.Fatalf("expected one connected container, got %+v", actual.Containers)
+				}
+			})
+	}
+}
+
+func TestGetContainerLabel(t *testing.T) {
+	cases := []struct {
+		name     string
+		key      string
+		output   string
+		expected string
+	}{
+		{
+			name:     "simple",
+			key:      "clab-node-name",
+			output:   "srl1\n",
+			expected: "srl1",
+		},
+		{
+			name:     "missing",
+			key:      "not-a-label",
+			output:   "<no value>\n",
+			expected: "",
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				fakeRunner := newFakeCommandRunner()
+
+				fakeRunner.outputs[`docker inspect --format {{index .Config.Labels "`+
+					testCase.key+`"}} srl1`] = []byte(testCase.output)
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				actual, err := claberneteslauncher.GetContainerLabel(
+					context.Background(),
+					"srl1",
+					testCase.key,
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if actual != testCase.expected {
+					clabernetestesthelper.FailOutput(t, actual, testCase.expected)
+				}
+			})
+	}
+}
+
+func TestGetContainerLabels(t *testing.T) {
+	cases := []struct {
+		name     string
+		output   string
+		expected map[string]string
+	}{
+		{
+			name:   "simple",
+			output: `{"containerlab":"topo","clab-node-name":"srl1"}`,
+			expected: map[string]string{
+				"containerlab":   "topo",
+				"clab-node-name": "srl1",
+			},
+		},
+		{
+			name:     "no-labels",
+			output:   "null",
+			expected: map[string]string{},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				fakeRunner := newFakeCommandRunner()
+
+				fakeRunner.outputs["docker inspect --format {{json .Config.Labels}} srl1"] = []byte(
+					testCase.output,
+				)
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				actual, err := claberneteslauncher.GetContainerLabels(context.Background(), "srl1")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if !reflect.DeepEqual(actual, testCase.expected) {
+					clabernetestesthelper.FailOutput(t, actual, testCase.expected)
+				}
+			})
+	}
+}
+
+func TestGetContainerPorts(t *testing.T) {
+	cases := []struct {
+		name      string
+		output    string
+		expected  []claberneteslauncher.PortMapping
+		expectErr bool
+	}{
+		{
+			name: "simple",
+			output: `{"57400/tcp":[{"HostIp":"0.0.0.0","HostPort":"57400"}],` +
+				`"22/tcp":[{"HostIp":"0.0.0.0","HostPort":"2222"},{"HostIp":"::","HostPort":"2222"}],` +
+				`"161/udp":[{"HostIp":"0.0.0.0","HostPort":"1161"}],"830/tcp":null}`,
+			expected: []claberneteslauncher.PortMapping{
+				{ContainerPort: 22, Protocol: "tcp", HostIP: "0.0.0.0", HostPort: 2222},
+				{ContainerPort: 22, Protocol: "tcp", HostIP: "::", HostPort: 2222},
+				{ContainerPort: 161, Protocol: "udp", HostIP: "0.0.0.0", HostPort: 1161},
+				{ContainerPort: 57400, Protocol: "tcp", HostIP: "0.0.0.0", HostPort: 57400},
+			},
+		},
+		{
+			name:     "no-ports",
+			output:   "{}",
+			expected: []claberneteslauncher.PortMapping{},
+		},
+		{
+			name:     "null-ports",
+			output:   "null",
+			expected: []claberneteslauncher.PortMapping{},
+		},
+		{
+			name:      "invalid-port",
+			output:    `{"ssh/tcp":[{"HostIp":"0.0.0.0","HostPort":"2222"}]}`,
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				fakeRunner := newFakeCommandRunner()
+
+				fakeRunner.outputs["docker inspect --format {{json .NetworkSettings.Ports}} srl1"] =
+					[]byte(testCase.output)
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				actual, err := claberneteslauncher.GetContainerPorts(context.Background(), "srl1")
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+
+				if testCase.expectErr {
+					return
+				}
+
+				if !reflect.DeepEqual(actual, testCase.expected) {
+					clabernetestesthelper.FailOutput(t, actual, testCase.expected)
+				}
+			})
+	}
+}
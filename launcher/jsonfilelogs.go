@@ -0,0 +1,179 @@
+package launcher
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	jsonFileLogDriver       = "json-file"
+	jsonFileLogPollInterval = 250 * time.Millisecond
+)
+
+// jsonFileLogRecord is a single record of a docker json-file log driver log file.
+type jsonFileLogRecord struct {
+	Log    string `json:"log"`
+	Stream string `json:"stream"`
+	Time   string `json:"time"`
+}
+
+// getContainerLogPath returns the log driver of the given container and the path of its log file
+// (if the driver writes one).
+func getContainerLogPath(ctx context.Context, containerID string) (driver, path string, err error) {
+	inspectCmd := exec.CommandContext(
+		ctx,
+		"docker",
+		"inspect",
+		"--format",
+		"{{.HostConfig.LogConfig.Type}} {{.LogPath}}",
+		containerID,
+	)
+
+	output, err := runner.Output(inspectCmd)
+	if err != nil {
+		return "", "", classifyDockerError(err)
+	}
+
+	driver, path, _ = strings.Cut(strings.TrimSpace(string(output)), " ")
+
+	return driver, path, nil
+}
+
+// writeJSONFileLogRecord decodes a single json-file log record and writes its log field to w,
+// tagged with its stream. Records at or before since (a docker timestamp, may be empty) are
+// skipped as they were already written by a previous launcher run. It returns the record time.
+func writeJSONFileLogRecord(w io.Writer, line []byte, since string) (string, error) {
+	record := &jsonFileLogRecord{}
+
+	err := json.Unmarshal(line, record)
+	if err != nil {
+		return "", err
+	}
+
+	if since != "" && !jsonFileLogRecordAfter(record.Time, since) {
+		return "", nil
+	}
+
+	_, err = w.Write([]byte("[" + record.Stream + "] " + record.Log))
+	if err != nil {
+		return "", err
+	}
+
+	return record.Time, nil
+}
+
+func jsonFileLogRecordAfter(recordTime, since string) bool {
+	parsedRecordTime, err := time.Parse(time.RFC3339Nano, recordTime)
+	if err != nil {
+		return true
+	}
+
+	parsedSince, err := time.Parse(time.RFC3339Nano, since)
+	if err != nil {
+		return true
+	}
+
+	return parsedRecordTime.After(parsedSince)
+}
+
+// followJSONLogFile follows the json-file log at path until the context is done, writing each
+// record to w. If the log file is rotated the new file is followed from its start.
+func (c *clabernetes) followJSONLogFile(
+	ctx context.Context,
+	path, containerLogName string,
+	w io.Writer,
+) error {
+	f, err := os.Open(path) //nolint:gosec
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	reader := bufio.NewReader(f)
+
+	var partial []byte
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		partial = append(partial, line...)
+
+		switch {
+		case err == nil:
+			recordTime, err := writeJSONFileLogRecord(
+				w,
+				bytes.TrimSpace(partial),
+				c.logTailState.since(containerLogName),
+			)
+			if err != nil {
+				c.logger.Warnf(
+					"failed decoding json-file log record of container %q, err: %s",
+					containerLogName,
+					err,
+				)
+			} else if recordTime != "" {
+				c.logTailState.update(containerLogName, recordTime)
+			}
+
+			partial = nil
+
+			continue
+		case !errors.Is(err, io.EOF):
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(jsonFileLogPollInterval):
+		}
+
+		rotated, err := jsonLogFileRotated(f, path)
+		if err != nil {
+			return err
+		}
+
+		if rotated {
+			_ = f.Close()
+
+			f, err = os.Open(path) //nolint:gosec
+			if err != nil {
+				return err
+			}
+
+			reader.Reset(f)
+
+			partial = nil
+		}
+	}
+}
+
+// jsonLogFileRotated returns true if the file at path is no longer the file f has open.
+func jsonLogFileRotated(f *os.File, path string) (bool, error) {
+	openInfo, err := f.Stat()
+	if err != nil {
+		return false, err
+	}
+
+	pathInfo, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			// mid rotation, check again next time around
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return !os.SameFile(openInfo, pathInfo), nil
+}
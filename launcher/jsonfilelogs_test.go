@@ -0,0 +1,71 @@
+package launcher_test
+
+import (
+	"bytes"
+	"testing"
+
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+func TestWriteJSONFileLogRecord(t *testing.T) {
+	cases := []struct {
+		name         string
+		line         string
+		since        string
+		expected     string
+		expectedTime string
+		expectErr    bool
+	}{
+		{
+			name:         "stdout",
+			line:         `{"log":"hello\n","stream":"stdout","time":"2024-01-01T00:00:01.5Z"}`,
+			expected:     "[stdout] hello\n",
+			expectedTime: "2024-01-01T00:00:01.5Z",
+		},
+		{
+			name:         "stderr",
+			line:         `{"log":"oops\n","stream":"stderr","time":"2024-01-01T00:00:02Z"}`,
+			expected:     "[stderr] oops\n",
+			expectedTime: "2024-01-01T00:00:02Z",
+		},
+		{
+			name:     "already-written",
+			line:     `{"log":"old\n","stream":"stdout","time":"2024-01-01T00:00:01Z"}`,
+			since:    "2024-01-01T00:00:01Z",
+			expected: "",
+		},
+		{
+			name:      "not-json",
+			line:      `hello`,
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				out := &bytes.Buffer{}
+
+				actualTime, err := claberneteslauncher.WriteJSONFileLogRecord(
+					out,
+					[]byte(testCase.line),
+					testCase.since,
+				)
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+
+				if out.String() != testCase.expected {
+					clabernetestesthelper.FailOutput(t, out.String(), testCase.expected)
+				}
+
+				if actualTime != testCase.expectedTime {
+					clabernetestesthelper.FailOutput(t, actualTime, testCase.expectedTime)
+				}
+			})
+	}
+}
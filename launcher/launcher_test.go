@@ -0,0 +1,14 @@
+package launcher_test
+
+import (
+	"os"
+	"testing"
+
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+func TestMain(m *testing.M) {
+	clabernetestesthelper.Flags()
+
+	os.Exit(m.Run())
+}
@@ -0,0 +1,117 @@
+package launcher
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+)
+
+const (
+	nodeLogPrefixNodePlaceholder      = "{node}"
+	nodeLogPrefixIDPlaceholder        = "{id}"
+	nodeLogPrefixTimestampPlaceholder = "{ts}"
+)
+
+var nodeLogPrefixPlaceholderPattern = regexp.MustCompile(`{[^{}]*}`) //nolint:gochecknoglobals
+
+// lineWriter is an io.Writer that buffers partial writes and calls emit once per complete line
+// (including the trailing newline).
+type lineWriter struct {
+	lock    sync.Mutex
+	partial []byte
+	emit    func(line []byte) error
+}
+
+func newLineWriter(emit func(line []byte) error) *lineWriter {
+	return &lineWriter{
+		emit: emit,
+	}
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	data := append(w.partial, p...) //nolint:gocritic
+
+	for {
+		idx := bytes.IndexByte(data, '\n')
+		if idx < 0 {
+			break
+		}
+
+		err := w.emit(data[:idx+1])
+		if err != nil {
+			return 0, err
+		}
+
+		data = data[idx+1:]
+	}
+
+	w.partial = append([]byte(nil), data...)
+
+	return len(p), nil
+}
+
+// validateNodeLogPrefixFormat ensures the given node log prefix format only contains known
+// placeholders.
+func validateNodeLogPrefixFormat(format string) error {
+	for _, placeholder := range nodeLogPrefixPlaceholderPattern.FindAllString(format, -1) {
+		switch placeholder {
+		case nodeLogPrefixNodePlaceholder,
+			nodeLogPrefixIDPlaceholder,
+			nodeLogPrefixTimestampPlaceholder:
+		default:
+			return fmt.Errorf(
+				"%w: unknown placeholder %q in node log prefix format %q, valid placeholders are"+
+					" %s, %s, and %s",
+				claberneteserrors.ErrLaunch,
+				placeholder,
+				format,
+				nodeLogPrefixNodePlaceholder,
+				nodeLogPrefixIDPlaceholder,
+				nodeLogPrefixTimestampPlaceholder,
+			)
+		}
+	}
+
+	return nil
+}
+
+// newPrefixWriter returns a writer that prepends each line written to it with the rendered prefix
+// format before writing it to w. If the format is empty w is returned as is.
+func newPrefixWriter(w io.Writer, format, nodeName, containerID string) io.Writer {
+	if format == "" {
+		return w
+	}
+
+	return newLineWriter(func(line []byte) error {
+		prefix := strings.NewReplacer(
+			nodeLogPrefixNodePlaceholder, nodeName,
+			nodeLogPrefixIDPlaceholder, containerID,
+			nodeLogPrefixTimestampPlaceholder, time.Now().UTC().Format(time.RFC3339),
+		).Replace(format)
+
+		_, err := w.Write(append([]byte(prefix), line...))
+
+		return err
+	})
+}
+
+// newReceiveTimestampWriter returns a writer that prepends each line written to it with the time
+// (per now, in RFC3339Nano) the launcher received it before writing it to w.
+func newReceiveTimestampWriter(w io.Writer, now func() time.Time) io.Writer {
+	return newLineWriter(func(line []byte) error {
+		timestamp := now().UTC().Format(time.RFC3339Nano)
+
+		_, err := w.Write(append([]byte(timestamp+" "), line...))
+
+		return err
+	})
+}
@@ -0,0 +1,113 @@
+package launcher_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+func TestValidateNodeLogPrefixFormat(t *testing.T) {
+	cases := []struct {
+		name        string
+		format      string
+		expectedErr error
+	}{
+		{
+			name:   "empty",
+			format: "",
+		},
+		{
+			name:   "all-placeholders",
+			format: "[{ts}] {node}/{id}: ",
+		},
+		{
+			name:        "unknown-placeholder",
+			format:      "{node} {host}: ",
+			expectedErr: claberneteserrors.ErrLaunch,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				err := claberneteslauncher.ValidateNodeLogPrefixFormat(testCase.format)
+				if !errors.Is(err, testCase.expectedErr) {
+					t.Fatalf("expected error %v, got %v", testCase.expectedErr, err)
+				}
+			})
+	}
+}
+
+func TestPrefixWriter(t *testing.T) {
+	cases := []struct {
+		name     string
+		format   string
+		writes   []string
+		expected string
+	}{
+		{
+			name:     "no-prefix",
+			format:   "",
+			writes:   []string{"one\n", "tw", "o\n"},
+			expected: "one\ntwo\n",
+		},
+		{
+			name:     "node-and-id",
+			format:   "{node}/{id}: ",
+			writes:   []string{"one\ntw", "o\nthree"},
+			expected: "srl1/abc123: one\nsrl1/abc123: two\n",
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				var out bytes.Buffer
+
+				w := claberneteslauncher.NewPrefixWriter(&out, testCase.format, "srl1", "abc123")
+
+				for _, write := range testCase.writes {
+					_, err := w.Write([]byte(write))
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				if out.String() != testCase.expected {
+					clabernetestesthelper.FailOutput(t, out.String(), testCase.expected)
+				}
+			})
+	}
+}
+
+func TestReceiveTimestampWriter(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 123456789, time.UTC)
+
+	var out bytes.Buffer
+
+	w := claberneteslauncher.NewReceiveTimestampWriter(&out, func() time.Time { return now })
+
+	for _, write := range []string{"one\ntw", "o\nthree"} {
+		_, err := w.Write([]byte(write))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := "2024-01-02T03:04:05.123456789Z one\n2024-01-02T03:04:05.123456789Z two\n"
+
+	if out.String() != expected {
+		clabernetestesthelper.FailOutput(t, out.String(), expected)
+	}
+}
@@ -0,0 +1,130 @@
+package launcher
+
+import (
+	"bytes"
+	"sync"
+)
+
+const (
+	defaultNodeLogBufferLines = 1000
+
+	// logRingBufferMaxLineLength is the most a logRingBuffer holds of a line that is not yet
+	// newline terminated, past that the line is split so a node that never writes a newline can
+	// not grow the buffer without bound.
+	logRingBufferMaxLineLength = 64 * 1024
+)
+
+// logRingBuffer is an io.Writer that keeps (only) the last size lines written to it in memory.
+type logRingBuffer struct {
+	lock    sync.Mutex
+	lines   []string
+	next    int
+	full    bool
+	partial []byte
+}
+
+func newLogRingBuffer(size int) *logRingBuffer {
+	size = max(size, 0)
+
+	return &logRingBuffer{
+		lines: make([]string, size),
+	}
+}
+
+func (r *logRingBuffer) Write(p []byte) (int, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if len(r.lines) == 0 {
+		return len(p), nil
+	}
+
+	data := append(r.partial, p...) //nolint:gocritic
+
+	for {
+		idx := bytes.IndexByte(data, '\n')
+
+		switch {
+		case idx >= 0:
+			r.push(string(data[:idx]))
+
+			data = data[idx+1:]
+		case len(data) >= logRingBufferMaxLineLength:
+			r.push(string(data[:logRingBufferMaxLineLength]))
+
+			data = data[logRingBufferMaxLineLength:]
+		default:
+			r.partial = append([]byte(nil), data...)
+
+			return len(p), nil
+		}
+	}
+}
+
+func (r *logRingBuffer) push(line string) {
+	r.lines[r.next] = line
+
+	r.next = (r.next + 1) % len(r.lines)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Lines returns (up to) the last n complete lines written to the buffer, oldest first. If n is
+// less than one all buffered lines are returned.
+func (r *logRingBuffer) Lines(n int) []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var ordered []string
+
+	if r.full {
+		ordered = append(ordered, r.lines[r.next:]...)
+	}
+
+	ordered = append(ordered, r.lines[:r.next]...)
+
+	if n > 0 && n < len(ordered) {
+		ordered = ordered[len(ordered)-n:]
+	}
+
+	return ordered
+}
+
+// logRingBuffers holds the logRingBuffer for each node (container) keyed by name.
+type logRingBuffers struct {
+	lock    sync.RWMutex
+	size    int
+	buffers map[string]*logRingBuffer
+}
+
+func newLogRingBuffers(size int) *logRingBuffers {
+	return &logRingBuffers{
+		size:    size,
+		buffers: map[string]*logRingBuffer{},
+	}
+}
+
+// add creates (or returns the existing) buffer for the given name.
+func (b *logRingBuffers) add(name string) *logRingBuffer {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	buffer, ok := b.buffers[name]
+	if !ok {
+		buffer = newLogRingBuffer(b.size)
+
+		b.buffers[name] = buffer
+	}
+
+	return buffer
+}
+
+func (b *logRingBuffers) get(name string) (*logRingBuffer, bool) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	buffer, ok := b.buffers[name]
+
+	return buffer, ok
+}
@@ -0,0 +1,83 @@
+package launcher_test
+
+import (
+	"strings"
+	"testing"
+
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+func TestLogRingBuffer(t *testing.T) {
+	cases := []struct {
+		name     string
+		size     int
+		writes   []string
+		n        int
+		expected []string
+	}{
+		{
+			name:     "simple",
+			size:     3,
+			writes:   []string{"one\ntwo\n"},
+			n:        0,
+			expected: []string{"one", "two"},
+		},
+		{
+			name:     "wrapped",
+			size:     3,
+			writes:   []string{"one\ntwo\nthree\n", "four\nfive\n"},
+			n:        0,
+			expected: []string{"three", "four", "five"},
+		},
+		{
+			name:     "partial-lines",
+			size:     3,
+			writes:   []string{"on", "e\ntw", "o\nthr"},
+			n:        0,
+			expected: []string{"one", "two"},
+		},
+		{
+			name:     "last-n",
+			size:     5,
+			writes:   []string{"one\ntwo\nthree\nfour\n"},
+			n:        2,
+			expected: []string{"three", "four"},
+		},
+		{
+			name:     "unterminated-long-line",
+			size:     3,
+			writes:   []string{strings.Repeat("a", 64*1024), strings.Repeat("a", 10) + "\n"},
+			n:        0,
+			expected: []string{strings.Repeat("a", 64*1024), strings.Repeat("a", 10)},
+		},
+		{
+			name:     "zero-size",
+			size:     0,
+			writes:   []string{"one\ntwo\n"},
+			n:        0,
+			expected: nil,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				buffer := claberneteslauncher.NewLogRingBuffer(testCase.size)
+
+				for _, write := range testCase.writes {
+					_, err := buffer.Write([]byte(write))
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				actual := buffer.Lines(testCase.n)
+
+				clabernetestesthelper.MarshaledEqual(t, actual, testCase.expected)
+			})
+	}
+}
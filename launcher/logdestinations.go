@@ -0,0 +1,147 @@
+package launcher
+
+import (
+	"fmt"
+	"io"
+	"log/syslog"
+	"os"
+	"strings"
+	"sync"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+	claberneteslogging "github.com/srl-labs/clabernetes/logging"
+)
+
+const (
+	nodeLogDestinationFile   = "file"
+	nodeLogDestinationStdout = "stdout"
+	nodeLogSyslogScheme      = "syslog://"
+	nodeLogSyslogTag         = "clabernetes-node"
+
+	// defaultNodeLogDestinations is the node log destinations used if none are configured.
+	defaultNodeLogDestinations = nodeLogDestinationFile + "," + nodeLogDestinationStdout
+)
+
+// parseNodeLogDestinations splits the comma separated node log destinations string into its
+// destinations, ensuring each is either "file", "stdout", or a "syslog://host:port" address.
+func parseNodeLogDestinations(destinations string) ([]string, error) {
+	var parsedDestinations []string
+
+	for _, destination := range strings.Split(destinations, ",") {
+		destination = strings.TrimSpace(destination)
+
+		switch {
+		case destination == "":
+			continue
+		case destination == nodeLogDestinationFile, destination == nodeLogDestinationStdout:
+		case strings.HasPrefix(destination, nodeLogSyslogScheme) &&
+			len(destination) > len(nodeLogSyslogScheme):
+		default:
+			return nil, fmt.Errorf(
+				"%w: invalid node log destination %q, must be one of %q, %q, or %shost:port",
+				claberneteserrors.ErrLaunch,
+				destination,
+				nodeLogDestinationFile,
+				nodeLogDestinationStdout,
+				nodeLogSyslogScheme,
+			)
+		}
+
+		parsedDestinations = append(parsedDestinations, destination)
+	}
+
+	return parsedDestinations, nil
+}
+
+// isolatedWriter wraps a node log destination so that a failing destination never fails the
+// writes to any other destination (io.MultiWriter stops at the first error). The first failure is
+// logged and the destination is dropped from then on.
+type isolatedWriter struct {
+	lock        sync.Mutex
+	destination string
+	w           io.Writer
+	logger      claberneteslogging.Instance
+	failed      bool
+}
+
+func (w *isolatedWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.failed {
+		return len(p), nil
+	}
+
+	_, err := w.w.Write(p)
+	if err != nil {
+		w.failed = true
+
+		w.logger.Warnf(
+			"failed writing to node log destination %q, dropping it, err: %s",
+			w.destination,
+			err,
+		)
+	}
+
+	return len(p), nil
+}
+
+// nodeLogDestinations opens all the configured node log destinations and returns a shared writer
+// fanning out to all of them along with how many were opened. Destinations that fail to open (i.e.
+// the node log file on a read-only filesystem) are logged and skipped.
+func (c *clabernetes) nodeLogDestinations(destinations []string) (*sharedLogWriter, int) {
+	writers := make([]io.Writer, 0, len(destinations))
+
+	for _, destination := range destinations {
+		w, err := c.openNodeLogDestination(destination)
+		if err != nil {
+			c.logger.Warnf(
+				"failed opening node log destination %q, skipping it, err: %s",
+				destination,
+				err,
+			)
+
+			continue
+		}
+
+		writers = append(writers, &isolatedWriter{
+			destination: destination,
+			w:           w,
+			logger:      c.logger,
+		})
+	}
+
+	return newSharedLogWriter(io.MultiWriter(writers...)), len(writers)
+}
+
+func (c *clabernetes) openNodeLogDestination(destination string) (io.Writer, error) {
+	switch destination {
+	case nodeLogDestinationFile:
+		// append rather than truncate, as tailing resumes where it left off after a restart
+		return os.OpenFile(
+			c.workPath(nodeLogFileName),
+			os.O_CREATE|os.O_WRONLY|os.O_APPEND,
+			clabernetesconstants.PermissionsEveryoneReadWrite,
+		)
+	case nodeLogDestinationStdout:
+		return c.nodeLogger, nil
+	}
+
+	syslogWriter, err := syslog.Dial(
+		"udp",
+		strings.TrimPrefix(destination, nodeLogSyslogScheme),
+		syslog.LOG_INFO|syslog.LOG_DAEMON,
+		nodeLogSyslogTag,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// one syslog message per log line rather than per (arbitrarily chunked) write
+	return newLineWriter(func(line []byte) error {
+		_, err := syslogWriter.Write(line)
+
+		return err
+	}), nil
+}
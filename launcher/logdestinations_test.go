@@ -0,0 +1,140 @@
+package launcher_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+func TestParseNodeLogDestinations(t *testing.T) {
+	cases := []struct {
+		name      string
+		in        string
+		expected  []string
+		expectErr bool
+	}{
+		{
+			name:     "default",
+			in:       "file,stdout",
+			expected: []string{"file", "stdout"},
+		},
+		{
+			name:     "syslog",
+			in:       "stdout, syslog://10.0.0.1:514,",
+			expected: []string{"stdout", "syslog://10.0.0.1:514"},
+		},
+		{
+			name:      "syslog-no-address",
+			in:        "syslog://",
+			expectErr: true,
+		},
+		{
+			name:      "unknown",
+			in:        "file,kafka://broker:9092",
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				actual, err := claberneteslauncher.ParseNodeLogDestinations(testCase.in)
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+
+				if testCase.expectErr {
+					return
+				}
+
+				clabernetestesthelper.MarshaledEqual(t, actual, testCase.expected)
+			})
+	}
+}
+
+func TestTailContainerLogsUnwritableWorkDir(t *testing.T) {
+	cases := []struct {
+		name             string
+		destinations     string
+		writable         bool
+		expectedLogFiles []string
+		expectErr        bool
+	}{
+		{
+			name:             "writable",
+			destinations:     "file,stdout",
+			writable:         true,
+			expectedLogFiles: []string{"abc123"},
+		},
+		{
+			name:             "unwritable-stdout-remains",
+			destinations:     "file,stdout",
+			expectedLogFiles: []string{},
+		},
+		{
+			name:         "unwritable-no-destination-remains",
+			destinations: "file",
+			expectErr:    true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				t.Setenv(clabernetesconstants.LauncherNodeLogDestinations, testCase.destinations)
+
+				workDir := t.TempDir()
+
+				if !testCase.writable {
+					// a regular file where a directory is expected makes the work dir unusable,
+					// even when running as root
+					blocker := filepath.Join(workDir, "blocker")
+
+					err := os.WriteFile(blocker, nil, 0o644) //nolint:gosec
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					workDir = filepath.Join(blocker, "work")
+				}
+
+				restore := claberneteslauncher.SetCommandRunner(newFakeCommandRunner())
+				defer restore()
+
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+
+				logFiles, err := claberneteslauncher.TailContainerLogsInWorkDir(
+					ctx,
+					workDir,
+					[]string{"abc123"},
+				)
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+
+				if testCase.expectErr {
+					return
+				}
+
+				actualLogFiles := make([]string, 0, len(logFiles))
+
+				for containerID := range logFiles {
+					actualLogFiles = append(actualLogFiles, containerID)
+				}
+
+				clabernetestesthelper.MarshaledEqual(t, actualLogFiles, testCase.expectedLogFiles)
+			})
+	}
+}
@@ -0,0 +1,268 @@
+package launcher
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	logFileRoute              = "/logs"
+	logFileTailQueryKey       = "tail"
+	logFileNodeQueryKey       = "node"
+	logFileFollowQueryKey     = "follow"
+	logFileFollowPollInterval = 500 * time.Millisecond
+	logFileTailBlockSize      = 64 * 1024
+)
+
+// nodeLogFileResolver returns the path of the log file of the given (containerlab) node's
+// container, and whether there is one.
+type nodeLogFileResolver func(nodeName string) (string, bool)
+
+// logFilePath returns the path of the log file to serve -- the combined node log if nodeName is
+// empty, otherwise that node's own log file. Since per container log files are named after the
+// container (not the node) the node name is resolved to its container's log file with
+// resolveNodeLogFile (if not nil); names that do not resolve are taken to be the container (log
+// stream) name the file is named after.
+func logFilePath(
+	workDir, nodeName string,
+	resolveNodeLogFile nodeLogFileResolver,
+) (string, error) {
+	if nodeName == "" {
+		return filepath.Join(workDir, nodeLogFileName), nil
+	}
+
+	if strings.ContainsAny(nodeName, `/\`) || nodeName == "." || nodeName == ".." {
+		return "", fmt.Errorf("invalid node name %q", nodeName)
+	}
+
+	if resolveNodeLogFile != nil {
+		path, ok := resolveNodeLogFile(nodeName)
+		if ok {
+			return path, nil
+		}
+	}
+
+	return filepath.Join(workDir, nodeLogsDirectory, nodeName+".log"), nil
+}
+
+// tailFile returns the last n lines of the given file (all of it if n is negative) and the offset
+// the returned content ends at. The file is read backwards in blocks so that tailing a large log
+// does not mean reading all of it.
+func tailFile(f *os.File, n int) ([]byte, int64, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	size := info.Size()
+
+	if n < 0 {
+		content := make([]byte, size)
+
+		_, err = f.ReadAt(content, 0)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, 0, err
+		}
+
+		return content, size, nil
+	}
+
+	var content []byte
+
+	start := size
+
+	for start > 0 {
+		blockSize := min(int64(logFileTailBlockSize), start)
+
+		start -= blockSize
+
+		block := make([]byte, blockSize)
+
+		_, err = f.ReadAt(block, start)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, 0, err
+		}
+
+		content = append(block, content...)
+
+		// the trailing newline of the last line doesn't start a new line, so we need n+1
+		// newlines to be sure we have n full lines
+		if bytes.Count(content, []byte("\n")) > n {
+			break
+		}
+	}
+
+	lines := bytes.SplitAfter(content, []byte("\n"))
+	if len(lines) > 0 && len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+
+	return bytes.Join(lines, nil), size, nil
+}
+
+// followFile writes anything appended to the given file from offset on to w until ctx is done,
+// starting over from the beginning of the file if it is truncated.
+func followFile(
+	ctx context.Context,
+	f *os.File,
+	offset int64,
+	w io.Writer,
+	flush func(),
+	pollInterval time.Duration,
+) error {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		info, err := f.Stat()
+		if err != nil {
+			return err
+		}
+
+		if info.Size() < offset {
+			offset = 0
+		}
+
+		if info.Size() == offset {
+			continue
+		}
+
+		written, err := io.Copy(w, io.NewSectionReader(f, offset, info.Size()-offset))
+		if err != nil {
+			return err
+		}
+
+		offset += written
+
+		flush()
+	}
+}
+
+// serveLogFile serves the combined node log (or, with the node query param, a single node's log
+// file) from the given work directory. The tail query param limits the response to the last n
+// lines and the follow query param streams anything appended to the log until the client goes
+// away.
+func serveLogFile(
+	w http.ResponseWriter,
+	r *http.Request,
+	workDir string,
+	resolveNodeLogFile nodeLogFileResolver,
+	pollInterval time.Duration,
+) error {
+	query := r.URL.Query()
+
+	n := -1
+
+	if query.Has(logFileTailQueryKey) {
+		var err error
+
+		n, err = strconv.Atoi(query.Get(logFileTailQueryKey))
+		if err != nil || n < 0 {
+			http.Error(w, "invalid tail line count", http.StatusBadRequest)
+
+			return nil
+		}
+	}
+
+	follow, _ := strconv.ParseBool(query.Get(logFileFollowQueryKey))
+
+	path, err := logFilePath(workDir, query.Get(logFileNodeQueryKey), resolveNodeLogFile)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return nil
+	}
+
+	f, err := os.Open(path) //nolint:gosec
+	if err != nil {
+		if os.IsNotExist(err) {
+			http.Error(w, "log file not found", http.StatusNotFound)
+
+			return nil
+		}
+
+		http.Error(w, "failed opening log file", http.StatusInternalServerError)
+
+		return err
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	content, offset, err := tailFile(f, n)
+	if err != nil {
+		http.Error(w, "failed reading log file", http.StatusInternalServerError)
+
+		return err
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+
+	_, err = w.Write(content)
+	if err != nil || !follow {
+		return err
+	}
+
+	controller := http.NewResponseController(w)
+
+	// the server write timeout would otherwise cut the stream off
+	err = controller.SetWriteDeadline(time.Time{})
+	if err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+
+	flush := func() {
+		_ = controller.Flush()
+	}
+
+	flush()
+
+	return followFile(r.Context(), f, offset, w, flush, pollInterval)
+}
+
+func (c *clabernetes) logFileHandler(w http.ResponseWriter, r *http.Request) {
+	c.logger.Debugf("received %q on %q endpoint from %q", r.Method, r.RequestURI, r.RemoteAddr)
+
+	err := serveLogFile(w, r, c.workDir, c.nodeLogFile, logFileFollowPollInterval)
+	if err != nil {
+		c.logger.Warnf("failed serving log file, err: %s", err)
+	}
+}
+
+// nodeLogFile resolves the given node name to the log file of its container through the node
+// container index, see nodeLogFileResolver. Both the index and the log files are only set up
+// during launch, so nothing resolves until startup completes.
+func (c *clabernetes) nodeLogFile(nodeName string) (string, bool) {
+	if !c.startupComplete.Load() {
+		return "", false
+	}
+
+	containerID, ok := c.nodeContainers.lookup(nodeName)
+	if !ok {
+		return "", false
+	}
+
+	path, ok := c.containerLogFiles[containerID]
+
+	return path, ok
+}
@@ -0,0 +1,207 @@
+package launcher_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+func writeLogFileAPITestFiles(t *testing.T) string {
+	t.Helper()
+
+	workDir := t.TempDir()
+
+	err := os.MkdirAll(filepath.Join(workDir, "node-logs"), 0o755) //nolint:gosec
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for path, content := range map[string]string{
+		"node.log":           "srl1 | one\nsrl1 | two\nsrl2 | three\n",
+		"node-logs/srl1.log": "one\ntwo\n",
+		// named after the container, as the per container log files are
+		"node-logs/clab-topo-srl2.log": "three\n",
+	} {
+		err = os.WriteFile(filepath.Join(workDir, path), []byte(content), 0o644) //nolint:gosec
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return workDir
+}
+
+func TestServeLogFile(t *testing.T) {
+	cases := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "combined",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "srl1 | one\nsrl1 | two\nsrl2 | three\n",
+		},
+		{
+			name:           "combined-tail",
+			query:          "?tail=2",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "srl1 | two\nsrl2 | three\n",
+		},
+		{
+			name:           "tail-more-than-available",
+			query:          "?tail=10",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "srl1 | one\nsrl1 | two\nsrl2 | three\n",
+		},
+		{
+			name:           "tail-zero",
+			query:          "?tail=0",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "",
+		},
+		{
+			name:           "node",
+			query:          "?node=srl1&tail=1",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "two\n",
+		},
+		{
+			name:           "node-resolved-to-container",
+			query:          "?node=srl2",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "three\n",
+		},
+		{
+			name:           "container-name",
+			query:          "?node=clab-topo-srl2",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "three\n",
+		},
+		{
+			name:           "unknown-node",
+			query:          "?node=srl9",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "log file not found\n",
+		},
+		{
+			name:           "path-traversal",
+			query:          "?node=../node",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid node name \"../node\"\n",
+		},
+		{
+			name:           "invalid-tail",
+			query:          "?tail=-1",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid tail line count\n",
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				workDir := writeLogFileAPITestFiles(t)
+
+				recorder := httptest.NewRecorder()
+
+				err := claberneteslauncher.ServeLogFile(
+					recorder,
+					httptest.NewRequest(http.MethodGet, "/logs"+testCase.query, http.NoBody),
+					workDir,
+					map[string]string{
+						"srl2": filepath.Join(workDir, "node-logs", "clab-topo-srl2.log"),
+					},
+					time.Millisecond,
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if recorder.Code != testCase.expectedStatus {
+					clabernetestesthelper.FailOutput(t, recorder.Code, testCase.expectedStatus)
+				}
+
+				if recorder.Body.String() != testCase.expectedBody {
+					clabernetestesthelper.FailOutput(
+						t,
+						recorder.Body.String(),
+						testCase.expectedBody,
+					)
+				}
+			})
+	}
+}
+
+func TestServeLogFileFollow(t *testing.T) {
+	workDir := writeLogFileAPITestFiles(t)
+
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = claberneteslauncher.ServeLogFile(w, r, workDir, nil, 10*time.Millisecond)
+		}),
+	)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		server.URL+"/logs?node=srl1&tail=1&follow=true",
+		http.NoBody,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	reader := bufio.NewReader(resp.Body)
+
+	line, err := reader.ReadString('\n')
+	if err != nil || line != "two\n" {
+		clabernetestesthelper.FailOutput(t, line, "two\n")
+	}
+
+	f, err := os.OpenFile(
+		filepath.Join(workDir, "node-logs", "srl1.log"),
+		os.O_APPEND|os.O_WRONLY,
+		0o644,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = f.WriteString("three\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_ = f.Close()
+
+	line, err = reader.ReadString('\n')
+	if err != nil || line != "three\n" {
+		clabernetestesthelper.FailOutput(t, line, "three\n")
+	}
+}
@@ -0,0 +1,628 @@
+package launcher
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+	clabernetesutil "github.com/srl-labs/clabernetes/util"
+)
+
+const (
+	nodeLogFileName   = "node.log"
+	nodeLogsDirectory = "node-logs"
+
+	// defaultTailNodeLogsPrefixFormat is the prefix format TailNodeLogs uses when tailing more than
+	// one node and no node log prefix format is configured, so lines can be told apart.
+	defaultTailNodeLogsPrefixFormat = "{node} | "
+
+	ansiReset = "\x1b[0m"
+)
+
+// nodeLogColors are the ansi colors (red, green, yellow, blue, magenta, cyan and their bright
+// variants) TailAllNodeLogs cycles through to give each node a distinct color.
+var nodeLogColors = []string{ //nolint:gochecknoglobals
+	"\x1b[31m", "\x1b[32m", "\x1b[33m", "\x1b[34m", "\x1b[35m", "\x1b[36m",
+	"\x1b[91m", "\x1b[92m", "\x1b[93m", "\x1b[94m", "\x1b[95m", "\x1b[96m",
+}
+
+// colorizedNodeName returns the given node name wrapped in the color of the node at idx (of the
+// sorted node names being tailed).
+func colorizedNodeName(nodeName string, idx int) string {
+	return nodeLogColors[idx%len(nodeLogColors)] + nodeName + ansiReset
+}
+
+// isTerminal returns true if the given file is a terminal (character device) rather than i.e. a
+// pipe or regular file.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// TailNodeLogs follows the logs of the containers of the given (containerlab) node names, writing
+// them to stdout until interrupted. Node names are resolved by exact match of the containerlab node
+// name label, an unknown node name is an error.
+func TailNodeLogs(nodeNames []string) error {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	prefixFormat := os.Getenv(clabernetesconstants.LauncherNodeLogPrefixFormat)
+	if prefixFormat == "" && len(nodeNames) > 1 {
+		prefixFormat = defaultTailNodeLogsPrefixFormat
+	}
+
+	err := validateNodeLogPrefixFormat(prefixFormat)
+	if err != nil {
+		return err
+	}
+
+	return tailNodeLogs(ctx, os.Stdout, nodeNames, prefixFormat, false)
+}
+
+// TailAllNodeLogs follows the logs of the containers of all nodes of this launcher, writing them to
+// stdout prefixed with the node name until interrupted. When stdout is a terminal, and noColor is
+// not set, each node name is given a distinct color so interleaved lines are easy to tell apart.
+func TailAllNodeLogs(noColor bool) error {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	prefixFormat := clabernetesutil.GetEnvStrOrDefault(
+		clabernetesconstants.LauncherNodeLogPrefixFormat,
+		defaultTailNodeLogsPrefixFormat,
+	)
+
+	err := validateNodeLogPrefixFormat(prefixFormat)
+	if err != nil {
+		return err
+	}
+
+	index, err := newNodeContainerIndex(ctx)
+	if err != nil {
+		return err
+	}
+
+	nodeNames := index.nodeNames()
+	if len(nodeNames) == 0 {
+		return fmt.Errorf("%w: no node containers found", claberneteserrors.ErrContainerNotFound)
+	}
+
+	slices.Sort(nodeNames)
+
+	return tailNodeLogs(
+		ctx,
+		os.Stdout,
+		nodeNames,
+		prefixFormat,
+		!noColor && isTerminal(os.Stdout),
+	)
+}
+
+// tailNodeLogs follows the logs of the containers of the given nodes, writing them to w with the
+// given prefix format, and, if colorize is set, the node name in the prefix colored per node.
+func tailNodeLogs(
+	ctx context.Context,
+	w io.Writer,
+	nodeNames []string,
+	prefixFormat string,
+	colorize bool,
+) error {
+	index, err := newNodeContainerIndex(ctx)
+	if err != nil {
+		return err
+	}
+
+	containerIDs := make([]string, len(nodeNames))
+
+	for idx, nodeName := range nodeNames {
+		containerIDs[idx], err = index.resolve(ctx, nodeName)
+		if err != nil {
+			return err
+		}
+
+		if containerIDs[idx] == "" {
+			return fmt.Errorf(
+				"%w: no container found for node %q",
+				claberneteserrors.ErrContainerNotFound,
+				nodeName,
+			)
+		}
+	}
+
+	sharedW := newSharedLogWriter(w)
+
+	errs := make([]error, len(nodeNames))
+
+	wg := &sync.WaitGroup{}
+
+	for idx, nodeName := range nodeNames {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			prefixNodeName := nodeName
+			if colorize {
+				prefixNodeName = colorizedNodeName(nodeName, idx)
+			}
+
+			out := newPrefixWriter(
+				sharedW.source(),
+				prefixFormat,
+				prefixNodeName,
+				containerIDs[idx],
+			)
+
+			cmd := exec.CommandContext( //nolint:gosec
+				ctx,
+				"docker",
+				"logs",
+				"-f",
+				containerIDs[idx],
+			)
+
+			cmd.Stdout = out
+			cmd.Stderr = out
+
+			err := runner.Run(cmd)
+			if err != nil && ctx.Err() == nil &&
+				!logStreamEndedExpectedly(ctx, containerIDs[idx], err) {
+				errs[idx] = fmt.Errorf(
+					"tailing node %q failed: %w",
+					nodeName,
+					classifyDockerError(err),
+				)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// lockedWriter serializes writes to the wrapped writer so concurrent tails don't interleave lines.
+type lockedWriter struct {
+	lock sync.Mutex
+	w    io.Writer
+}
+
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return w.w.Write(p)
+}
+
+// sharedLogWriter guards a writer (i.e. the node log file) shared by multiple concurrent sources.
+// Each source writes via its own line buffered writer so that only whole lines are ever written,
+// and only one at a time -- concurrent sources therefore can't corrupt each other's lines.
+type sharedLogWriter struct {
+	lockedWriter
+}
+
+func newSharedLogWriter(w io.Writer) *sharedLogWriter {
+	return &sharedLogWriter{
+		lockedWriter: lockedWriter{w: w},
+	}
+}
+
+// source returns a writer for a single source of the shared writer, a source's own writes must
+// not be concurrent but any number of sources may write concurrently.
+func (w *sharedLogWriter) source() io.Writer {
+	return newLineWriter(func(line []byte) error {
+		_, err := w.Write(line)
+
+		return err
+	})
+}
+
+// containerLogTail is a single container log tail, cancel is nil while the tail is stopped.
+type containerLogTail struct {
+	ctx    context.Context
+	start  func(ctx context.Context)
+	cancel context.CancelFunc
+}
+
+// containerLogTails holds each container log tail (by full container id) so that we can stop
+// tailing a single container without stopping everything else, and resume tailing it should the
+// container be started again.
+type containerLogTails struct {
+	lock  sync.Mutex
+	tails map[string]*containerLogTail
+}
+
+func newContainerLogTails() *containerLogTails {
+	return &containerLogTails{
+		tails: map[string]*containerLogTail{},
+	}
+}
+
+// add starts tailing the given container by running start (in its own goroutine) with a context
+// derived from ctx, start is run the same way again whenever the tail is resumed.
+func (t *containerLogTails) add(
+	ctx context.Context,
+	containerID string,
+	start func(ctx context.Context),
+) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	tail := &containerLogTail{
+		ctx:   ctx,
+		start: start,
+	}
+
+	t.tails[containerID] = tail
+
+	t.run(tail)
+}
+
+// run starts the given (stopped) tail, the lock must be held.
+func (t *containerLogTails) run(tail *containerLogTail) {
+	tailCtx, cancel := context.WithCancel(tail.ctx)
+
+	tail.cancel = cancel
+
+	go tail.start(tailCtx)
+}
+
+// stop stops tailing the given container, returning true if there was a running tail to stop.
+func (t *containerLogTails) stop(containerID string) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	tail, ok := t.tails[containerID]
+	if !ok || tail.cancel == nil {
+		return false
+	}
+
+	tail.cancel()
+
+	tail.cancel = nil
+
+	return true
+}
+
+// resume resumes tailing the given (stopped) container, returning true if there was a stopped tail
+// to resume.
+func (t *containerLogTails) resume(containerID string) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	tail, ok := t.tails[containerID]
+	if !ok || tail.cancel != nil || tail.ctx.Err() != nil {
+		return false
+	}
+
+	t.run(tail)
+
+	return true
+}
+
+// tailContainerLogs follows the logs of all the given containers, writing them to the configured
+// node log destinations (by default the node logger and the combined node log file), a
+// per-container log file, and a per-container in memory buffer of recent lines. It returns a map of
+// container id to the path of that container's log file.
+func (c *clabernetes) tailContainerLogs(containerIDs []string) (map[string]string, error) {
+	nodeLogDestinations, err := parseNodeLogDestinations(
+		clabernetesutil.GetEnvStrOrDefault(
+			clabernetesconstants.LauncherNodeLogDestinations,
+			defaultNodeLogDestinations,
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// the per container log files (and log tail state) are best effort, as are the node log
+	// destinations, so logs keep flowing wherever they can on read-only or full filesystems
+	nodeLogsDirErr := os.MkdirAll(
+		c.workPath(nodeLogsDirectory),
+		clabernetesconstants.PermissionsEveryoneAllPermissions,
+	)
+	if nodeLogsDirErr != nil {
+		c.logger.Warnf(
+			"failed creating node logs directory, not writing per container log files, err: %s",
+			nodeLogsDirErr,
+		)
+	}
+
+	nodeOutWriter, openedDestinations := c.nodeLogDestinations(nodeLogDestinations)
+
+	if nodeLogsDirErr != nil && openedDestinations == 0 {
+		return nil, fmt.Errorf(
+			"%w: no viable node log destination, failed creating node logs directory, err: %w",
+			claberneteserrors.ErrLaunch,
+			nodeLogsDirErr,
+		)
+	}
+
+	c.logTailState, err = loadLogTailState(c.workPath(nodeLogsDirectory, logTailStateFileName))
+	if err != nil {
+		c.logger.Warnf("failed loading log tail state, tailing from the start, err: %s", err)
+	}
+
+	if nodeLogsDirErr == nil {
+		go c.saveLogTailState()
+	}
+
+	rateLimit := clabernetesutil.GetEnvIntOrDefault(
+		clabernetesconstants.LauncherNodeLogRateLimit,
+		0,
+	)
+
+	receiveTimestamps := strings.EqualFold(
+		os.Getenv(clabernetesconstants.LauncherNodeLogReceiveTimestamps),
+		clabernetesconstants.True,
+	)
+
+	containerLogFiles := make(map[string]string, len(containerIDs))
+
+	for _, stream := range containerStreams(c.ctx, containerIDs) {
+		containerID, containerLogName := stream.ID, stream.Name
+
+		containerOutWriter := io.MultiWriter(
+			newPrefixWriter(
+				nodeOutWriter.source(),
+				c.nodeLogPrefixFormat,
+				containerLogName,
+				containerID,
+			),
+			c.nodeLogBuffers.add(containerLogName),
+		)
+
+		if nodeLogsDirErr == nil {
+			containerLogFilePath := c.workPath(nodeLogsDirectory, stream.fileName())
+
+			containerLogFile, err := os.OpenFile( //nolint:gosec
+				containerLogFilePath,
+				os.O_CREATE|os.O_WRONLY|os.O_APPEND,
+				clabernetesconstants.PermissionsEveryoneReadWrite,
+			)
+			if err != nil {
+				c.logger.Warnf(
+					"failed creating log file for container id %q, will only write to the node"+
+						" log destinations, err: %s",
+					containerID,
+					err,
+				)
+			} else {
+				containerLogFiles[containerID] = containerLogFilePath
+				containerOutWriter = io.MultiWriter(containerOutWriter, containerLogFile)
+			}
+		}
+
+		if receiveTimestamps {
+			containerOutWriter = newReceiveTimestampWriter(containerOutWriter, time.Now)
+		}
+
+		containerOutWriter = newRateLimitedWriter(
+			containerOutWriter,
+			rateLimit,
+			time.Now,
+			func(suppressed int) {
+				c.logger.Warnf(
+					"container %q exceeded %d log lines per second, %d lines suppressed",
+					containerLogName,
+					rateLimit,
+					suppressed,
+				)
+			},
+		)
+
+		// docker events identify containers by full id, if the container could not be inspected
+		// the (possibly short) id is all we have, and its tail simply won't follow its events
+		tailID := cmp.Or(stream.FullID, containerID)
+
+		c.containerLogTails.add(c.ctx, tailID, func(ctx context.Context) {
+			c.tailContainerLog(ctx, containerID, containerLogName, containerOutWriter)
+		})
+	}
+
+	return containerLogFiles, nil
+}
+
+// tailContainerLog follows the logs of the given container, resuming from the last line written
+// in a previous launcher run if the log tail state has one.
+func (c *clabernetes) tailContainerLog(
+	ctx context.Context,
+	containerID,
+	containerLogName string,
+	w io.Writer,
+) {
+	if strings.EqualFold(
+		os.Getenv(clabernetesconstants.LauncherNodeLogJSONFile),
+		clabernetesconstants.True,
+	) && c.tailContainerJSONLogFile(ctx, containerID, containerLogName, w) {
+		return
+	}
+
+	args := []string{
+		"logs",
+		"-f",
+		"--timestamps",
+	}
+
+	since := c.logTailState.since(containerLogName)
+	if since != "" {
+		c.logger.Debugf("resuming logs for container %q since %s", containerLogName, since)
+
+		args = append(args, "--since", since)
+	}
+
+	args = append(args, containerID)
+
+	w = newTimestampStrippingWriter(w, c.logTailState, containerLogName)
+
+	cmd := exec.CommandContext(ctx, "docker", args...) //nolint:gosec
+
+	cmd.Stdout = w
+	cmd.Stderr = w
+
+	err := runner.Run(cmd)
+	if err == nil || ctx.Err() != nil {
+		return
+	}
+
+	if logStreamEndedExpectedly(c.ctx, containerID, err) {
+		c.logger.Infof(
+			"container %q exited or was removed, stopped tailing its logs", containerLogName,
+		)
+
+		return
+	}
+
+	c.logger.Warnf(
+		"tailing node logs for container id %q failed, err: %s", containerID, err,
+	)
+}
+
+// logStreamEndedExpectedly returns true if a docker logs invocation for the given container failed
+// because the container exited or was removed (rather than because something is actually wrong),
+// such terminations are expected and shouldn't be reported as failures.
+func logStreamEndedExpectedly(ctx context.Context, containerID string, err error) bool {
+	if errors.Is(classifyDockerError(err), claberneteserrors.ErrContainerNotFound) {
+		return true
+	}
+
+	state, _, err := getContainerState(ctx, containerID)
+	if err != nil {
+		return errors.Is(err, claberneteserrors.ErrContainerNotFound)
+	}
+
+	switch state {
+	case containerStateExited, containerStateDead, containerStateRemoving:
+		return true
+	default:
+		return false
+	}
+}
+
+// tailContainerJSONLogFile follows the given container's json-file log directly (rather than via
+// docker logs) so that the stdout/stderr stream of each line is preserved. It returns false if the
+// container does not log to a (readable) json-file so the caller can fall back to docker logs.
+func (c *clabernetes) tailContainerJSONLogFile(
+	ctx context.Context,
+	containerID,
+	containerLogName string,
+	w io.Writer,
+) bool {
+	driver, path, err := getContainerLogPath(ctx, containerID)
+	if err != nil || driver != jsonFileLogDriver || path == "" {
+		c.logger.Debugf(
+			"container %q does not log to a json-file, falling back to docker logs",
+			containerLogName,
+		)
+
+		return false
+	}
+
+	_, err = os.Stat(path)
+	if err != nil {
+		c.logger.Warnf(
+			"cannot read json-file log of container %q, falling back to docker logs, err: %s",
+			containerLogName,
+			err,
+		)
+
+		return false
+	}
+
+	err = c.followJSONLogFile(ctx, path, containerLogName, w)
+	if err != nil && ctx.Err() == nil {
+		c.logger.Warnf(
+			"following json-file log of container %q failed, err: %s", containerLogName, err,
+		)
+	}
+
+	return true
+}
+
+// updateTailsOnContainerEvents watches docker events for containers dying and stops tailing the
+// logs of any container that does so, this way we don't leave orphaned "docker logs -f" processes
+// around. Tails of containers that are started again (i.e. per their restart policy) are resumed.
+func (c *clabernetes) updateTailsOnContainerEvents() {
+	eventsCmd := exec.CommandContext(
+		c.ctx,
+		"docker",
+		"events",
+		"--filter",
+		"type=container",
+		"--filter",
+		"event=die",
+		"--filter",
+		"event=start",
+		"--format",
+		"{{.Action}} {{.ID}}",
+	)
+
+	eventsCmd.Stdout = newLineWriter(func(line []byte) error {
+		action, containerID, _ := strings.Cut(strings.TrimSpace(string(line)), " ")
+		if containerID == "" {
+			return nil
+		}
+
+		if action == "start" {
+			if c.containerLogTails.resume(containerID) {
+				c.logger.Infof("container %q started, resumed tailing its logs", containerID)
+			}
+
+			return nil
+		}
+
+		if c.containerLogTails.stop(containerID) {
+			c.logger.Infof("container %q died, stopped tailing its logs", containerID)
+		}
+
+		c.checkContainerOOMKilled(containerID)
+		c.checkContainerExitCode(containerID)
+
+		return nil
+	})
+
+	err := runner.Run(eventsCmd)
+	if err != nil && c.ctx.Err() == nil {
+		c.logger.Warnf("failed watching docker container events, err: %s", err)
+	}
+}
+
+// getContainerLogName returns the name of the given container (sans the leading slash docker
+// reports) for use in log file names, falling back to the container id if the name is unknown.
+func getContainerLogName(ctx context.Context, containerID string) string {
+	inspectCmd := exec.CommandContext(
+		ctx,
+		"docker",
+		"inspect",
+		"--format",
+		"{{.Name}}",
+		containerID,
+	)
+
+	output, err := runner.Output(inspectCmd)
+	if err != nil {
+		return containerID
+	}
+
+	containerName := strings.TrimPrefix(strings.TrimSpace(string(output)), "/")
+	if containerName == "" {
+		return containerID
+	}
+
+	return containerName
+}
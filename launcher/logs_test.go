@@ -0,0 +1,514 @@
+package launcher_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+func TestLogStreamEndedExpectedly(t *testing.T) {
+	inspectKey := "docker inspect --format " +
+		"{{.State.Status}} {{if .State.Health}}{{.State.Health.Status}}{{end}} abc123"
+
+	cases := []struct {
+		name          string
+		logsErr       error
+		inspectOutput string
+		inspectErr    error
+		expected      bool
+	}{
+		{
+			name:     "logs-no-such-container",
+			logsErr:  &exec.ExitError{Stderr: []byte("Error: No such container: abc123")},
+			expected: true,
+		},
+		{
+			name:          "container-exited",
+			logsErr:       errFakeCommand,
+			inspectOutput: "exited \n",
+			expected:      true,
+		},
+		{
+			name:          "container-removing",
+			logsErr:       errFakeCommand,
+			inspectOutput: "removing \n",
+			expected:      true,
+		},
+		{
+			name:    "container-removed",
+			logsErr: errFakeCommand,
+			inspectErr: &exec.ExitError{
+				Stderr: []byte("Error: No such object: abc123"),
+			},
+			expected: true,
+		},
+		{
+			name:          "container-running",
+			logsErr:       errFakeCommand,
+			inspectOutput: "running \n",
+			expected:      false,
+		},
+		{
+			name:       "docker-unavailable",
+			logsErr:    errFakeCommand,
+			inspectErr: errFakeCommand,
+			expected:   false,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				fakeRunner := newFakeCommandRunner()
+
+				fakeRunner.outputs[inspectKey] = []byte(testCase.inspectOutput)
+				fakeRunner.results[inspectKey] = testCase.inspectErr
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				actual := claberneteslauncher.LogStreamEndedExpectedly(
+					context.Background(),
+					"abc123",
+					testCase.logsErr,
+				)
+				if actual != testCase.expected {
+					clabernetestesthelper.FailOutput(t, actual, testCase.expected)
+				}
+			})
+	}
+}
+
+func TestSharedLogWriterConcurrentSources(t *testing.T) {
+	sourceCount := 8
+	lineCount := 200
+
+	// deliberately not goroutine safe, the shared log writer must serialize writes to it
+	out := &bytes.Buffer{}
+
+	sources := claberneteslauncher.NewSharedLogWriterSources(out, sourceCount)
+
+	wg := &sync.WaitGroup{}
+
+	for sourceIdx, source := range sources {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for lineIdx := range lineCount {
+				line := fmt.Sprintf("source-%d line-%d\n", sourceIdx, lineIdx)
+
+				// split every line across writes so partial lines are in flight concurrently
+				for _, chunk := range []string{line[:5], line[5:11], line[11:]} {
+					_, err := source.Write([]byte(chunk))
+					if err != nil {
+						t.Error(err)
+
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+
+	if len(lines) != sourceCount*lineCount {
+		clabernetestesthelper.FailOutput(t, len(lines), sourceCount*lineCount)
+	}
+
+	nextLineIdx := make([]int, sourceCount)
+
+	for _, line := range lines {
+		var sourceIdx, lineIdx int
+
+		_, err := fmt.Sscanf(line, "source-%d line-%d", &sourceIdx, &lineIdx)
+		if err != nil || line != fmt.Sprintf("source-%d line-%d", sourceIdx, lineIdx) {
+			t.Fatalf("corrupted line %q", line)
+		}
+
+		if lineIdx != nextLineIdx[sourceIdx] {
+			t.Fatalf("out of order line %q, expected line %d", line, nextLineIdx[sourceIdx])
+		}
+
+		nextLineIdx[sourceIdx]++
+	}
+}
+
+func TestTailNodeLogsColorize(t *testing.T) {
+	cases := []struct {
+		name     string
+		colorize bool
+		expected []string
+	}{
+		{
+			name: "plain",
+			expected: []string{
+				"srl1 | booting",
+				"srl1 | ready",
+				"srl2 | booting",
+			},
+		},
+		{
+			name:     "colorized",
+			colorize: true,
+			expected: []string{
+				"\x1b[31msrl1\x1b[0m | booting",
+				"\x1b[31msrl1\x1b[0m | ready",
+				"\x1b[32msrl2\x1b[0m | booting",
+			},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				fakeRunner := newFakeCommandRunner()
+
+				fakeRunner.outputs[`docker ps --all --filter label=containerlab`+
+					` --format {{.Label "clab-node-name"}} {{.ID}}`] = []byte(
+					"srl1 abc123\nsrl2 def456\n",
+				)
+
+				fakeRunner.outputs["docker logs -f abc123"] = []byte("booting\nready\n")
+				fakeRunner.outputs["docker logs -f def456"] = []byte("booting\n")
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				out := &bytes.Buffer{}
+
+				err := claberneteslauncher.TailNodeLogsTo(
+					context.Background(),
+					out,
+					[]string{"srl1", "srl2"},
+					"{node} | ",
+					testCase.colorize,
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				actual := strings.Split(strings.TrimSpace(out.String()), "\n")
+
+				slices.Sort(actual)
+
+				clabernetestesthelper.MarshaledEqual(t, actual, testCase.expected)
+			})
+	}
+}
+
+func TestTailNodeLogsResolve(t *testing.T) {
+	cases := []struct {
+		name          string
+		nodeNames     []string
+		expectErr     bool
+		expected      string
+		expectedCalls map[string]int
+	}{
+		{
+			name:      "resolves-node-container",
+			nodeNames: []string{"srl2"},
+			expected:  "srl2 | booting\n",
+			expectedCalls: map[string]int{
+				"docker logs -f abc123": 0,
+				"docker logs -f def456": 1,
+			},
+		},
+		{
+			name:      "unknown-node",
+			nodeNames: []string{"srl1", "srl3"},
+			expectErr: true,
+			expectedCalls: map[string]int{
+				"docker logs -f abc123": 0,
+				"docker logs -f def456": 0,
+			},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				fakeRunner := newFakeCommandRunner()
+
+				fakeRunner.outputs[`docker ps --all --filter label=containerlab`+
+					` --format {{.Label "clab-node-name"}} {{.ID}}`] = []byte(
+					"srl1 abc123\nsrl2 def456\n",
+				)
+
+				fakeRunner.outputs["docker logs -f abc123"] = []byte("booting\nready\n")
+				fakeRunner.outputs["docker logs -f def456"] = []byte("booting\n")
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				out := &bytes.Buffer{}
+
+				err := claberneteslauncher.TailNodeLogsTo(
+					context.Background(),
+					out,
+					testCase.nodeNames,
+					"{node} | ",
+					false,
+				)
+				if testCase.expectErr {
+					if !errors.Is(err, claberneteserrors.ErrContainerNotFound) {
+						clabernetestesthelper.FailOutput(
+							t,
+							err,
+							claberneteserrors.ErrContainerNotFound,
+						)
+					}
+				} else if err != nil {
+					t.Fatal(err)
+				}
+
+				if out.String() != testCase.expected {
+					clabernetestesthelper.FailOutput(t, out.String(), testCase.expected)
+				}
+
+				for cmd, expectedCount := range testCase.expectedCalls {
+					if fakeRunner.calls[cmd] != expectedCount {
+						t.Fatalf(
+							"expected %d %q calls, got %d",
+							expectedCount,
+							cmd,
+							fakeRunner.calls[cmd],
+						)
+					}
+				}
+			})
+	}
+}
+
+func TestContainerLogTailsStop(t *testing.T) {
+	shortID := "abc123def456"
+	fullID := shortID + strings.Repeat("0", 52)
+
+	cases := []struct {
+		name          string
+		tailedID      string
+		stoppedID     string
+		expectStopped bool
+	}{
+		{
+			name:          "full-tailed-full-stopped",
+			tailedID:      fullID,
+			stoppedID:     fullID,
+			expectStopped: true,
+		},
+		{
+			name:          "other-container",
+			tailedID:      fullID,
+			stoppedID:     "fff123def456" + strings.Repeat("0", 52),
+			expectStopped: false,
+		},
+		{
+			name:          "short-tailed-full-stopped",
+			tailedID:      shortID,
+			stoppedID:     fullID,
+			expectStopped: false,
+		},
+		{
+			name:          "full-tailed-short-stopped",
+			tailedID:      fullID,
+			stoppedID:     shortID,
+			expectStopped: false,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				tails := claberneteslauncher.NewContainerLogTails()
+
+				started := claberneteslauncher.AddContainerLogTail(
+					context.Background(),
+					tails,
+					testCase.tailedID,
+				)
+
+				tailCtx := <-started
+
+				stopped := claberneteslauncher.StopContainerLogTail(tails, testCase.stoppedID)
+				if stopped != testCase.expectStopped {
+					clabernetestesthelper.FailOutput(t, stopped, testCase.expectStopped)
+				}
+
+				if cancelled := tailCtx.Err() != nil; cancelled != testCase.expectStopped {
+					clabernetestesthelper.FailOutput(t, cancelled, testCase.expectStopped)
+				}
+
+				// a tail is only stopped once
+				if claberneteslauncher.StopContainerLogTail(tails, testCase.stoppedID) {
+					t.Fatal("expected tail to already be stopped")
+				}
+			})
+	}
+}
+
+func TestContainerLogTailsResume(t *testing.T) {
+	containerID := "abc123def456" + strings.Repeat("0", 52)
+
+	tails := claberneteslauncher.NewContainerLogTails()
+
+	started := claberneteslauncher.AddContainerLogTail(context.Background(), tails, containerID)
+
+	firstCtx := <-started
+
+	// a running tail is not resumed
+	if claberneteslauncher.ResumeContainerLogTail(tails, containerID) {
+		t.Fatal("expected running tail to not be resumed")
+	}
+
+	if !claberneteslauncher.StopContainerLogTail(tails, containerID) {
+		t.Fatal("expected tail to be stopped")
+	}
+
+	if !claberneteslauncher.ResumeContainerLogTail(tails, containerID) {
+		t.Fatal("expected stopped tail to be resumed")
+	}
+
+	resumedCtx := <-started
+
+	if firstCtx.Err() == nil {
+		t.Fatal("expected the stopped tail to be cancelled")
+	}
+
+	if resumedCtx.Err() != nil {
+		t.Fatal("expected the resumed tail to not be cancelled")
+	}
+
+	if claberneteslauncher.ResumeContainerLogTail(tails, "fff123def456") {
+		t.Fatal("expected unknown container to not be resumed")
+	}
+}
+
+func TestUpdateTailsOnContainerEvents(t *testing.T) {
+	eventsKey := "docker events --filter type=container --filter event=die" +
+		" --filter event=start --format {{.Action}} {{.ID}}"
+
+	restartedID := "abc123def456" + strings.Repeat("0", 52)
+	diedID := "def456abc123" + strings.Repeat("0", 52)
+	aliveID := "fff123def456" + strings.Repeat("0", 52)
+
+	fakeRunner := newFakeCommandRunner()
+
+	fakeRunner.outputs[eventsKey] = []byte(
+		"die " + restartedID + "\n" +
+			"die " + diedID + "\n" +
+			"start " + restartedID + "\n",
+	)
+
+	restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+	defer restore()
+
+	tails := claberneteslauncher.NewContainerLogTails()
+
+	restartedStarts := claberneteslauncher.AddContainerLogTail(
+		context.Background(),
+		tails,
+		restartedID,
+	)
+	diedStarts := claberneteslauncher.AddContainerLogTail(context.Background(), tails, diedID)
+	aliveStarts := claberneteslauncher.AddContainerLogTail(context.Background(), tails, aliveID)
+
+	restartedCtx, diedCtx, aliveCtx := <-restartedStarts, <-diedStarts, <-aliveStarts
+
+	claberneteslauncher.UpdateTailsOnContainerEvents(context.Background(), tails)
+
+	if fakeRunner.calls[eventsKey] != 1 {
+		clabernetestesthelper.FailOutput(t, fakeRunner.calls[eventsKey], 1)
+	}
+
+	if restartedCtx.Err() == nil || diedCtx.Err() == nil {
+		t.Fatal("expected the tails of the died containers to be cancelled")
+	}
+
+	resumedCtx := <-restartedStarts
+
+	if resumedCtx.Err() != nil {
+		t.Fatal("expected the tail of the restarted container to be resumed")
+	}
+
+	if len(diedStarts) != 0 {
+		t.Fatal("expected the tail of the died container to not be resumed")
+	}
+
+	if aliveCtx.Err() != nil {
+		t.Fatal("expected the tail of the running container to not be cancelled")
+	}
+}
+
+func TestTailContainerLogsFiles(t *testing.T) {
+	workDir := t.TempDir()
+
+	// a directory where the log file of srl2 would go keeps that file from being created
+	err := os.MkdirAll(filepath.Join(workDir, "node-logs", "clab-topo-srl2.log"), 0o755) //nolint:gosec
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fakeRunner := newFakeCommandRunner()
+
+	fakeRunner.outputs["docker inspect srl1id srl2id"] = []byte(`[
+		{"Id": "srl1id", "Name": "/clab-topo-srl1",
+			"Config": {"Labels": {"clab-node-name": "srl1"}}},
+		{"Id": "srl2id", "Name": "/clab-topo-srl2",
+			"Config": {"Labels": {"clab-node-name": "srl2"}}}
+	]`)
+
+	restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+	defer restore()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logFiles, err := claberneteslauncher.TailContainerLogsInWorkDir(
+		ctx,
+		workDir,
+		[]string{"srl1id", "srl2id"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// files are named after the container, srl2 falls back to only the node log destinations
+	expected := map[string]string{
+		"srl1id": filepath.Join(workDir, "node-logs", "clab-topo-srl1.log"),
+	}
+
+	clabernetestesthelper.MarshaledEqual(t, logFiles, expected)
+
+	_, err = os.Stat(expected["srl1id"])
+	if err != nil {
+		t.Fatalf("expected container log file to be created, err: %s", err)
+	}
+}
@@ -0,0 +1,200 @@
+package launcher
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+)
+
+const (
+	logSearchRoute           = "/logs/search"
+	logSearchQueryQueryKey   = "q"
+	logSearchContextQueryKey = "context"
+
+	// logSearchMaxContext is the largest number of context lines that may be requested around each
+	// match.
+	logSearchMaxContext = 20
+	// logSearchMaxMatches is the largest number of matches returned by a single search, anything
+	// beyond that is dropped and the result marked as truncated.
+	logSearchMaxMatches = 200
+	// logSearchMaxLineLength is the longest log line a search will read.
+	logSearchMaxLineLength = 1024 * 1024
+)
+
+// logSearchMatch is a single line matching a log search along with its surrounding context.
+type logSearchMatch struct {
+	LineNumber int      `json:"lineNumber"`
+	Line       string   `json:"line"`
+	Before     []string `json:"before,omitempty"`
+	After      []string `json:"after,omitempty"`
+}
+
+// logSearchResult is the response of the log search endpoint.
+type logSearchResult struct {
+	Node      string           `json:"node"`
+	Query     string           `json:"query"`
+	Matches   []logSearchMatch `json:"matches"`
+	Truncated bool             `json:"truncated"`
+}
+
+// searchLog returns the lines of r matching pattern along with up to contextLines lines before and
+// after each match. At most maxMatches matches are returned, if there were more than that the
+// truncated return value is true.
+func searchLog(
+	r io.Reader,
+	pattern *regexp.Regexp,
+	contextLines, maxMatches int,
+) (matches []logSearchMatch, truncated bool, err error) {
+	matches = []logSearchMatch{}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, logSearchMaxLineLength)
+
+	var before []string
+
+	// pending holds the indexes of the matches still collecting their trailing context
+	var pending []int
+
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := scanner.Text()
+
+		stillPending := pending[:0]
+
+		for _, idx := range pending {
+			matches[idx].After = append(matches[idx].After, line)
+
+			if len(matches[idx].After) < contextLines {
+				stillPending = append(stillPending, idx)
+			}
+		}
+
+		pending = stillPending
+
+		if pattern.MatchString(line) {
+			if len(matches) >= maxMatches {
+				truncated = true
+			} else {
+				matches = append(matches, logSearchMatch{
+					LineNumber: lineNumber,
+					Line:       line,
+					Before:     append([]string(nil), before...),
+				})
+
+				if contextLines > 0 {
+					pending = append(pending, len(matches)-1)
+				}
+			}
+		}
+
+		if truncated && len(pending) == 0 {
+			break
+		}
+
+		if contextLines > 0 {
+			if len(before) == contextLines {
+				before = before[1:]
+			}
+
+			before = append(before, line)
+		}
+	}
+
+	return matches, truncated, scanner.Err()
+}
+
+// serveLogSearch serves the lines of a node's log file matching the regular expression in the q
+// query param as json, with the number of lines of context around each match set by the context
+// query param.
+func serveLogSearch(
+	w http.ResponseWriter,
+	r *http.Request,
+	workDir string,
+	resolveNodeLogFile nodeLogFileResolver,
+) error {
+	query := r.URL.Query()
+
+	nodeName := query.Get(logFileNodeQueryKey)
+	if nodeName == "" {
+		http.Error(w, "node is required", http.StatusBadRequest)
+
+		return nil
+	}
+
+	pattern, err := regexp.Compile(query.Get(logSearchQueryQueryKey))
+	if err != nil || query.Get(logSearchQueryQueryKey) == "" {
+		http.Error(w, "invalid or missing search query", http.StatusBadRequest)
+
+		return nil
+	}
+
+	var contextLines int
+
+	if query.Has(logSearchContextQueryKey) {
+		contextLines, err = strconv.Atoi(query.Get(logSearchContextQueryKey))
+		if err != nil || contextLines < 0 || contextLines > logSearchMaxContext {
+			http.Error(
+				w,
+				fmt.Sprintf("invalid context line count, must be 0-%d", logSearchMaxContext),
+				http.StatusBadRequest,
+			)
+
+			return nil
+		}
+	}
+
+	path, err := logFilePath(workDir, nodeName, resolveNodeLogFile)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return nil
+	}
+
+	f, err := os.Open(path) //nolint:gosec
+	if err != nil {
+		if os.IsNotExist(err) {
+			http.Error(w, "log file not found", http.StatusNotFound)
+
+			return nil
+		}
+
+		http.Error(w, "failed opening log file", http.StatusInternalServerError)
+
+		return err
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	matches, truncated, err := searchLog(f, pattern, contextLines, logSearchMaxMatches)
+	if err != nil && !errors.Is(err, bufio.ErrTooLong) {
+		http.Error(w, "failed reading log file", http.StatusInternalServerError)
+
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	return json.NewEncoder(w).Encode(logSearchResult{
+		Node:    nodeName,
+		Query:   pattern.String(),
+		Matches: matches,
+		// a line too long to read ends the search early, so flag the result as incomplete
+		Truncated: truncated || err != nil,
+	})
+}
+
+func (c *clabernetes) logSearchHandler(w http.ResponseWriter, r *http.Request) {
+	c.logger.Debugf("received %q on %q endpoint from %q", r.Method, r.RequestURI, r.RemoteAddr)
+
+	err := serveLogSearch(w, r, c.workDir, c.nodeLogFile)
+	if err != nil {
+		c.logger.Warnf("failed serving log search, err: %s", err)
+	}
+}
@@ -0,0 +1,166 @@
+package launcher_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+func TestServeLogSearch(t *testing.T) {
+	cases := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "match",
+			query:          "?node=srl2&q=err",
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"node":"srl2","query":"err","matches":[` +
+				`{"lineNumber":3,"line":"err one"},{"lineNumber":6,"line":"err two"}` +
+				`],"truncated":false}` + "\n",
+		},
+		{
+			name:           "context",
+			query:          "?node=srl2&q=err&context=1",
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"node":"srl2","query":"err","matches":[` +
+				`{"lineNumber":3,"line":"err one","before":["b"],"after":["c"]},` +
+				`{"lineNumber":6,"line":"err two","before":["d"]}` +
+				`],"truncated":false}` + "\n",
+		},
+		{
+			name:           "context-overlapping",
+			query:          "?node=srl2&q=err&context=3",
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"node":"srl2","query":"err","matches":[` +
+				`{"lineNumber":3,"line":"err one","before":["a","b"],` +
+				`"after":["c","d","err two"]},` +
+				`{"lineNumber":6,"line":"err two","before":["err one","c","d"]}` +
+				`],"truncated":false}` + "\n",
+		},
+		{
+			name:           "no-match",
+			query:          "?node=srl2&q=nope",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"node":"srl2","query":"nope","matches":[],"truncated":false}` + "\n",
+		},
+		{
+			name:           "missing-node",
+			query:          "?q=err",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "node is required\n",
+		},
+		{
+			name:           "invalid-query",
+			query:          "?node=srl2&q=(",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid or missing search query\n",
+		},
+		{
+			name:           "invalid-context",
+			query:          "?node=srl2&q=err&context=100",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid context line count, must be 0-20\n",
+		},
+		{
+			name:           "unknown-node",
+			query:          "?node=srl9&q=err",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "log file not found\n",
+		},
+		{
+			name:           "path-traversal",
+			query:          "?node=../node&q=err",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid node name \"../node\"\n",
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				workDir := writeLogFileAPITestFiles(t)
+
+				err := os.WriteFile(
+					filepath.Join(workDir, "node-logs", "srl2.log"),
+					[]byte("a\nb\nerr one\nc\nd\nerr two\n"),
+					0o644, //nolint:gosec
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				recorder := httptest.NewRecorder()
+
+				err = claberneteslauncher.ServeLogSearch(
+					recorder,
+					httptest.NewRequest(
+						http.MethodGet,
+						"/logs/search"+testCase.query,
+						http.NoBody,
+					),
+					workDir,
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if recorder.Code != testCase.expectedStatus {
+					clabernetestesthelper.FailOutput(t, recorder.Code, testCase.expectedStatus)
+				}
+
+				if recorder.Body.String() != testCase.expectedBody {
+					clabernetestesthelper.FailOutput(
+						t,
+						recorder.Body.String(),
+						testCase.expectedBody,
+					)
+				}
+			})
+	}
+}
+
+func TestServeLogSearchTruncated(t *testing.T) {
+	workDir := writeLogFileAPITestFiles(t)
+
+	err := os.WriteFile(
+		filepath.Join(workDir, "node-logs", "srl2.log"),
+		[]byte(strings.Repeat("err\n", 500)),
+		0o644, //nolint:gosec
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recorder := httptest.NewRecorder()
+
+	err = claberneteslauncher.ServeLogSearch(
+		recorder,
+		httptest.NewRequest(http.MethodGet, "/logs/search?node=srl2&q=err", http.NoBody),
+		workDir,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := recorder.Body.String()
+
+	if strings.Count(body, `"line":"err"`) != 200 {
+		clabernetestesthelper.FailOutput(t, strings.Count(body, `"line":"err"`), 200)
+	}
+
+	if !strings.HasSuffix(body, `"truncated":true}`+"\n") {
+		t.Fatalf("expected truncated result, got %q", body[len(body)-40:])
+	}
+}
@@ -0,0 +1,158 @@
+package launcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+)
+
+const (
+	logTailStateFileName     = ".log-tail-state.json"
+	logTailStateSaveInterval = 5 * time.Second
+)
+
+// logTailState tracks the timestamp of the last log line written for each container (by container
+// name, as ids change when containers are recreated) and persists it to the work directory, so
+// that after a launcher restart tailing resumes where it left off rather than from the start.
+type logTailState struct {
+	lock  sync.Mutex
+	path  string
+	dirty bool
+	// Containers maps container name to the docker timestamp of the last written log line.
+	Containers map[string]string `json:"containers"`
+}
+
+// loadLogTailState loads the log tail state from the given path, a missing (first run) or
+// unreadable state file simply results in an empty state.
+func loadLogTailState(path string) (*logTailState, error) {
+	state := &logTailState{
+		path:       path,
+		Containers: map[string]string{},
+	}
+
+	content, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return state, nil
+		}
+
+		return state, err
+	}
+
+	err = json.Unmarshal(content, state)
+	if err != nil || state.Containers == nil {
+		state.Containers = map[string]string{}
+	}
+
+	return state, err
+}
+
+// since returns the timestamp of the last written log line of the given container, or an empty
+// string if there is none.
+func (s *logTailState) since(containerName string) string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.Containers[containerName]
+}
+
+func (s *logTailState) update(containerName, timestamp string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.Containers[containerName] = timestamp
+	s.dirty = true
+}
+
+// save writes the state to disk if anything changed since the last save.
+func (s *logTailState) save() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !s.dirty {
+		return nil
+	}
+
+	content, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(s.path, content, clabernetesconstants.PermissionsEveryoneReadWrite)
+	if err != nil {
+		return err
+	}
+
+	s.dirty = false
+
+	return nil
+}
+
+// newTimestampStrippingWriter returns a writer for the output of "docker logs --timestamps" that
+// records the timestamp of each line in the log tail state and writes the line sans timestamp to w.
+// Since "docker logs --since" is inclusive, lines not newer than the last line written in a
+// previous run (per the state at the time the writer is created) are dropped rather than written
+// again.
+func newTimestampStrippingWriter(
+	w io.Writer,
+	state *logTailState,
+	containerName string,
+) io.Writer {
+	// an empty or unparsable previous timestamp leaves resumeAfter zero, so nothing is dropped
+	resumeAfter, _ := time.Parse(time.RFC3339Nano, state.since(containerName))
+
+	return newLineWriter(func(line []byte) error {
+		timestamp, rest, ok := bytes.Cut(line, []byte(" "))
+		if !ok {
+			_, err := w.Write(line)
+
+			return err
+		}
+
+		lineTime, err := time.Parse(time.RFC3339Nano, string(timestamp))
+		if err != nil {
+			// not a timestamped line (should not happen), pass it along as is
+			_, err = w.Write(line)
+
+			return err
+		}
+
+		if !lineTime.After(resumeAfter) {
+			return nil
+		}
+
+		_, err = w.Write(rest)
+		if err != nil {
+			return err
+		}
+
+		state.update(containerName, string(timestamp))
+
+		return nil
+	})
+}
+
+// saveLogTailState periodically persists the log tail state until the launcher context is done,
+// the final save happens on the way out of startup.
+func (c *clabernetes) saveLogTailState() {
+	ticker := time.NewTicker(logTailStateSaveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+			err := c.logTailState.save()
+			if err != nil {
+				c.logger.Warnf("failed saving log tail state, err: %s", err)
+			}
+		}
+	}
+}
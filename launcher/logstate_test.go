@@ -0,0 +1,89 @@
+package launcher_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+func TestTailTimestampedLog(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "state.json")
+
+	written, since, err := claberneteslauncher.TailTimestampedLog(
+		statePath,
+		"srl1",
+		[]byte(
+			"2024-01-01T00:00:00.000000001Z first line\n"+
+				"2024-01-01T00:00:01.000000002Z second line\n"+
+				"2024-01-01T00:00:02.000000003Z partial",
+		),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedWritten := "first line\nsecond line\n"
+
+	if string(written) != expectedWritten {
+		clabernetestesthelper.FailOutput(t, string(written), expectedWritten)
+	}
+
+	expectedSince := "2024-01-01T00:00:01.000000002Z"
+
+	if since != expectedSince {
+		clabernetestesthelper.FailOutput(t, since, expectedSince)
+	}
+
+	// first run for another container, no state to resume from
+	_, since, err = claberneteslauncher.TailTimestampedLog(statePath, "srl2", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if since != "" {
+		clabernetestesthelper.FailOutput(t, since, "")
+	}
+}
+
+func TestTailTimestampedLogResume(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "state.json")
+
+	_, _, err := claberneteslauncher.TailTimestampedLog(
+		statePath,
+		"srl1",
+		[]byte(
+			"2024-01-01T00:00:00.000000001Z first line\n"+
+				"2024-01-01T00:00:01.000000002Z second line\n",
+		),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// after a restart docker logs --since the saved timestamp includes the line at that timestamp
+	written, since, err := claberneteslauncher.TailTimestampedLog(
+		statePath,
+		"srl1",
+		[]byte(
+			"2024-01-01T00:00:01.000000002Z second line\n"+
+				"2024-01-01T00:00:03.000000004Z third line\n",
+		),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedWritten := "third line\n"
+
+	if string(written) != expectedWritten {
+		clabernetestesthelper.FailOutput(t, string(written), expectedWritten)
+	}
+
+	expectedSince := "2024-01-01T00:00:03.000000004Z"
+
+	if since != expectedSince {
+		clabernetestesthelper.FailOutput(t, since, expectedSince)
+	}
+}
@@ -0,0 +1,228 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+	claberneteslogging "github.com/srl-labs/clabernetes/logging"
+	"gopkg.in/yaml.v3"
+)
+
+const overlayNetworkDriver = "overlay"
+
+// overlayNetworkConfig is the config of the (multi-host) network the launcher creates or joins. The
+// driver defaults to docker's overlay driver which requires the daemon to be part of a swarm, any
+// other (plugin) driver providing an external datapath works as well.
+type overlayNetworkConfig struct {
+	Name         string            `yaml:"name"`
+	Driver       string            `yaml:"driver"`
+	Subnet       string            `yaml:"subnet"`
+	Attachable   bool              `yaml:"attachable"`
+	DriverOpts   map[string]string `yaml:"driverOpts"`
+	ConnectNodes bool              `yaml:"connectNodes"`
+}
+
+// createArgs returns the docker network create arguments for creating the network.
+func (n *overlayNetworkConfig) createArgs() []string {
+	args := []string{"create", "--driver", n.Driver}
+
+	if n.Attachable {
+		args = append(args, "--attachable")
+	}
+
+	if n.Subnet != "" {
+		args = append(args, "--subnet", n.Subnet)
+	}
+
+	optKeys := make([]string, 0, len(n.DriverOpts))
+
+	for key := range n.DriverOpts {
+		optKeys = append(optKeys, key)
+	}
+
+	slices.Sort(optKeys)
+
+	for _, key := range optKeys {
+		args = append(args, "--opt", fmt.Sprintf("%s=%s", key, n.DriverOpts[key]))
+	}
+
+	return append(args, n.Name)
+}
+
+// loadOverlayNetworkConfig loads the overlay network config from the LauncherOverlayNetworkConfig
+// env var, returning nil if it is unset.
+func loadOverlayNetworkConfig() (*overlayNetworkConfig, error) {
+	rawConfig := os.Getenv(clabernetesconstants.LauncherOverlayNetworkConfig)
+	if rawConfig == "" {
+		return nil, nil //nolint:nilnil
+	}
+
+	config := &overlayNetworkConfig{}
+
+	err := yaml.Unmarshal([]byte(rawConfig), config)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"%w: invalid overlay network config, err: %w",
+			claberneteserrors.ErrLaunch,
+			err,
+		)
+	}
+
+	if config.Name == "" {
+		return nil, fmt.Errorf(
+			"%w: overlay network config must set a network name",
+			claberneteserrors.ErrLaunch,
+		)
+	}
+
+	if config.Driver == "" {
+		config.Driver = overlayNetworkDriver
+	}
+
+	if config.Subnet != "" {
+		_, _, err = net.ParseCIDR(config.Subnet)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"%w: invalid overlay network subnet %q, must be in CIDR notation, err: %w",
+				claberneteserrors.ErrLaunch,
+				config.Subnet,
+				err,
+			)
+		}
+	}
+
+	return config, nil
+}
+
+// ensureNetwork creates the configured network, or joins it if it already exists (i.e. it was
+// created by another launcher) and uses the configured driver.
+func ensureNetwork(
+	ctx context.Context,
+	logger claberneteslogging.Instance,
+	config *overlayNetworkConfig,
+) error {
+	existing, err := inspectNetwork(ctx, config.Name)
+
+	switch {
+	case err == nil:
+		if existing.Driver != config.Driver {
+			return fmt.Errorf(
+				"%w: network %q already exists with driver %q, expected driver %q",
+				claberneteserrors.ErrLaunch,
+				config.Name,
+				existing.Driver,
+				config.Driver,
+			)
+		}
+
+		logger.Infof("joining existing %s network %q", config.Driver, config.Name)
+
+		return nil
+	case errors.Is(err, claberneteserrors.ErrNetworkNotFound):
+	default:
+		return err
+	}
+
+	logger.Infof("creating %s network %q...", config.Driver, config.Name)
+
+	return runDockerNetworkCmd(ctx, logger, config.createArgs()...)
+}
+
+// connectNetwork connects the given containers to the given network, containers that are already
+// connected are left alone. Container ids may be short (as listed by docker ps) while the network
+// lists full ids, so ids are matched by prefix.
+func connectNetwork(
+	ctx context.Context,
+	logger claberneteslogging.Instance,
+	name string,
+	containerIDs []string,
+) error {
+	existing, err := inspectNetwork(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	for _, containerID := range containerIDs {
+		connected := slices.ContainsFunc(
+			slices.Collect(maps.Keys(existing.Containers)),
+			func(connectedID string) bool {
+				return strings.HasPrefix(connectedID, containerID)
+			},
+		)
+		if connected {
+			continue
+		}
+
+		err = runDockerNetworkCmd(ctx, logger, "connect", name, containerID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func runDockerNetworkCmd(
+	ctx context.Context,
+	logger claberneteslogging.Instance,
+	args ...string,
+) error {
+	cmd := exec.CommandContext(ctx, "docker", append([]string{"network"}, args...)...)
+
+	cmd.Stdout = logger
+	cmd.Stderr = logger
+
+	return classifyDockerError(runner.Run(cmd))
+}
+
+// setupOverlayNetwork creates or joins the configured overlay network ahead of launching
+// containerlab so the topology can reference it.
+func (c *clabernetes) setupOverlayNetwork() {
+	// validated in validateConfig
+	config, _ := loadOverlayNetworkConfig()
+	if config == nil {
+		return
+	}
+
+	err := ensureNetwork(c.ctx, c.logger, config)
+	if err != nil {
+		c.logger.Fatalf("failed setting up overlay network %q, err: %s", config.Name, err)
+	}
+}
+
+// connectNodesToOverlayNetwork connects all node containers to the configured overlay network if
+// the config asks for it.
+func (c *clabernetes) connectNodesToOverlayNetwork() {
+	config, _ := loadOverlayNetworkConfig()
+	if config == nil || !config.ConnectNodes {
+		return
+	}
+
+	var containerIDs []string
+
+	for _, nodeName := range c.nodeContainers.nodeNames() {
+		containerID, _ := c.nodeContainers.lookup(nodeName)
+
+		containerIDs = append(containerIDs, containerID)
+	}
+
+	slices.Sort(containerIDs)
+
+	err := connectNetwork(c.ctx, c.logger, config.Name, containerIDs)
+	if err != nil {
+		c.logger.Warnf(
+			"failed connecting node containers to overlay network %q, err: %s",
+			config.Name,
+			err,
+		)
+	}
+}
@@ -0,0 +1,175 @@
+package launcher_test
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+func TestOverlayNetworkCreateArgs(t *testing.T) {
+	cases := []struct {
+		name      string
+		config    string
+		expected  []string
+		expectErr bool
+	}{
+		{
+			name:     "unset",
+			config:   "",
+			expected: nil,
+		},
+		{
+			name:   "defaults",
+			config: "name: fabric",
+			expected: []string{
+				"create", "--driver", "overlay", "fabric",
+			},
+		},
+		{
+			name: "full",
+			config: `name: fabric
+driver: weaveworks/net-plugin
+subnet: 10.100.0.0/16
+attachable: true
+driverOpts:
+  mtu: "9000"
+  encrypted: "true"`,
+			expected: []string{
+				"create",
+				"--driver",
+				"weaveworks/net-plugin",
+				"--attachable",
+				"--subnet",
+				"10.100.0.0/16",
+				"--opt",
+				"encrypted=true",
+				"--opt",
+				"mtu=9000",
+				"fabric",
+			},
+		},
+		{
+			name:      "missing-name",
+			config:    "driver: overlay",
+			expectErr: true,
+		},
+		{
+			name:      "invalid-subnet",
+			config:    "name: fabric\nsubnet: 10.100.0.0",
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				t.Setenv(clabernetesconstants.LauncherOverlayNetworkConfig, testCase.config)
+
+				actual, err := claberneteslauncher.OverlayNetworkCreateArgs()
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+
+				clabernetestesthelper.MarshaledEqual(t, actual, testCase.expected)
+			})
+	}
+}
+
+func TestEnsureNetwork(t *testing.T) {
+	inspectKey := "docker network inspect --format {{json .}} fabric"
+	createKey := "docker network create --driver overlay --attachable fabric"
+
+	cases := []struct {
+		name                string
+		inspectOutput       string
+		inspectErr          error
+		expectedCreateCalls int
+		expectErr           bool
+	}{
+		{
+			name: "create",
+			inspectErr: &exec.ExitError{
+				Stderr: []byte("Error response from daemon: network fabric not found"),
+			},
+			expectedCreateCalls: 1,
+		},
+		{
+			name:                "join",
+			inspectOutput:       `{"Name":"fabric","Driver":"overlay"}`,
+			expectedCreateCalls: 0,
+		},
+		{
+			name:                "driver-mismatch",
+			inspectOutput:       `{"Name":"fabric","Driver":"bridge"}`,
+			expectedCreateCalls: 0,
+			expectErr:           true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				t.Setenv(
+					clabernetesconstants.LauncherOverlayNetworkConfig,
+					"name: fabric\nattachable: true",
+				)
+
+				fakeRunner := newFakeCommandRunner()
+				fakeRunner.outputs[inspectKey] = []byte(testCase.inspectOutput)
+				fakeRunner.results[inspectKey] = testCase.inspectErr
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				err := claberneteslauncher.EnsureNetwork(context.Background())
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+
+				if fakeRunner.calls[createKey] != testCase.expectedCreateCalls {
+					clabernetestesthelper.FailOutput(
+						t,
+						fakeRunner.calls[createKey],
+						testCase.expectedCreateCalls,
+					)
+				}
+			})
+	}
+}
+
+func TestConnectNetwork(t *testing.T) {
+	fakeRunner := newFakeCommandRunner()
+	fakeRunner.outputs["docker network inspect --format {{json .}} fabric"] = []byte(
+		`{"Name":"fabric","Driver":"overlay","Containers":{"4f66ad9a0b2e8c6a9b6f":{"Name":"srl1"}}}`,
+	)
+
+	restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+	defer restore()
+
+	err := claberneteslauncher.ConnectNetwork(
+		context.Background(),
+		"fabric",
+		[]string{"4f66ad9a0b2e", "9a8b7c6d5e4f"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fakeRunner.calls["docker network connect fabric 4f66ad9a0b2e"] != 0 {
+		t.Fatal("expected already connected container to not be connected again")
+	}
+
+	if fakeRunner.calls["docker network connect fabric 9a8b7c6d5e4f"] != 1 {
+		t.Fatal("expected unconnected container to be connected")
+	}
+}
@@ -0,0 +1,193 @@
+package launcher
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+)
+
+const (
+	// maxDNSSearchDomains is the max number of search domains glibc's resolver honors.
+	maxDNSSearchDomains = 6
+	// maxDNSSearchDomainLength is the max length of a dns name.
+	maxDNSSearchDomainLength = 253
+	// maxDNSNdots is the max value of the ndots resolver option, larger values are capped by the
+	// resolver anyway so they are almost certainly a mistake.
+	maxDNSNdots = 15
+	// maxDNSTimeout and maxDNSAttempts are the caps glibc's resolver applies to the timeout and
+	// attempts options.
+	maxDNSTimeout  = 30
+	maxDNSAttempts = 5
+)
+
+var dnsSearchDomainPattern = regexp.MustCompile( //nolint:gochecknoglobals
+	`^[A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?` +
+		`(\.[A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?)*\.?$`,
+)
+
+// dnsFlagOptions are the resolver options that take no value.
+var dnsFlagOptions = []string{ //nolint:gochecknoglobals
+	"debug",
+	"edns0",
+	"inet6",
+	"no-aaaa",
+	"no-check-names",
+	"no-reload",
+	"no-tld-query",
+	"rotate",
+	"single-request",
+	"single-request-reopen",
+	"trust-ad",
+	"use-vc",
+}
+
+// dnsValueOptions are the resolver options that take a (non-negative integer) value, mapped to
+// their max value.
+var dnsValueOptions = map[string]int{ //nolint:gochecknoglobals
+	"ndots":    maxDNSNdots,
+	"timeout":  maxDNSTimeout,
+	"attempts": maxDNSAttempts,
+}
+
+// parseDNSSearchDomains parses the given comma separated list of dns search domains.
+func parseDNSSearchDomains(s string) ([]string, error) {
+	var searchDomains []string
+
+	for _, searchDomain := range strings.Split(s, ",") {
+		searchDomain = strings.TrimSpace(searchDomain)
+		if searchDomain != "" {
+			searchDomains = append(searchDomains, searchDomain)
+		}
+	}
+
+	if len(searchDomains) > maxDNSSearchDomains {
+		return nil, fmt.Errorf(
+			"%w: too many dns search domains, at most %d are supported",
+			claberneteserrors.ErrLaunch,
+			maxDNSSearchDomains,
+		)
+	}
+
+	for _, searchDomain := range searchDomains {
+		if len(searchDomain) > maxDNSSearchDomainLength ||
+			!dnsSearchDomainPattern.MatchString(searchDomain) {
+			return nil, fmt.Errorf(
+				"%w: invalid dns search domain %q",
+				claberneteserrors.ErrLaunch,
+				searchDomain,
+			)
+		}
+	}
+
+	return searchDomains, nil
+}
+
+// parseDNSOptions parses the given comma separated list of resolver options, i.e. "ndots:2,rotate".
+func parseDNSOptions(s string) ([]string, error) {
+	var options []string
+
+	for _, option := range strings.Split(s, ",") {
+		option = strings.TrimSpace(option)
+		if option == "" {
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(option, ":")
+
+		maxValue, isValueOption := dnsValueOptions[name]
+
+		switch {
+		case isValueOption && hasValue:
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 || n > maxValue {
+				return nil, fmt.Errorf(
+					"%w: invalid value for dns option %q, must be an integer from 0 to %d",
+					claberneteserrors.ErrLaunch,
+					option,
+					maxValue,
+				)
+			}
+		case !isValueOption && !hasValue && slices.Contains(dnsFlagOptions, name):
+		default:
+			return nil, fmt.Errorf(
+				"%w: invalid dns option %q",
+				claberneteserrors.ErrLaunch,
+				option,
+			)
+		}
+
+		options = append(options, option)
+	}
+
+	return options, nil
+}
+
+// loadNodeDNS loads the dns search domains and resolver options to set on all node containers.
+func loadNodeDNS() (searchDomains, options []string, err error) {
+	searchDomains, err = parseDNSSearchDomains(
+		os.Getenv(clabernetesconstants.LauncherNodeDNSSearch),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	options, err = parseDNSOptions(os.Getenv(clabernetesconstants.LauncherNodeDNSOptions))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return searchDomains, options, nil
+}
+
+// applyNodeDNS sets the given search domains and options in the dns section of the given
+// containerlab node definition, replacing any search domains/options already in the topology but
+// leaving any dns servers as is.
+func applyNodeDNS(node map[string]any, searchDomains, options []string) {
+	nodeDNS, ok := node["dns"].(map[string]any)
+	if !ok || nodeDNS == nil {
+		nodeDNS = map[string]any{}
+	}
+
+	if len(searchDomains) > 0 {
+		nodeDNS["search"] = searchDomains
+	}
+
+	if len(options) > 0 {
+		nodeDNS["options"] = options
+	}
+
+	node["dns"] = nodeDNS
+}
+
+// injectNodeDNS adds the configured dns search domains and resolver options (if any) to all node
+// definitions in the containerlab topology so they are set on the node containers when containerlab
+// creates them.
+func (c *clabernetes) injectNodeDNS() {
+	searchDomains, options, err := loadNodeDNS()
+	if err != nil {
+		c.logger.Fatalf("failed loading node dns config, err: %s", err)
+	}
+
+	if len(searchDomains) == 0 && len(options) == 0 {
+		return
+	}
+
+	c.logger.Infof(
+		"injecting dns search domains %q and options %q into all nodes", searchDomains, options,
+	)
+
+	err = patchTopologyNodes(c.topologyPath(), func(_ string, node map[string]any) error {
+		applyNodeDNS(node, searchDomains, options)
+
+		return nil
+	})
+	if err != nil {
+		c.logger.Fatalf("failed injecting node dns config into topology, err: %s", err)
+	}
+}
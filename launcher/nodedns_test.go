@@ -0,0 +1,110 @@
+package launcher_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+const injectNodeDNSTestName = "inject-node-dns"
+
+func TestInjectNodeDNS(t *testing.T) {
+	cases := []struct {
+		name      string
+		search    string
+		options   string
+		expectErr bool
+	}{
+		{
+			name:    "simple",
+			search:  "lab.example, svc.cluster.local",
+			options: "ndots:2,rotate",
+		},
+		{
+			name:    "options-only",
+			options: "ndots:1",
+		},
+		{
+			name:      "invalid-search-domain",
+			search:    "not_a_domain",
+			expectErr: true,
+		},
+		{
+			name:      "too-many-search-domains",
+			search:    "a,b,c,d,e,f,g",
+			expectErr: true,
+		},
+		{
+			name:      "invalid-option",
+			options:   "nope",
+			expectErr: true,
+		},
+		{
+			name:      "invalid-ndots",
+			options:   "ndots:99",
+			expectErr: true,
+		},
+		{
+			name:      "flag-option-with-value",
+			options:   "rotate:1",
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				t.Setenv(clabernetesconstants.LauncherNodeDNSSearch, testCase.search)
+				t.Setenv(clabernetesconstants.LauncherNodeDNSOptions, testCase.options)
+
+				topologyPath := filepath.Join(t.TempDir(), "topo.clab.yaml")
+
+				err := os.WriteFile(
+					topologyPath,
+					clabernetestesthelper.ReadTestFixtureFile(t, "node-dns/topo.clab.yaml"),
+					0o644, //nolint:gosec
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				err = claberneteslauncher.InjectNodeDNS(topologyPath)
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+
+				if testCase.expectErr {
+					return
+				}
+
+				actual, err := os.ReadFile(topologyPath)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				goldenFileName := fmt.Sprintf(
+					"golden/%s/%s.yaml",
+					injectNodeDNSTestName,
+					testCase.name,
+				)
+
+				if *clabernetestesthelper.Update {
+					clabernetestesthelper.WriteTestFixtureFile(t, goldenFileName, actual)
+				}
+
+				expected := clabernetestesthelper.ReadTestFixtureFile(t, goldenFileName)
+
+				if string(actual) != string(expected) {
+					clabernetestesthelper.FailOutput(t, actual, expected)
+				}
+			})
+	}
+}
@@ -0,0 +1,155 @@
+package launcher
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const redactedValue = "REDACTED"
+
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`) //nolint:gochecknoglobals
+
+// secretKeyMarkers are the (lower case) substrings of keys whose values are redacted whenever the
+// launcher logs or otherwise exposes them.
+var secretKeyMarkers = []string{ //nolint:gochecknoglobals
+	"auth",
+	"key",
+	"license",
+	"password",
+	"secret",
+	"token",
+}
+
+// secretLooking returns true if the given key looks like it holds a secret value.
+func secretLooking(key string) bool {
+	lowerKey := strings.ToLower(key)
+
+	for _, marker := range secretKeyMarkers {
+		if strings.Contains(lowerKey, marker) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// loadNodeEnv loads the per node env (a map of node name to env var name to value) from the file
+// at LauncherNodeEnvFile or, if unset, from LauncherNodeEnv -- both in yaml (or json) form.
+func loadNodeEnv() (map[string]map[string]string, error) {
+	var content []byte
+
+	nodeEnvFile := os.Getenv(clabernetesconstants.LauncherNodeEnvFile)
+
+	if nodeEnvFile != "" {
+		var err error
+
+		content, err = os.ReadFile(nodeEnvFile) //nolint:gosec
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		content = []byte(os.Getenv(clabernetesconstants.LauncherNodeEnv))
+	}
+
+	nodeEnv := map[string]map[string]string{}
+
+	err := yaml.Unmarshal(content, &nodeEnv)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"%w: invalid node env, must be a mapping of node name to env var name to value,"+
+				" err: %w",
+			claberneteserrors.ErrLaunch,
+			err,
+		)
+	}
+
+	for nodeName, env := range nodeEnv {
+		for name := range env {
+			if !envNamePattern.MatchString(name) {
+				return nil, fmt.Errorf(
+					"%w: invalid env var name %q for node %q",
+					claberneteserrors.ErrLaunch,
+					name,
+					nodeName,
+				)
+			}
+		}
+	}
+
+	return nodeEnv, nil
+}
+
+// applyNodeEnv merges the given env into the env of the given containerlab node definition, the
+// injected env wins over env already in the topology.
+func applyNodeEnv(node map[string]any, env map[string]string) {
+	nodeEnv, ok := node["env"].(map[string]any)
+	if !ok || nodeEnv == nil {
+		nodeEnv = map[string]any{}
+	}
+
+	for name, value := range env {
+		nodeEnv[name] = value
+	}
+
+	node["env"] = nodeEnv
+}
+
+// redactedEnv returns the given env as sorted "name=value" strings with secret looking values
+// redacted, suitable for logging.
+func redactedEnv(env map[string]string) []string {
+	redacted := make([]string, 0, len(env))
+
+	for name, value := range env {
+		if secretLooking(name) {
+			value = redactedValue
+		}
+
+		redacted = append(redacted, name+"="+value)
+	}
+
+	slices.Sort(redacted)
+
+	return redacted
+}
+
+// injectNodeEnv adds the user provided per node env (if any) to the node definitions in the
+// containerlab topology so it is set on the node containers when containerlab creates them.
+func (c *clabernetes) injectNodeEnv() {
+	nodeEnv, err := loadNodeEnv()
+	if err != nil {
+		c.logger.Fatalf("failed loading node env, err: %s", err)
+	}
+
+	if len(nodeEnv) == 0 {
+		return
+	}
+
+	err = patchTopologyNodes(c.topologyPath(), func(nodeName string, node map[string]any) error {
+		env, ok := nodeEnv[nodeName]
+		if !ok {
+			return nil
+		}
+
+		c.logger.Infof("injecting env %q into node %q", redactedEnv(env), nodeName)
+
+		applyNodeEnv(node, env)
+
+		delete(nodeEnv, nodeName)
+
+		return nil
+	})
+	if err != nil {
+		c.logger.Fatalf("failed injecting node env into topology, err: %s", err)
+	}
+
+	for nodeName := range nodeEnv {
+		c.logger.Warnf("node env provided for node %q but node is not in the topology", nodeName)
+	}
+}
@@ -0,0 +1,99 @@
+package launcher_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+const injectNodeEnvTestName = "inject-node-env"
+
+func TestInjectNodeEnv(t *testing.T) {
+	cases := []struct {
+		name             string
+		nodeEnv          string
+		expectedInjected map[string][]string
+		expectErr        bool
+	}{
+		{
+			name: "simple",
+			nodeEnv: `{"srl1": {"LICENSE_KEY": "abc123", "FEATURE_X": "on"},` +
+				` "not-a-node": {"FOO": "bar"}}`,
+			expectedInjected: map[string][]string{
+				"srl1": {"FEATURE_X=on", "LICENSE_KEY=REDACTED"},
+			},
+		},
+		{
+			name:      "invalid-name",
+			nodeEnv:   `{"srl1": {"NOT-VALID": "x"}}`,
+			expectErr: true,
+		},
+		{
+			name:      "invalid-format",
+			nodeEnv:   `["srl1"]`,
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				t.Setenv(clabernetesconstants.LauncherNodeEnv, testCase.nodeEnv)
+
+				topologyPath := filepath.Join(t.TempDir(), "topo.clab.yaml")
+
+				err := os.WriteFile(
+					topologyPath,
+					clabernetestesthelper.ReadTestFixtureFile(t, "node-env/topo.clab.yaml"),
+					0o644, //nolint:gosec
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				actualInjected, err := claberneteslauncher.InjectNodeEnv(topologyPath)
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+
+				if testCase.expectErr {
+					return
+				}
+
+				clabernetestesthelper.MarshaledEqual(
+					t,
+					actualInjected,
+					testCase.expectedInjected,
+				)
+
+				actual, err := os.ReadFile(topologyPath)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				goldenFileName := fmt.Sprintf(
+					"golden/%s/%s.yaml",
+					injectNodeEnvTestName,
+					testCase.name,
+				)
+
+				if *clabernetestesthelper.Update {
+					clabernetestesthelper.WriteTestFixtureFile(t, goldenFileName, actual)
+				}
+
+				expected := clabernetestesthelper.ReadTestFixtureFile(t, goldenFileName)
+
+				if string(actual) != string(expected) {
+					clabernetestesthelper.FailOutput(t, actual, expected)
+				}
+			})
+	}
+}
@@ -0,0 +1,126 @@
+package launcher
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+)
+
+// standardNodeLabelKeys are the keys of the labels the launcher sets on all node containers, user
+// provided node labels may not use them.
+var standardNodeLabelKeys = []string{ //nolint:gochecknoglobals
+	clabernetesconstants.LabelApp,
+	clabernetesconstants.LabelTopologyOwner,
+	clabernetesconstants.LabelTopologyNode,
+	clabernetesconstants.LabelLauncherInstance,
+}
+
+// launcherLabels returns the labels identifying the containers of this launcher -- the app name,
+// the topology name, and the launcher pod name. Labels whose value is unknown are omitted.
+func launcherLabels(appName string) map[string]string {
+	labels := map[string]string{}
+
+	for key, value := range map[string]string{
+		clabernetesconstants.LabelApp: appName,
+		clabernetesconstants.LabelTopologyOwner: os.Getenv(
+			clabernetesconstants.LauncherTopologyNameEnv,
+		),
+		clabernetesconstants.LabelLauncherInstance: os.Getenv(clabernetesconstants.PodNameEnv),
+	} {
+		if value != "" {
+			labels[key] = value
+		}
+	}
+
+	return labels
+}
+
+// parseNodeLabels parses the given comma separated list of key=value labels, keys must not be
+// empty or contain whitespace and must not be one of the launcher's standard label keys.
+func parseNodeLabels(rawLabels string) (map[string]string, error) {
+	labels := map[string]string{}
+
+	for _, rawLabel := range strings.Split(rawLabels, ",") {
+		rawLabel = strings.TrimSpace(rawLabel)
+		if rawLabel == "" {
+			continue
+		}
+
+		key, value, _ := strings.Cut(rawLabel, "=")
+
+		switch {
+		case key == "" || strings.IndexFunc(key, unicode.IsSpace) != -1:
+			return nil, fmt.Errorf(
+				"%w: invalid node label %q, must be key=value with a key without whitespace",
+				claberneteserrors.ErrLaunch,
+				rawLabel,
+			)
+		case slices.Contains(standardNodeLabelKeys, key):
+			return nil, fmt.Errorf(
+				"%w: node label %q is set by the launcher and cannot be overridden",
+				claberneteserrors.ErrLaunch,
+				key,
+			)
+		}
+
+		labels[key] = value
+	}
+
+	return labels, nil
+}
+
+// applyNodeLabels merges the given labels into the labels of the given containerlab node
+// definition, the given labels win over labels already in the topology.
+func applyNodeLabels(node map[string]any, labels map[string]string) {
+	nodeLabels, ok := node["labels"].(map[string]any)
+	if !ok || nodeLabels == nil {
+		nodeLabels = map[string]any{}
+	}
+
+	for key, value := range labels {
+		nodeLabels[key] = value
+	}
+
+	node["labels"] = nodeLabels
+}
+
+// patchNodeLabels adds the launcher's standard labels and the user provided labels (if any) to all
+// node definitions in the containerlab topology at path.
+func patchNodeLabels(path, appName string) error {
+	labels, err := parseNodeLabels(os.Getenv(clabernetesconstants.LauncherNodeLabels))
+	if err != nil {
+		return err
+	}
+
+	maps.Copy(labels, launcherLabels(appName))
+
+	return patchTopologyNodes(path, func(nodeName string, node map[string]any) error {
+		nodeLabels := maps.Clone(labels)
+
+		nodeLabels[clabernetesconstants.LabelTopologyNode] = nodeName
+
+		applyNodeLabels(node, nodeLabels)
+
+		return nil
+	})
+}
+
+// injectNodeLabels labels all node containers with the launcher's standard and the user provided
+// labels by adding them to the containerlab topology so they are set when containerlab creates
+// the containers. The labels are informational only, so failing to inject them is not fatal.
+func (c *clabernetes) injectNodeLabels() {
+	err := patchNodeLabels(c.topologyPath(), c.appName)
+	if err != nil {
+		c.logger.Warnf(
+			"failed injecting node labels into topology, node containers will not be labeled,"+
+				" err: %s",
+			err,
+		)
+	}
+}
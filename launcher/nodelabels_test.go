@@ -0,0 +1,108 @@
+package launcher_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+const patchNodeLabelsTestName = "patch-node-labels"
+
+func TestPatchNodeLabels(t *testing.T) {
+	cases := []struct {
+		name      string
+		labels    string
+		expectErr bool
+	}{
+		{
+			name: "standard-only",
+		},
+		{
+			name:   "custom",
+			labels: "team=netops, env=lab,existing=2",
+		},
+		{
+			name:      "invalid-key",
+			labels:    "=nope",
+			expectErr: true,
+		},
+		{
+			name:      "reserved-key",
+			labels:    "clabernetes/topologyNode=srl9",
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				t.Setenv(clabernetesconstants.LauncherNodeLabels, testCase.labels)
+				t.Setenv(clabernetesconstants.LauncherTopologyNameEnv, "topo1")
+				t.Setenv(clabernetesconstants.PodNameEnv, "topo1-srl1-abc123")
+
+				topologyPath := filepath.Join(t.TempDir(), "topo.clab.yaml")
+
+				err := os.WriteFile(
+					topologyPath,
+					clabernetestesthelper.ReadTestFixtureFile(t, "node-labels/topo.clab.yaml"),
+					0o644, //nolint:gosec
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				err = claberneteslauncher.PatchNodeLabels(topologyPath, "clabernetes")
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+
+				if testCase.expectErr {
+					return
+				}
+
+				actual, err := os.ReadFile(topologyPath)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				goldenFileName := fmt.Sprintf(
+					"golden/%s/%s.yaml",
+					patchNodeLabelsTestName,
+					testCase.name,
+				)
+
+				if *clabernetestesthelper.Update {
+					clabernetestesthelper.WriteTestFixtureFile(t, goldenFileName, actual)
+				}
+
+				expected := clabernetestesthelper.ReadTestFixtureFile(t, goldenFileName)
+
+				if string(actual) != string(expected) {
+					clabernetestesthelper.FailOutput(t, actual, expected)
+				}
+			})
+	}
+}
+
+func TestInjectNodeLabelsFailureNotFatal(t *testing.T) {
+	// no topology copy in the work dir, so patching the labels fails
+	logger := &recordingLogger{}
+
+	claberneteslauncher.InjectNodeLabels(t.TempDir(), logger)
+
+	if len(logger.fatals) != 0 {
+		t.Fatalf("expected no fatal errors, got %q", logger.fatals)
+	}
+
+	if len(logger.warnings) != 1 {
+		t.Fatalf("expected one warning, got %q", logger.warnings)
+	}
+}
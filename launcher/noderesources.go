@@ -0,0 +1,225 @@
+package launcher
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	nanoCPUs = 1e9
+
+	// postCreateContainerLookupRetry is how long a node container that is not (yet) in the node
+	// container index is looked up for before giving up on it.
+	postCreateContainerLookupRetry = 5 * time.Second
+)
+
+// nodeResources is the memory, cpu, and pids limits of a node container, zero values mean no
+// limit.
+type nodeResources struct {
+	// Memory is a docker size string such as "2g" or "512MiB".
+	Memory    string  `yaml:"memory"`
+	CPUs      float64 `yaml:"cpus"`
+	PidsLimit int     `yaml:"pids-limit"`
+}
+
+// loadNodeResources loads the per node resource limits (a map of node name to memory, cpus, and
+// pids limit) from LauncherNodeResources in yaml (or json) form.
+func loadNodeResources() (map[string]nodeResources, error) {
+	allNodeResources := map[string]nodeResources{}
+
+	err := yaml.Unmarshal(
+		[]byte(os.Getenv(clabernetesconstants.LauncherNodeResources)),
+		&allNodeResources,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"%w: invalid node resources, must be a mapping of node name to memory, cpus, and"+
+				" pids-limit, err: %w",
+			claberneteserrors.ErrLaunch,
+			err,
+		)
+	}
+
+	for nodeName, resources := range allNodeResources {
+		switch {
+		case resources.Memory != "" && !dockerSizePattern.MatchString(resources.Memory):
+			return nil, fmt.Errorf(
+				"%w: invalid memory limit %q for node %q, must be a size such as \"2g\"",
+				claberneteserrors.ErrLaunch,
+				resources.Memory,
+				nodeName,
+			)
+		case resources.CPUs < 0:
+			return nil, fmt.Errorf(
+				"%w: invalid cpus limit %v for node %q, must be positive",
+				claberneteserrors.ErrLaunch,
+				resources.CPUs,
+				nodeName,
+			)
+		case resources.PidsLimit < 0:
+			return nil, fmt.Errorf(
+				"%w: invalid pids limit %d for node %q, must be positive",
+				claberneteserrors.ErrLaunch,
+				resources.PidsLimit,
+				nodeName,
+			)
+		}
+	}
+
+	return allNodeResources, nil
+}
+
+// applyNodeResources sets the memory and cpu limits of the given containerlab node definition,
+// containerlab has no pids limit so that is applied to the container once created, see
+// setContainerPidsLimit.
+func applyNodeResources(node map[string]any, resources nodeResources) {
+	if resources.Memory != "" {
+		node["memory"] = resources.Memory
+	}
+
+	if resources.CPUs > 0 {
+		node["cpu"] = resources.CPUs
+	}
+}
+
+// setContainerPidsLimit sets the pids limit of the given container.
+func setContainerPidsLimit(ctx context.Context, containerID string, pidsLimit int) error {
+	updateCmd := exec.CommandContext(
+		ctx,
+		"docker",
+		"update",
+		"--pids-limit",
+		strconv.Itoa(pidsLimit),
+		containerID,
+	)
+
+	_, err := runner.Output(updateCmd)
+	if err != nil {
+		return classifyDockerError(err)
+	}
+
+	return nil
+}
+
+// appliedResources returns the resource limits docker reports for the given container, formatted
+// for logging.
+func appliedResources(ctx context.Context, containerID string) (string, error) {
+	inspected, err := inspectContainers(ctx, []string{containerID})
+	if err != nil {
+		return "", err
+	}
+
+	result, ok := inspected[containerID]
+	if !ok {
+		return "", fmt.Errorf(
+			"%w: container %q not found",
+			claberneteserrors.ErrContainerNotFound,
+			containerID,
+		)
+	}
+
+	var pidsLimit int64
+
+	if result.HostConfig.PidsLimit != nil {
+		pidsLimit = *result.HostConfig.PidsLimit
+	}
+
+	return fmt.Sprintf(
+		"memory=%d cpus=%s pids-limit=%d",
+		result.HostConfig.Memory,
+		strconv.FormatFloat(float64(result.HostConfig.NanoCPUs)/nanoCPUs, 'f', -1, 64),
+		pidsLimit,
+	), nil
+}
+
+// injectNodeResources adds the user provided per node memory and cpu limits (if any) to the node
+// definitions in the containerlab topology so they are set when containerlab creates the node
+// containers.
+func (c *clabernetes) injectNodeResources() {
+	allNodeResources, err := loadNodeResources()
+	if err != nil {
+		c.logger.Fatalf("failed loading node resources, err: %s", err)
+	}
+
+	if len(allNodeResources) == 0 {
+		return
+	}
+
+	err = patchTopologyNodes(c.topologyPath(), func(nodeName string, node map[string]any) error {
+		resources, ok := allNodeResources[nodeName]
+		if !ok {
+			return nil
+		}
+
+		applyNodeResources(node, resources)
+
+		delete(allNodeResources, nodeName)
+
+		return nil
+	})
+	if err != nil {
+		c.logger.Fatalf("failed injecting node resources into topology, err: %s", err)
+	}
+
+	for nodeName := range allNodeResources {
+		c.logger.Warnf(
+			"node resources provided for node %q but node is not in the topology",
+			nodeName,
+		)
+	}
+}
+
+// applyNodePidsLimits sets the pids limits of the node containers and logs the resource limits
+// docker actually applied to each node with configured resources.
+func (c *clabernetes) applyNodePidsLimits() {
+	// validated in validateConfig
+	allNodeResources, _ := loadNodeResources()
+
+	for nodeName, resources := range allNodeResources {
+		containerID, ok := c.nodeContainers.lookup(nodeName)
+		if !ok {
+			// the node may have only just been created and not been listed yet
+			var err error
+
+			containerID, err = getContainerIDForNodeName(
+				c.ctx,
+				nodeName,
+				false,
+				postCreateContainerLookupRetry,
+			)
+			if err != nil || containerID == "" {
+				c.logger.Warnf(
+					"no container found for node %q, not applying its resources, err: %v",
+					nodeName,
+					err,
+				)
+
+				continue
+			}
+		}
+
+		if resources.PidsLimit > 0 {
+			err := setContainerPidsLimit(c.ctx, containerID, resources.PidsLimit)
+			if err != nil {
+				c.logger.Warnf("failed setting node %q pids limit, err: %s", nodeName, err)
+			}
+		}
+
+		applied, err := appliedResources(c.ctx, containerID)
+		if err != nil {
+			c.logger.Warnf("failed reading node %q resource limits, err: %s", nodeName, err)
+
+			continue
+		}
+
+		c.logger.Infof("node %q resource limits: %s", nodeName, applied)
+	}
+}
@@ -0,0 +1,132 @@
+package launcher_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+const injectNodeResourcesTestName = "inject-node-resources"
+
+func TestInjectNodeResources(t *testing.T) {
+	cases := []struct {
+		name          string
+		nodeResources string
+		expectErr     bool
+	}{
+		{
+			name: "simple",
+			nodeResources: `{"srl1": {"memory": "2g", "cpus": 1.5, "pids-limit": 4096},` +
+				` "srl2": {"pids-limit": 1024}, "not-a-node": {"memory": "1g"}}`,
+		},
+		{
+			name:          "invalid-memory",
+			nodeResources: `{"srl1": {"memory": "lots"}}`,
+			expectErr:     true,
+		},
+		{
+			name:          "invalid-cpus",
+			nodeResources: `{"srl1": {"cpus": -1}}`,
+			expectErr:     true,
+		},
+		{
+			name:          "invalid-pids-limit",
+			nodeResources: `{"srl1": {"pids-limit": -5}}`,
+			expectErr:     true,
+		},
+		{
+			name:          "invalid-format",
+			nodeResources: `["srl1"]`,
+			expectErr:     true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				t.Setenv(clabernetesconstants.LauncherNodeResources, testCase.nodeResources)
+
+				topologyPath := filepath.Join(t.TempDir(), "topo.clab.yaml")
+
+				err := os.WriteFile(
+					topologyPath,
+					clabernetestesthelper.ReadTestFixtureFile(t, "node-env/topo.clab.yaml"),
+					0o644, //nolint:gosec
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				err = claberneteslauncher.InjectNodeResources(topologyPath)
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+
+				if testCase.expectErr {
+					return
+				}
+
+				actual, err := os.ReadFile(topologyPath)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				goldenFileName := fmt.Sprintf(
+					"golden/%s/%s.yaml",
+					injectNodeResourcesTestName,
+					testCase.name,
+				)
+
+				if *clabernetestesthelper.Update {
+					clabernetestesthelper.WriteTestFixtureFile(t, goldenFileName, actual)
+				}
+
+				expected := clabernetestesthelper.ReadTestFixtureFile(t, goldenFileName)
+
+				if string(actual) != string(expected) {
+					clabernetestesthelper.FailOutput(t, actual, expected)
+				}
+			})
+	}
+}
+
+func TestSetContainerPidsLimit(t *testing.T) {
+	fakeRunner := newFakeCommandRunner()
+
+	fakeRunner.outputs["docker inspect abc123"] = []byte(
+		`[{"Id": "abc123", "HostConfig": {"Memory": 2147483648, "NanoCpus": 1500000000,` +
+			` "PidsLimit": 4096}}]`,
+	)
+
+	restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+	defer restore()
+
+	err := claberneteslauncher.SetContainerPidsLimit(context.Background(), "abc123", 4096)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fakeRunner.calls["docker update --pids-limit 4096 abc123"] != 1 {
+		t.Fatalf("expected pids limit to be updated, calls: %v", fakeRunner.calls)
+	}
+
+	applied, err := claberneteslauncher.AppliedResources(context.Background(), "abc123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "memory=2147483648 cpus=1.5 pids-limit=4096"
+
+	if applied != expected {
+		clabernetestesthelper.FailOutput(t, applied, expected)
+	}
+}
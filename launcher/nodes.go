@@ -0,0 +1,154 @@
+package launcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	nodesTimeout    = 30 * time.Second
+	nodesTabPadding = 2
+)
+
+// nodeAddress is the resolved container and address information of a single containerlab node.
+type nodeAddress struct {
+	Name        string `json:"name"`
+	ContainerID string `json:"containerID"`
+	Status      string `json:"status,omitempty"`
+	IPv4        string `json:"ipv4,omitempty"`
+	IPv6        string `json:"ipv6,omitempty"`
+}
+
+// PrintNodeAddresses writes the container id, status, and primary addresses of every containerlab
+// node to stdout, as an aligned table or, if asJSON is true, as json.
+func PrintNodeAddresses(asJSON bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), nodesTimeout)
+	defer cancel()
+
+	nodeAddresses, err := listNodeAddresses(ctx)
+	if err != nil {
+		return err
+	}
+
+	return writeNodeAddresses(os.Stdout, nodeAddresses, asJSON)
+}
+
+// listNodeAddresses resolves all containerlab nodes to their container and its primary addresses,
+// sorted by node name.
+func listNodeAddresses(ctx context.Context) ([]nodeAddress, error) {
+	index, err := newNodeContainerIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeNames := index.nodeNames()
+
+	slices.Sort(nodeNames)
+
+	containerIDs := make([]string, 0, len(nodeNames))
+
+	for _, nodeName := range nodeNames {
+		containerID, _ := index.lookup(nodeName)
+
+		containerIDs = append(containerIDs, containerID)
+	}
+
+	inspected, err := inspectContainers(ctx, containerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeAddresses := make([]nodeAddress, len(nodeNames))
+
+	for idx, nodeName := range nodeNames {
+		nodeAddresses[idx] = nodeAddress{
+			Name:        nodeName,
+			ContainerID: containerIDs[idx],
+		}
+
+		container, ok := inspected[containerIDs[idx]]
+		if !ok {
+			// container went away between listing and inspecting
+			continue
+		}
+
+		nodeAddresses[idx].Status = container.State.Status
+		nodeAddresses[idx].IPv4, nodeAddresses[idx].IPv6 = container.primaryAddresses()
+	}
+
+	return nodeAddresses, nil
+}
+
+// primaryAddresses returns the first ipv4 and ipv6 address of the container's networks, in network
+// name order so that the result is stable.
+func (c *containerInspect) primaryAddresses() (ipv4, ipv6 string) {
+	networkNames := make([]string, 0, len(c.NetworkSettings.Networks))
+
+	for networkName := range c.NetworkSettings.Networks {
+		networkNames = append(networkNames, networkName)
+	}
+
+	slices.Sort(networkNames)
+
+	for _, networkName := range networkNames {
+		network := c.NetworkSettings.Networks[networkName]
+
+		if ipv4 == "" {
+			ipv4 = network.IPAddress
+		}
+
+		if ipv6 == "" {
+			ipv6 = network.GlobalIPv6Address
+		}
+	}
+
+	return ipv4, ipv6
+}
+
+func writeNodeAddresses(w io.Writer, nodeAddresses []nodeAddress, asJSON bool) error {
+	if asJSON {
+		encoder := json.NewEncoder(w)
+
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(nodeAddresses)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, nodesTabPadding, ' ', 0)
+
+	_, err := fmt.Fprintln(tw, "NODE\tCONTAINER ID\tSTATUS\tIPV4\tIPV6")
+	if err != nil {
+		return err
+	}
+
+	for _, node := range nodeAddresses {
+		_, err = fmt.Fprintf(
+			tw,
+			"%s\t%s\t%s\t%s\t%s\n",
+			node.Name,
+			node.ContainerID,
+			valueOrDash(node.Status),
+			valueOrDash(node.IPv4),
+			valueOrDash(node.IPv6),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tw.Flush()
+}
+
+func valueOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
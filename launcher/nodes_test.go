@@ -0,0 +1,78 @@
+package launcher_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+const listNodeAddressesTestName = "list-node-addresses"
+
+func TestListNodeAddresses(t *testing.T) {
+	cases := []struct {
+		name   string
+		asJSON bool
+	}{
+		{
+			name:   "table",
+			asJSON: false,
+		},
+		{
+			name:   "json",
+			asJSON: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				fakeRunner := newFakeCommandRunner()
+
+				fakeRunner.outputs[`docker ps --all --filter label=containerlab`+
+					` --format {{.Label "clab-node-name"}} {{.ID}}`] = []byte(
+					"srl2 deadbeef\nsrl1 4f66ad9a0b2e\n",
+				)
+
+				inspectKey := "docker inspect 4f66ad9a0b2e deadbeef"
+
+				fakeRunner.outputs[inspectKey] = clabernetestesthelper.ReadTestFixtureFile(
+					t,
+					"docker-inspect/partial.json",
+				)
+				fakeRunner.results[inspectKey] = errFakeCommand
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				actual, err := claberneteslauncher.ListNodeAddresses(
+					context.Background(),
+					testCase.asJSON,
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				goldenFileName := fmt.Sprintf(
+					"golden/%s/%s.txt",
+					listNodeAddressesTestName,
+					testCase.name,
+				)
+
+				if *clabernetestesthelper.Update {
+					clabernetestesthelper.WriteTestFixtureFile(t, goldenFileName, actual)
+				}
+
+				expected := clabernetestesthelper.ReadTestFixtureFile(t, goldenFileName)
+
+				if string(actual) != string(expected) {
+					clabernetestesthelper.FailOutput(t, actual, expected)
+				}
+			})
+	}
+}
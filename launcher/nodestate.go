@@ -0,0 +1,262 @@
+package launcher
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+)
+
+const (
+	// nodeStateEventsRetryInterval is how long we wait before resubscribing to docker events after
+	// the event stream dropped.
+	nodeStateEventsRetryInterval = 5 * time.Second
+
+	dockerEventHealthStatusPrefix = "health_status: "
+)
+
+// nodeState is the last known state of a node container.
+type nodeState struct {
+	State  string
+	Health string
+}
+
+// dockerEvent is the subset of a docker events (json formatted) event that the launcher cares
+// about.
+type dockerEvent struct {
+	Action string           `json:"Action"`
+	Actor  dockerEventActor `json:"Actor"`
+}
+
+type dockerEventActor struct {
+	ID         string            `json:"ID"`
+	Attributes map[string]string `json:"Attributes"`
+}
+
+// nodeStates is an in memory map of node container id to state, kept up to date from the docker
+// event stream so that readiness and the container watchdog don't need to poll docker. The map is
+// only live (trustworthy) while the event stream is up, callers fall back to polling otherwise.
+type nodeStates struct {
+	lock       sync.RWMutex
+	containers map[string]*nodeState
+	live       bool
+	// changed is signalled (without blocking) whenever an event changed a container's state
+	changed chan struct{}
+}
+
+func newNodeStates() *nodeStates {
+	return &nodeStates{
+		containers: map[string]*nodeState{},
+		changed:    make(chan struct{}, 1),
+	}
+}
+
+// reset replaces the tracked containers with the given container summaries, i.e. when
+// (re)subscribing to the event stream.
+func (s *nodeStates) reset(summaries []containerSummary) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.containers = make(map[string]*nodeState, len(summaries))
+
+	for _, summary := range summaries {
+		state := &nodeState{State: summary.State}
+
+		switch {
+		case strings.Contains(summary.Status, "(healthy)"):
+			state.Health = containerHealthHealthy
+		case strings.Contains(summary.Status, "(unhealthy)"):
+			state.Health = "unhealthy"
+		case strings.Contains(summary.Status, "(health: starting)"):
+			state.Health = "starting"
+		}
+
+		s.containers[summary.ID] = state
+	}
+}
+
+// apply updates the tracked containers with the given docker event, returning true if a
+// container's state changed.
+func (s *nodeStates) apply(event dockerEvent) bool {
+	containerID := event.Actor.ID
+	if containerID == "" {
+		return false
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	state, ok := s.containers[containerID]
+	if !ok {
+		state = &nodeState{}
+	}
+
+	switch {
+	case event.Action == "start" || event.Action == "restart" || event.Action == "unpause":
+		state.State = containerStateRunning
+	case event.Action == "die":
+		state.State = containerStateExited
+	case event.Action == "pause":
+		state.State = "paused"
+	case event.Action == "destroy":
+		delete(s.containers, containerID)
+
+		s.signalChanged()
+
+		return true
+	case strings.HasPrefix(event.Action, dockerEventHealthStatusPrefix):
+		state.Health = strings.TrimPrefix(event.Action, dockerEventHealthStatusPrefix)
+	default:
+		return false
+	}
+
+	s.containers[containerID] = state
+
+	s.signalChanged()
+
+	return true
+}
+
+func (s *nodeStates) signalChanged() {
+	select {
+	case s.changed <- struct{}{}:
+	default:
+	}
+}
+
+func (s *nodeStates) setLive(live bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.live = live
+}
+
+func (s *nodeStates) isLive() bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.live
+}
+
+// runningCount returns the number of tracked containers that are running.
+func (s *nodeStates) runningCount() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var count int
+
+	for _, state := range s.containers {
+		if state.State == containerStateRunning {
+			count++
+		}
+	}
+
+	return count
+}
+
+// watchNodeStates subscribes to docker container events of the containerlab node containers and
+// keeps c.nodeStates up to date from them (if LauncherNodeStateEvents is set). Whenever the event
+// stream drops the node states stop being live -- so readiness and the container watchdog fall back
+// to polling -- and the stream is resubscribed after nodeStateEventsRetryInterval.
+func (c *clabernetes) watchNodeStates() {
+	if !strings.EqualFold(
+		os.Getenv(clabernetesconstants.LauncherNodeStateEvents),
+		clabernetesconstants.True,
+	) {
+		return
+	}
+
+	if !c.dockerSubcommandAvailable("events") {
+		c.logger.Warn("docker events unavailable, node states will be polled")
+
+		return
+	}
+
+	for {
+		err := c.streamNodeStates()
+
+		c.nodeStates.setLive(false)
+
+		if c.ctx.Err() != nil {
+			return
+		}
+
+		c.logger.Warnf(
+			"docker events stream ended, polling node states until resubscribed, err: %v",
+			err,
+		)
+
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-time.After(nodeStateEventsRetryInterval):
+		}
+	}
+}
+
+// streamNodeStates runs a single docker events subscription, seeding the node states once the
+// subscription is up and applying events to it until the stream ends.
+func (c *clabernetes) streamNodeStates() error {
+	eventsCmd := exec.CommandContext(
+		c.ctx,
+		"docker",
+		"events",
+		"--filter",
+		"type=container",
+		"--filter",
+		"label="+containerlabLabLabel,
+		"--format",
+		"{{json .}}",
+	)
+
+	eventsOut, err := eventsCmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	err = eventsCmd.Start()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = eventsCmd.Wait()
+	}()
+
+	// seed only once subscribed so that no event between the listing and subscribing is missed
+	summaries, err := listContainers(c.ctx, true, containerlabLabLabel)
+	if err != nil {
+		_ = eventsCmd.Process.Kill()
+
+		return err
+	}
+
+	c.nodeStates.reset(summaries)
+	c.nodeStates.setLive(true)
+
+	c.logger.Debug("node states are now updated from docker events")
+
+	scanner := bufio.NewScanner(eventsOut)
+
+	for scanner.Scan() {
+		var event dockerEvent
+
+		err = json.Unmarshal(scanner.Bytes(), &event)
+		if err != nil {
+			c.logger.Debugf("failed parsing docker event %q, err: %s", scanner.Text(), err)
+
+			continue
+		}
+
+		if c.nodeStates.apply(event) {
+			c.logger.Debugf("node container %q event %q", event.Actor.ID, event.Action)
+		}
+	}
+
+	return scanner.Err()
+}
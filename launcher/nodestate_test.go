@@ -0,0 +1,77 @@
+package launcher_test
+
+import (
+	"testing"
+
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+func TestNodeStatesAfterEvents(t *testing.T) {
+	cases := []struct {
+		name            string
+		summaries       []claberneteslauncher.ContainerSummary
+		events          []string
+		expected        map[string]string
+		expectedRunning int
+	}{
+		{
+			name: "seeded",
+			summaries: []claberneteslauncher.ContainerSummary{
+				{ID: "abc", State: "running", Status: "Up 2 hours (healthy)"},
+				{ID: "def", State: "running", Status: "Up 2 seconds (health: starting)"},
+				{ID: "ghi", State: "exited", Status: "Exited (137) 1 minute ago"},
+			},
+			expected: map[string]string{
+				"abc": "running/healthy",
+				"def": "running/starting",
+				"ghi": "exited/",
+			},
+			expectedRunning: 2,
+		},
+		{
+			name: "events",
+			summaries: []claberneteslauncher.ContainerSummary{
+				{ID: "abc", State: "running", Status: "Up 2 hours"},
+				{ID: "def", State: "running", Status: "Up 2 hours"},
+				{ID: "ghi", State: "exited", Status: "Exited (0) 1 minute ago"},
+			},
+			events: []string{
+				`{"Type":"container","Action":"die","Actor":{"ID":"abc"}}`,
+				`{"Type":"container","Action":"health_status: unhealthy","Actor":{"ID":"def"}}`,
+				`{"Type":"container","Action":"start","Actor":{"ID":"ghi"}}`,
+				`{"Type":"container","Action":"start","Actor":{"ID":"jkl"}}`,
+				`{"Type":"container","Action":"destroy","Actor":{"ID":"jkl"}}`,
+				`{"Type":"container","Action":"exec_start: sr_cli","Actor":{"ID":"def"}}`,
+			},
+			expected: map[string]string{
+				"abc": "exited/",
+				"def": "running/unhealthy",
+				"ghi": "running/",
+			},
+			expectedRunning: 2,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				actual, running, err := claberneteslauncher.NodeStatesAfterEvents(
+					testCase.summaries,
+					testCase.events,
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				clabernetestesthelper.MarshaledEqual(t, actual, testCase.expected)
+
+				if running != testCase.expectedRunning {
+					clabernetestesthelper.FailOutput(t, running, testCase.expectedRunning)
+				}
+			})
+	}
+}
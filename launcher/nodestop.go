@@ -0,0 +1,269 @@
+package launcher
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// nodeStopTimeoutMargin is added on top of the longest configured stop timeout when stopping
+	// nodes during shutdown, so docker has a chance to kill containers that ignore the signal.
+	nodeStopTimeoutMargin = 10 * time.Second
+	// defaultDockerStopTimeout is the stop timeout docker applies when none is given.
+	defaultDockerStopTimeout = 10 * time.Second
+	maxSignalNumber          = 64
+)
+
+var signalNamePattern = regexp.MustCompile( //nolint:gochecknoglobals
+	`^(SIG)?[A-Z][A-Z0-9]*([+-][0-9]+)?$`,
+)
+
+// nodeStopConfig is the stop signal and timeout to stop a node container with, an empty signal or
+// zero timeout means docker's default (the image's stop signal, ten seconds).
+type nodeStopConfig struct {
+	Signal  string `yaml:"signal"`
+	Timeout string `yaml:"timeout"`
+
+	timeout time.Duration
+}
+
+// args returns the docker stop arguments for stopping the given container with this config.
+func (s nodeStopConfig) args(containerID string) []string {
+	args := []string{"stop"}
+
+	if s.Signal != "" {
+		args = append(args, "--signal", s.Signal)
+	}
+
+	if s.timeout > 0 {
+		args = append(
+			args,
+			"--time",
+			strconv.Itoa(int(math.Ceil(s.timeout.Seconds()))),
+		)
+	}
+
+	return append(args, containerID)
+}
+
+// validateStopSignal ensures the given signal looks like something docker will accept -- either a
+// signal number or a signal name with or without the "SIG" prefix.
+func validateStopSignal(signal string) error {
+	n, err := strconv.Atoi(signal)
+	if err == nil {
+		if n < 1 || n > maxSignalNumber {
+			return fmt.Errorf(
+				"%w: invalid stop signal number %d", claberneteserrors.ErrLaunch, n,
+			)
+		}
+
+		return nil
+	}
+
+	if !signalNamePattern.MatchString(signal) {
+		return fmt.Errorf("%w: invalid stop signal %q", claberneteserrors.ErrLaunch, signal)
+	}
+
+	return nil
+}
+
+// loadNodeStopConfig loads the per node stop config (a map of node name to stop signal and
+// timeout) from LauncherNodeStopConfig in yaml (or json) form.
+func loadNodeStopConfig() (map[string]nodeStopConfig, error) {
+	nodeStopConfigs := map[string]nodeStopConfig{}
+
+	err := yaml.Unmarshal(
+		[]byte(os.Getenv(clabernetesconstants.LauncherNodeStopConfig)),
+		&nodeStopConfigs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"%w: invalid node stop config, must be a mapping of node name to signal and timeout,"+
+				" err: %w",
+			claberneteserrors.ErrLaunch,
+			err,
+		)
+	}
+
+	for nodeName, stopConfig := range nodeStopConfigs {
+		if stopConfig.Signal != "" {
+			err = validateStopSignal(stopConfig.Signal)
+			if err != nil {
+				return nil, fmt.Errorf("node %q: %w", nodeName, err)
+			}
+		}
+
+		if stopConfig.Timeout != "" {
+			stopConfig.timeout, err = time.ParseDuration(stopConfig.Timeout)
+			if err != nil || stopConfig.timeout < 0 {
+				return nil, fmt.Errorf(
+					"%w: invalid stop timeout %q for node %q",
+					claberneteserrors.ErrLaunch,
+					stopConfig.Timeout,
+					nodeName,
+				)
+			}
+		}
+
+		nodeStopConfigs[nodeName] = stopConfig
+	}
+
+	return nodeStopConfigs, nil
+}
+
+// stopContainer stops the given container with the given stop config.
+func stopContainer(ctx context.Context, containerID string, stopConfig nodeStopConfig) error {
+	stopCmd := exec.CommandContext(ctx, "docker", stopConfig.args(containerID)...)
+
+	_, err := runner.Output(stopCmd)
+	if err != nil {
+		return classifyDockerError(err)
+	}
+
+	return nil
+}
+
+// loadContainerStopTimeout loads the default stop timeout for node containers from
+// LauncherContainerStopTimeout, zero if unset.
+func loadContainerStopTimeout() (time.Duration, error) {
+	rawTimeout := os.Getenv(clabernetesconstants.LauncherContainerStopTimeout)
+	if rawTimeout == "" {
+		return 0, nil
+	}
+
+	timeout, err := time.ParseDuration(rawTimeout)
+	if err != nil || timeout < 0 {
+		return 0, fmt.Errorf(
+			"%w: invalid container stop timeout %q",
+			claberneteserrors.ErrLaunch,
+			rawTimeout,
+		)
+	}
+
+	return timeout, nil
+}
+
+// shutdownStopConfigs returns the stop config of every node that should be stopped during
+// shutdown. Without a default stop timeout that is just the nodes with stop config, with one every
+// node is stopped and nodes without a configured timeout get the default.
+func shutdownStopConfigs(
+	nodeStopConfigs map[string]nodeStopConfig,
+	nodeNames []string,
+	defaultTimeout time.Duration,
+) map[string]nodeStopConfig {
+	stopConfigs := make(map[string]nodeStopConfig, len(nodeStopConfigs))
+
+	for nodeName, stopConfig := range nodeStopConfigs {
+		if stopConfig.timeout == 0 {
+			stopConfig.timeout = defaultTimeout
+		}
+
+		stopConfigs[nodeName] = stopConfig
+	}
+
+	if defaultTimeout == 0 {
+		return stopConfigs
+	}
+
+	for _, nodeName := range nodeNames {
+		_, ok := stopConfigs[nodeName]
+		if !ok {
+			stopConfigs[nodeName] = nodeStopConfig{timeout: defaultTimeout}
+		}
+	}
+
+	return stopConfigs
+}
+
+// shutdownStopBudget returns how long stopping the given nodes may take in total -- as nodes are
+// stopped in parallel that is the longest stop timeout plus nodeStopTimeoutMargin.
+func shutdownStopBudget(stopConfigs map[string]nodeStopConfig) time.Duration {
+	stopTimeout := defaultDockerStopTimeout
+
+	for _, stopConfig := range stopConfigs {
+		stopTimeout = max(stopTimeout, stopConfig.timeout)
+	}
+
+	return stopTimeout + nodeStopTimeoutMargin
+}
+
+// stopNodes stops the containers of all nodes with a configured stop signal/timeout so that they
+// can shut down cleanly rather than being killed along with the launcher pod. If a default
+// container stop timeout is set all nodes are stopped, otherwise nodes without stop config are
+// left alone (as they always have been). This is meant to be called during shutdown so it uses its
+// own context rather than the (already cancelled) clabernetes context.
+func (c *clabernetes) stopNodes() {
+	nodeStopConfigs, err := loadNodeStopConfig()
+	if err != nil {
+		c.logger.Warnf("failed loading node stop config, not stopping nodes, err: %s", err)
+
+		return
+	}
+
+	defaultTimeout, err := loadContainerStopTimeout()
+	if err != nil {
+		c.logger.Warnf("failed loading container stop timeout, not stopping nodes, err: %s", err)
+
+		return
+	}
+
+	if len(nodeStopConfigs) == 0 && defaultTimeout == 0 {
+		return
+	}
+
+	indexCtx, indexCancel := context.WithTimeout(context.Background(), defaultDockerStopTimeout)
+	defer indexCancel()
+
+	index, err := newNodeContainerIndex(indexCtx)
+	if err != nil {
+		c.logger.Warnf("failed listing node containers, not stopping nodes, err: %s", err)
+
+		return
+	}
+
+	stopConfigs := shutdownStopConfigs(nodeStopConfigs, index.nodeNames(), defaultTimeout)
+
+	stopBudget := shutdownStopBudget(stopConfigs)
+
+	c.logger.Debugf("stopping %d node(s) within %s", len(stopConfigs), stopBudget)
+
+	ctx, cancel := context.WithTimeout(context.Background(), stopBudget)
+	defer cancel()
+
+	wg := &sync.WaitGroup{}
+
+	for nodeName, stopConfig := range stopConfigs {
+		containerID, ok := index.lookup(nodeName)
+		if !ok {
+			c.logger.Warnf("stop config provided for node %q but node has no container", nodeName)
+
+			continue
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			c.logger.Infof("stopping node %q...", nodeName)
+
+			err := stopContainer(ctx, containerID, stopConfig)
+			if err != nil {
+				c.logger.Warnf("failed stopping node %q, err: %s", nodeName, err)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,176 @@
+package launcher_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+func TestStopNodeContainer(t *testing.T) {
+	cases := []struct {
+		name           string
+		nodeStopConfig string
+		expectErr      bool
+		expectedCalls  map[string]int
+	}{
+		{
+			name: "docker-default",
+			expectedCalls: map[string]int{
+				"docker stop abc123": 1,
+			},
+		},
+		{
+			name:           "signal-and-timeout",
+			nodeStopConfig: `{"srl1": {"signal": "SIGINT", "timeout": "1m30s"}}`,
+			expectedCalls: map[string]int{
+				"docker stop --signal SIGINT --time 90 abc123": 1,
+			},
+		},
+		{
+			name:           "numeric-signal-partial-seconds",
+			nodeStopConfig: `{"srl1": {"signal": "15", "timeout": "1500ms"}}`,
+			expectedCalls: map[string]int{
+				"docker stop --signal 15 --time 2 abc123": 1,
+			},
+		},
+		{
+			name:           "other-node",
+			nodeStopConfig: `{"srl2": {"signal": "SIGINT"}}`,
+			expectedCalls: map[string]int{
+				"docker stop abc123": 1,
+			},
+		},
+		{
+			name:           "invalid-signal",
+			nodeStopConfig: `{"srl1": {"signal": "sig int"}}`,
+			expectErr:      true,
+			expectedCalls:  map[string]int{},
+		},
+		{
+			name:           "invalid-signal-number",
+			nodeStopConfig: `{"srl1": {"signal": "99"}}`,
+			expectErr:      true,
+			expectedCalls:  map[string]int{},
+		},
+		{
+			name:           "invalid-timeout",
+			nodeStopConfig: `{"srl1": {"timeout": "-5s"}}`,
+			expectErr:      true,
+			expectedCalls:  map[string]int{},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				t.Setenv(clabernetesconstants.LauncherNodeStopConfig, testCase.nodeStopConfig)
+
+				fakeRunner := newFakeCommandRunner()
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				err := claberneteslauncher.StopNodeContainer(
+					context.Background(),
+					"srl1",
+					"abc123",
+				)
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+
+				clabernetestesthelper.MarshaledEqual(t, fakeRunner.calls, testCase.expectedCalls)
+			})
+	}
+}
+
+func TestShutdownStopTimeouts(t *testing.T) {
+	cases := []struct {
+		name                  string
+		nodeStopConfig        string
+		containerStopTimeout  string
+		expectErr             bool
+		expectedTimeouts      map[string]time.Duration
+		expectedShutdownLimit time.Duration
+	}{
+		{
+			name:                  "nothing-configured",
+			expectedTimeouts:      map[string]time.Duration{},
+			expectedShutdownLimit: 20 * time.Second,
+		},
+		{
+			name:           "node-stop-config-only",
+			nodeStopConfig: `{"srl1": {"timeout": "30s"}}`,
+			expectedTimeouts: map[string]time.Duration{
+				"srl1": 30 * time.Second,
+			},
+			expectedShutdownLimit: 40 * time.Second,
+		},
+		{
+			name:                 "default-timeout-all-nodes",
+			containerStopTimeout: "1m",
+			expectedTimeouts: map[string]time.Duration{
+				"srl1": time.Minute,
+				"srl2": time.Minute,
+			},
+			expectedShutdownLimit: 70 * time.Second,
+		},
+		{
+			name:                 "node-timeout-overrides-default",
+			nodeStopConfig:       `{"srl1": {"timeout": "2m"}, "srl2": {"signal": "SIGINT"}}`,
+			containerStopTimeout: "5s",
+			expectedTimeouts: map[string]time.Duration{
+				"srl1": 2 * time.Minute,
+				"srl2": 5 * time.Second,
+			},
+			expectedShutdownLimit: 130 * time.Second,
+		},
+		{
+			name:                 "invalid-default-timeout",
+			containerStopTimeout: "-1s",
+			expectErr:            true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				t.Setenv(clabernetesconstants.LauncherNodeStopConfig, testCase.nodeStopConfig)
+				t.Setenv(
+					clabernetesconstants.LauncherContainerStopTimeout,
+					testCase.containerStopTimeout,
+				)
+
+				timeouts, shutdownLimit, err := claberneteslauncher.ShutdownStopTimeouts(
+					[]string{"srl1", "srl2"},
+				)
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+
+				if testCase.expectErr {
+					return
+				}
+
+				clabernetestesthelper.MarshaledEqual(t, timeouts, testCase.expectedTimeouts)
+
+				if shutdownLimit != testCase.expectedShutdownLimit {
+					clabernetestesthelper.FailOutput(
+						t,
+						shutdownLimit,
+						testCase.expectedShutdownLimit,
+					)
+				}
+			})
+	}
+}
@@ -0,0 +1,182 @@
+package launcher
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	networkModeHost            = "host"
+	networkModeContainerPrefix = "container:"
+)
+
+var sysctlNamePattern = regexp.MustCompile( //nolint:gochecknoglobals
+	`^[a-z0-9_-]+(\.[a-zA-Z0-9_*-]+)+$`,
+)
+
+// namespacedSysctls are the (non network) sysctls docker permits setting on a container, either
+// exactly or, for prefixes ending with ".", everything below them.
+var namespacedSysctls = []string{ //nolint:gochecknoglobals
+	"kernel.msgmax",
+	"kernel.msgmnb",
+	"kernel.msgmni",
+	"kernel.sem",
+	"kernel.shmall",
+	"kernel.shmmax",
+	"kernel.shmmni",
+	"kernel.shm_rmid_forced",
+	"fs.mqueue.",
+}
+
+// loadNodeSysctls loads the per node sysctls (a map of node name to a map of sysctl name to value)
+// from LauncherNodeSysctls in yaml (or json) form.
+func loadNodeSysctls() (map[string]map[string]string, error) {
+	allNodeSysctls := map[string]map[string]string{}
+
+	err := yaml.Unmarshal(
+		[]byte(os.Getenv(clabernetesconstants.LauncherNodeSysctls)),
+		&allNodeSysctls,
+	)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"%w: invalid node sysctls, must be a mapping of node name to a mapping of sysctl name"+
+				" to value, err: %w",
+			claberneteserrors.ErrLaunch,
+			err,
+		)
+	}
+
+	for nodeName, sysctls := range allNodeSysctls {
+		for name, value := range sysctls {
+			if !sysctlNamePattern.MatchString(name) || value == "" {
+				return nil, fmt.Errorf(
+					"%w: invalid sysctl %q=%q for node %q",
+					claberneteserrors.ErrLaunch,
+					name,
+					value,
+					nodeName,
+				)
+			}
+		}
+	}
+
+	return allNodeSysctls, nil
+}
+
+// validateSysctl ensures docker permits setting the given sysctl on a container with the given
+// network mode -- only namespaced sysctls may be set, and the network ones only if the container
+// has its own network namespace.
+func validateSysctl(name, networkMode string) error {
+	if strings.HasPrefix(name, "net.") {
+		if networkMode == networkModeHost ||
+			strings.HasPrefix(networkMode, networkModeContainerPrefix) {
+			return fmt.Errorf(
+				"%w: sysctl %q can not be set with network mode %q",
+				claberneteserrors.ErrLaunch,
+				name,
+				networkMode,
+			)
+		}
+
+		return nil
+	}
+
+	for _, namespaced := range namespacedSysctls {
+		if name == namespaced ||
+			(strings.HasSuffix(namespaced, ".") && strings.HasPrefix(name, namespaced)) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf(
+		"%w: sysctl %q is not namespaced, docker does not permit setting it on a container",
+		claberneteserrors.ErrLaunch,
+		name,
+	)
+}
+
+// applyNodeSysctls validates the given sysctls against the network mode of the given containerlab
+// node definition and adds them to the node's sysctls (overriding any already set there).
+func applyNodeSysctls(node map[string]any, sysctls map[string]string) error {
+	networkMode, _ := node["network-mode"].(string)
+
+	for _, name := range slices.Sorted(maps.Keys(sysctls)) {
+		err := validateSysctl(name, networkMode)
+		if err != nil {
+			return err
+		}
+	}
+
+	nodeSysctls, ok := node["sysctls"].(map[string]any)
+	if !ok {
+		nodeSysctls = make(map[string]any, len(sysctls))
+	}
+
+	for name, value := range sysctls {
+		nodeSysctls[name] = value
+	}
+
+	node["sysctls"] = nodeSysctls
+
+	return nil
+}
+
+// formatSysctls formats the given sysctls for logging, sorted by name.
+func formatSysctls(sysctls map[string]string) string {
+	formatted := make([]string, 0, len(sysctls))
+
+	for _, name := range slices.Sorted(maps.Keys(sysctls)) {
+		formatted = append(formatted, name+"="+sysctls[name])
+	}
+
+	return strings.Join(formatted, ", ")
+}
+
+// injectNodeSysctls adds the user provided per node sysctls (if any) to the node definitions in
+// the containerlab topology so they are set when containerlab creates the node containers.
+func (c *clabernetes) injectNodeSysctls() {
+	allNodeSysctls, err := loadNodeSysctls()
+	if err != nil {
+		c.logger.Fatalf("failed loading node sysctls, err: %s", err)
+	}
+
+	if len(allNodeSysctls) == 0 {
+		return
+	}
+
+	err = patchTopologyNodes(c.topologyPath(), func(nodeName string, node map[string]any) error {
+		sysctls, ok := allNodeSysctls[nodeName]
+		if !ok {
+			return nil
+		}
+
+		err = applyNodeSysctls(node, sysctls)
+		if err != nil {
+			return fmt.Errorf("node %q: %w", nodeName, err)
+		}
+
+		c.logger.Infof("node %q sysctls: %s", nodeName, formatSysctls(sysctls))
+
+		delete(allNodeSysctls, nodeName)
+
+		return nil
+	})
+	if err != nil {
+		c.logger.Fatalf("failed injecting node sysctls into topology, err: %s", err)
+	}
+
+	for nodeName := range allNodeSysctls {
+		c.logger.Warnf(
+			"node sysctls provided for node %q but node is not in the topology",
+			nodeName,
+		)
+	}
+}
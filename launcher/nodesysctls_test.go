@@ -0,0 +1,99 @@
+package launcher_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+const injectNodeSysctlsTestName = "inject-node-sysctls"
+
+func TestInjectNodeSysctls(t *testing.T) {
+	cases := []struct {
+		name        string
+		nodeSysctls string
+		expectErr   bool
+	}{
+		{
+			name: "simple",
+			nodeSysctls: `{"srl1": {"net.ipv4.ip_forward": 1, "net.ipv6.conf.all.disable_ipv6": 0,` +
+				` "fs.mqueue.msg_max": 64}, "srl2": {"kernel.sem": "250 32000 100 128"}}`,
+		},
+		{
+			name:        "network-sysctl-host-network-mode",
+			nodeSysctls: `{"srl2": {"net.ipv4.ip_forward": 1}}`,
+			expectErr:   true,
+		},
+		{
+			name:        "not-namespaced",
+			nodeSysctls: `{"srl1": {"vm.swappiness": 10}}`,
+			expectErr:   true,
+		},
+		{
+			name:        "invalid-name",
+			nodeSysctls: `{"srl1": {"ip_forward": 1}}`,
+			expectErr:   true,
+		},
+		{
+			name:        "invalid-format",
+			nodeSysctls: `["srl1"]`,
+			expectErr:   true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				t.Setenv(clabernetesconstants.LauncherNodeSysctls, testCase.nodeSysctls)
+
+				topologyPath := filepath.Join(t.TempDir(), "topo.clab.yaml")
+
+				err := os.WriteFile(
+					topologyPath,
+					clabernetestesthelper.ReadTestFixtureFile(t, "node-sysctls/topo.clab.yaml"),
+					0o644, //nolint:gosec
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				err = claberneteslauncher.InjectNodeSysctls(topologyPath)
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+
+				if testCase.expectErr {
+					return
+				}
+
+				actual, err := os.ReadFile(topologyPath)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				goldenFileName := fmt.Sprintf(
+					"golden/%s/%s.yaml",
+					injectNodeSysctlsTestName,
+					testCase.name,
+				)
+
+				if *clabernetestesthelper.Update {
+					clabernetestesthelper.WriteTestFixtureFile(t, goldenFileName, actual)
+				}
+
+				expected := clabernetestesthelper.ReadTestFixtureFile(t, goldenFileName)
+
+				if string(actual) != string(expected) {
+					clabernetestesthelper.FailOutput(t, actual, expected)
+				}
+			})
+	}
+}
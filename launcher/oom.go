@@ -0,0 +1,88 @@
+package launcher
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// oomKills tracks the node containers that were OOMKilled, keyed by node name.
+type oomKills struct {
+	lock  sync.Mutex
+	kills map[string]int
+}
+
+func newOOMKills() *oomKills {
+	return &oomKills{
+		kills: map[string]int{},
+	}
+}
+
+// record records an OOM kill of the given node, returning how many times that node has been
+// OOMKilled so far.
+func (o *oomKills) record(nodeName string) int {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	o.kills[nodeName]++
+
+	return o.kills[nodeName]
+}
+
+// nodes returns the sorted names of all nodes that were OOMKilled.
+func (o *oomKills) nodes() []string {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	nodeNames := make([]string, 0, len(o.kills))
+
+	for nodeName := range o.kills {
+		nodeNames = append(nodeNames, nodeName)
+	}
+
+	slices.Sort(nodeNames)
+
+	return nodeNames
+}
+
+// containerOOMKilled returns whether the given container was OOMKilled along with its
+// (containerlab) node name, falling back to the container name if it is not a node container.
+func containerOOMKilled(ctx context.Context, containerID string) (string, bool, error) {
+	inspected, err := inspectContainers(ctx, []string{containerID})
+	if err != nil {
+		return "", false, err
+	}
+
+	result, ok := inspected[containerID]
+	if !ok {
+		return "", false, fmt.Errorf("container %q not found", containerID)
+	}
+
+	return inspectedNodeName(result), result.State.OOMKilled, nil
+}
+
+// checkContainerOOMKilled checks if the given (stopped) container was OOMKilled, and if so, warns
+// about it and records it so that it is reported by the readiness endpoint.
+func (c *clabernetes) checkContainerOOMKilled(containerID string) {
+	nodeName, oomKilled, err := containerOOMKilled(c.ctx, containerID)
+	if err != nil {
+		c.logger.Debugf("failed checking if container %q was OOMKilled, err: %s", containerID, err)
+
+		return
+	}
+
+	if !oomKilled {
+		return
+	}
+
+	count := c.oomKills.record(nodeName)
+
+	c.logger.Warnf(
+		"node %q (container %q) was OOMKilled (%d time(s) so far), consider raising the "+
+			"launcher memory limits",
+		nodeName,
+		containerID,
+		count,
+	)
+}
@@ -0,0 +1,81 @@
+package launcher_test
+
+import (
+	"context"
+	"testing"
+
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+func TestContainerOOMKilled(t *testing.T) {
+	cases := []struct {
+		name              string
+		output            string
+		cmdErr            error
+		expectedNodeName  string
+		expectedOOMKilled bool
+		expectErr         bool
+	}{
+		{
+			name: "oom-killed",
+			output: `[{"Id":"abc123def","Name":"/clab-clabernetes-srl1-srl1",` +
+				`"State":{"Status":"exited","OOMKilled":true,"ExitCode":137},` +
+				`"Config":{"Labels":{"clab-node-name":"srl1"}}}]`,
+			expectedNodeName:  "srl1",
+			expectedOOMKilled: true,
+		},
+		{
+			name: "exited",
+			output: `[{"Id":"abc123def","Name":"/clab-clabernetes-srl1-srl1",` +
+				`"State":{"Status":"exited","OOMKilled":false,"ExitCode":1},` +
+				`"Config":{"Labels":{"clab-node-name":"srl1"}}}]`,
+			expectedNodeName: "srl1",
+		},
+		{
+			name: "not-a-node-container",
+			output: `[{"Id":"abc123def","Name":"/sidecar",` +
+				`"State":{"Status":"exited","OOMKilled":true,"ExitCode":137},` +
+				`"Config":{"Labels":{}}}]`,
+			expectedNodeName:  "sidecar",
+			expectedOOMKilled: true,
+		},
+		{
+			name:      "docker-error",
+			cmdErr:    errFakeCommand,
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				fakeRunner := newFakeCommandRunner()
+
+				fakeRunner.outputs["docker inspect abc123"] = []byte(testCase.output)
+				fakeRunner.results["docker inspect abc123"] = testCase.cmdErr
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				nodeName, oomKilled, err := claberneteslauncher.ContainerOOMKilled(
+					context.Background(),
+					"abc123",
+				)
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+
+				if nodeName != testCase.expectedNodeName {
+					clabernetestesthelper.FailOutput(t, nodeName, testCase.expectedNodeName)
+				}
+
+				if oomKilled != testCase.expectedOOMKilled {
+					clabernetestesthelper.FailOutput(t, oomKilled, testCase.expectedOOMKilled)
+				}
+			})
+	}
+}
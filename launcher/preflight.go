@@ -0,0 +1,197 @@
+package launcher
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+)
+
+// dockerSubcommandRequirement is a docker cli subcommand the launcher relies on along with the
+// feature relying on it. Optional requirements disable their feature when the subcommand is
+// missing, all others fail the launcher.
+type dockerSubcommandRequirement struct {
+	subcommand string
+	feature    string
+	optional   bool
+}
+
+// dockerSubcommandRequirements returns the docker cli subcommands needed by the launcher in its
+// current configuration -- subcommands of features that are not configured are not included.
+func dockerSubcommandRequirements() []dockerSubcommandRequirement {
+	requirements := []dockerSubcommandRequirement{
+		{subcommand: "ps", feature: "container discovery"},
+		{subcommand: "inspect", feature: "container inspection"},
+		{subcommand: "logs", feature: "node log tailing"},
+		{subcommand: "events", feature: "stopping log tails of dead containers", optional: true},
+		{subcommand: "system", feature: "image cleanup", optional: true},
+	}
+
+	if os.Getenv(clabernetesconstants.LauncherImagePullPolicy) != "" ||
+		os.Getenv(clabernetesconstants.LauncherImagePullMirror) != "" {
+		requirements = append(
+			requirements,
+			dockerSubcommandRequirement{subcommand: "image", feature: "image pull"},
+		)
+	}
+
+	if os.Getenv(clabernetesconstants.LauncherImageBuildContext) != "" {
+		requirements = append(
+			requirements,
+			dockerSubcommandRequirement{subcommand: "build", feature: "image build"},
+		)
+	}
+
+	if strings.EqualFold(
+		os.Getenv(clabernetesconstants.LauncherSelfTest),
+		clabernetesconstants.True,
+	) {
+		requirements = append(
+			requirements,
+			dockerSubcommandRequirement{subcommand: "image", feature: "self-test"},
+			dockerSubcommandRequirement{subcommand: "run", feature: "self-test"},
+		)
+	}
+
+	if os.Getenv(clabernetesconstants.LauncherContainerRestartPolicy) != "" {
+		requirements = append(
+			requirements,
+			dockerSubcommandRequirement{subcommand: "update", feature: "container restart policy"},
+		)
+	}
+
+	if os.Getenv(clabernetesconstants.LauncherNodeStopConfig) != "" ||
+		os.Getenv(clabernetesconstants.LauncherContainerStopTimeout) != "" {
+		requirements = append(
+			requirements,
+			dockerSubcommandRequirement{subcommand: "stop", feature: "node stop config"},
+		)
+	}
+
+	if os.Getenv(clabernetesconstants.LauncherOverlayNetworkConfig) != "" {
+		requirements = append(
+			requirements,
+			dockerSubcommandRequirement{subcommand: "network", feature: "overlay network"},
+		)
+	}
+
+	return requirements
+}
+
+// parseDockerSubcommands returns the set of subcommands listed in the given "docker --help"
+// output, that is the first word of every indented line in any of its "... Commands:" sections.
+// Plugin subcommands are listed with a trailing "*" which is stripped.
+func parseDockerSubcommands(help []byte) map[string]bool {
+	subcommands := map[string]bool{}
+
+	var inCommands bool
+
+	scanner := bufio.NewScanner(bytes.NewReader(help))
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		if !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") {
+			inCommands = strings.HasSuffix(strings.TrimSpace(line), "Commands:")
+
+			continue
+		}
+
+		if !inCommands {
+			continue
+		}
+
+		subcommands[strings.TrimSuffix(strings.Fields(line)[0], "*")] = true
+	}
+
+	return subcommands
+}
+
+// getDockerSubcommands returns the set of subcommands the installed docker cli supports.
+func getDockerSubcommands(ctx context.Context) (map[string]bool, error) {
+	helpCmd := exec.CommandContext(ctx, "docker", "--help")
+
+	output, err := runner.Output(helpCmd)
+	if err != nil {
+		return nil, err
+	}
+
+	subcommands := parseDockerSubcommands(output)
+	if len(subcommands) == 0 {
+		return nil, fmt.Errorf(
+			"%w: found no subcommands in docker help output",
+			claberneteserrors.ErrLaunch,
+		)
+	}
+
+	return subcommands, nil
+}
+
+// missingDockerSubcommands returns the requirements whose subcommand is not in the given set of
+// available subcommands.
+func missingDockerSubcommands(
+	available map[string]bool,
+	requirements []dockerSubcommandRequirement,
+) []dockerSubcommandRequirement {
+	var missing []dockerSubcommandRequirement
+
+	for _, requirement := range requirements {
+		if !available[requirement.subcommand] {
+			missing = append(missing, requirement)
+		}
+	}
+
+	return missing
+}
+
+// dockerCLIPreflight ensures the docker cli supports the subcommands the configured features need
+// so that a stripped down docker binary fails the launcher upfront rather than deep into runtime.
+// Optional features whose subcommand is missing are disabled instead. If the docker cli can't be
+// probed the preflight is skipped.
+func (c *clabernetes) dockerCLIPreflight() {
+	available, err := getDockerSubcommands(c.ctx)
+	if err != nil {
+		c.logger.Warnf("failed probing docker cli subcommands, skipping preflight, err: %s", err)
+
+		return
+	}
+
+	c.missingDockerSubcommands = map[string]bool{}
+
+	for _, requirement := range missingDockerSubcommands(
+		available,
+		dockerSubcommandRequirements(),
+	) {
+		if !requirement.optional {
+			c.logger.Fatalf(
+				"docker cli does not support the %q subcommand required for %s",
+				requirement.subcommand,
+				requirement.feature,
+			)
+		}
+
+		c.logger.Warnf(
+			"docker cli does not support the %q subcommand, disabling %s",
+			requirement.subcommand,
+			requirement.feature,
+		)
+
+		c.missingDockerSubcommands[requirement.subcommand] = true
+	}
+}
+
+// dockerSubcommandAvailable returns false if the docker cli preflight found the given subcommand
+// to be missing.
+func (c *clabernetes) dockerSubcommandAvailable(subcommand string) bool {
+	return !c.missingDockerSubcommands[subcommand]
+}
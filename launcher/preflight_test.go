@@ -0,0 +1,98 @@
+package launcher_test
+
+import (
+	"testing"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+const dockerHelpOutput = `
+Usage:  docker [OPTIONS] COMMAND
+
+A self-sufficient runtime for containers
+
+Common Commands:
+  run         Create and run a new container from an image
+  ps          List containers
+  images      List images
+
+Management Commands:
+  buildx*     Docker Buildx
+  image       Manage images
+  system      Manage Docker
+
+Commands:
+  inspect     Return low-level information on Docker objects
+  logs        Fetch the logs of a container
+
+Global Options:
+      --config string      Location of client config files
+  -D, --debug              Enable debug mode
+
+Run 'docker COMMAND --help' for more information on a command.
+`
+
+func TestParseDockerSubcommands(t *testing.T) {
+	actual := claberneteslauncher.ParseDockerSubcommands([]byte(dockerHelpOutput))
+
+	expected := map[string]bool{
+		"run":     true,
+		"ps":      true,
+		"images":  true,
+		"buildx":  true,
+		"image":   true,
+		"system":  true,
+		"inspect": true,
+		"logs":    true,
+	}
+
+	clabernetestesthelper.MarshaledEqual(t, actual, expected)
+}
+
+func TestMissingDockerSubcommands(t *testing.T) {
+	cases := []struct {
+		name          string
+		pullPolicy    string
+		restartPolicy string
+		expected      map[string]bool
+	}{
+		{
+			name:     "defaults",
+			expected: map[string]bool{"events": true},
+		},
+		{
+			name:       "image-pull",
+			pullPolicy: "Always",
+			expected:   map[string]bool{"events": true},
+		},
+		{
+			name:          "restart-policy",
+			restartPolicy: "always",
+			expected:      map[string]bool{"events": true, "update": false},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				t.Setenv(clabernetesconstants.LauncherImagePullPolicy, testCase.pullPolicy)
+				t.Setenv(clabernetesconstants.LauncherImagePullMirror, "")
+				t.Setenv(
+					clabernetesconstants.LauncherContainerRestartPolicy,
+					testCase.restartPolicy,
+				)
+				t.Setenv(clabernetesconstants.LauncherNodeStopConfig, "")
+
+				actual := claberneteslauncher.MissingDockerSubcommands(
+					claberneteslauncher.ParseDockerSubcommands([]byte(dockerHelpOutput)),
+				)
+
+				clabernetestesthelper.MarshaledEqual(t, actual, testCase.expected)
+			})
+	}
+}
@@ -0,0 +1,401 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+	claberneteslogging "github.com/srl-labs/clabernetes/logging"
+	clabernetesutil "github.com/srl-labs/clabernetes/util"
+)
+
+const (
+	dockerHubLibraryPrefix = "library/"
+
+	// imagePullProgressInterval is how often the progress of a running image pull is logged.
+	imagePullProgressInterval = 10 * time.Second
+	// maxImagePullAttempts is the number of times an image pull that timed out is attempted.
+	maxImagePullAttempts = 3
+
+	imagePullPolicyAlways       = "Always"
+	imagePullPolicyIfNotPresent = "IfNotPresent"
+	imagePullPolicyNever        = "Never"
+)
+
+// mirrorImageReference returns the reference of the given image on the given pull-through cache
+// mirror -- that is, the image's repository path (sans its registry) on the mirror host.
+func mirrorImageReference(image, mirror string) string {
+	mirror = strings.TrimSuffix(mirror, "/")
+
+	registry, path, ok := strings.Cut(image, "/")
+
+	switch {
+	case !ok:
+		// docker hub official image, i.e. "nginx"
+		return mirror + "/" + dockerHubLibraryPrefix + image
+	case strings.ContainsAny(registry, ".:") || registry == "localhost":
+		return mirror + "/" + path
+	default:
+		// docker hub user image, i.e. "user/image"
+		return mirror + "/" + image
+	}
+}
+
+// imagePullCandidates returns the references to attempt to pull the given image from, in order --
+// the mirror reference first if a mirror is configured, then the canonical reference.
+func imagePullCandidates(image, mirror string) []string {
+	if mirror == "" {
+		return []string{image}
+	}
+
+	return []string{mirrorImageReference(image, mirror), image}
+}
+
+// pullImage pulls the given image, trying the pull-through cache mirror first (if set) and falling
+// back to the canonical reference if pulling from the mirror fails. An image pulled from the mirror
+// is tagged with its canonical reference so that containerlab finds it as usual.
+func pullImage(
+	ctx context.Context,
+	logger claberneteslogging.Instance,
+	image, mirror string,
+) error {
+	var err error
+
+	timeout := clabernetesutil.GetEnvDurationOrDefault(
+		clabernetesconstants.LauncherImagePullTimeout,
+		0,
+	)
+
+	for _, candidate := range imagePullCandidates(image, mirror) {
+		err = pullImageReference(ctx, logger, candidate, timeout)
+		if err != nil {
+			if candidate != image {
+				logger.Warnf(
+					"failed pulling image %q from mirror as %q, falling back to %q, err: %s",
+					image,
+					candidate,
+					image,
+					err,
+				)
+			}
+
+			continue
+		}
+
+		if candidate != image {
+			return runDockerImageCmd(ctx, logger, "tag", candidate, image)
+		}
+
+		return nil
+	}
+
+	return err
+}
+
+// imagePullLayerPattern matches the per layer status lines of (non tty) docker pull output, i.e.
+// "a1b2c3d4e5f6: Pull complete".
+var imagePullLayerPattern = regexp.MustCompile( //nolint:gochecknoglobals
+	`^([0-9a-f]{12}): (.+)$`,
+)
+
+// imagePullProgress tracks the layers of a running image pull as reported by the docker pull
+// output so that a short summary can be logged rather than the raw per layer noise.
+type imagePullProgress struct {
+	lock   sync.Mutex
+	layers map[string]bool
+}
+
+func newImagePullProgress() *imagePullProgress {
+	return &imagePullProgress{
+		layers: map[string]bool{},
+	}
+}
+
+// observe records the given docker pull output line, lines that are not layer status lines are
+// ignored.
+func (p *imagePullProgress) observe(line string) {
+	matches := imagePullLayerPattern.FindStringSubmatch(strings.TrimSpace(line))
+	if matches == nil {
+		return
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	switch matches[2] {
+	case "Pull complete", "Already exists":
+		p.layers[matches[1]] = true
+	default:
+		if _, ok := p.layers[matches[1]]; !ok {
+			p.layers[matches[1]] = false
+		}
+	}
+}
+
+// summary returns a one line summary of the pull progress, i.e. "40%: layer 2/5".
+func (p *imagePullProgress) summary() string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if len(p.layers) == 0 {
+		return "no progress reported yet"
+	}
+
+	var complete int
+
+	for _, layerComplete := range p.layers {
+		if layerComplete {
+			complete++
+		}
+	}
+
+	return fmt.Sprintf(
+		"%d%%: layer %d/%d",
+		complete*100/len(p.layers), //nolint:mnd
+		complete,
+		len(p.layers),
+	)
+}
+
+// pullImageReference pulls the given image reference, logging a progress summary periodically. If
+// timeout is non-zero a pull that takes longer than that is killed and retried, up to
+// maxImagePullAttempts times.
+func pullImageReference(
+	ctx context.Context,
+	logger claberneteslogging.Instance,
+	reference string,
+	timeout time.Duration,
+) error {
+	for attempt := 1; ; attempt++ {
+		err := pullImageReferenceOnce(ctx, logger, reference, timeout)
+		if err == nil ||
+			!errors.Is(err, claberneteserrors.ErrImagePullTimeout) ||
+			attempt >= maxImagePullAttempts {
+			return err
+		}
+
+		logger.Warnf(
+			"pulling image %q timed out after %s, retrying (attempt %d/%d)...",
+			reference,
+			timeout,
+			attempt+1,
+			maxImagePullAttempts,
+		)
+	}
+}
+
+func pullImageReferenceOnce(
+	ctx context.Context,
+	logger claberneteslogging.Instance,
+	reference string,
+	timeout time.Duration,
+) error {
+	pullCtx := ctx
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+
+		pullCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	progress := newImagePullProgress()
+
+	pullCmd := exec.CommandContext(pullCtx, "docker", "image", "pull", reference)
+
+	pullCmd.Stdout = newLineWriter(func(line []byte) error {
+		progress.observe(string(line))
+
+		logger.Debug(strings.TrimSpace(string(line)))
+
+		return nil
+	})
+	pullCmd.Stderr = logger
+
+	done := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(imagePullProgressInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				logger.Infof("pulling image %q, %s", reference, progress.summary())
+			}
+		}
+	}()
+
+	err := runner.Run(pullCmd)
+
+	close(done)
+
+	if err != nil && ctx.Err() == nil && errors.Is(pullCtx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf(
+			"%w: pulling image %q did not complete within %s",
+			claberneteserrors.ErrImagePullTimeout,
+			reference,
+			timeout,
+		)
+	}
+
+	if err != nil {
+		return classifyDockerError(err)
+	}
+
+	logger.Infof("pulled image %q", reference)
+
+	return nil
+}
+
+func runDockerImageCmd(ctx context.Context, logger io.Writer, args ...string) error {
+	cmd := exec.CommandContext(ctx, "docker", append([]string{"image"}, args...)...)
+
+	cmd.Stdout = logger
+	cmd.Stderr = logger
+
+	return classifyDockerError(runner.Run(cmd))
+}
+
+// imageExists returns true if the given image is present in the docker daemon.
+func imageExists(ctx context.Context, image string) (bool, error) {
+	inspectCmd := exec.CommandContext(ctx, "docker", "image", "inspect", image)
+
+	_, err := runner.Output(inspectCmd)
+	if err == nil {
+		return true, nil
+	}
+
+	err = classifyDockerError(err)
+	if errors.Is(err, claberneteserrors.ErrContainerNotFound) {
+		return false, nil
+	}
+
+	return false, err
+}
+
+// validateImagePullPolicy ensures the given image pull policy is one of the kubernetes style
+// policies.
+func validateImagePullPolicy(imagePullPolicy string) error {
+	switch imagePullPolicy {
+	case imagePullPolicyAlways, imagePullPolicyIfNotPresent, imagePullPolicyNever:
+		return nil
+	default:
+		return fmt.Errorf(
+			"%w: invalid image pull policy %q, must be one of %q, %q, or %q",
+			claberneteserrors.ErrLaunch,
+			imagePullPolicy,
+			imagePullPolicyAlways,
+			imagePullPolicyIfNotPresent,
+			imagePullPolicyNever,
+		)
+	}
+}
+
+// ensureImage makes sure the given image is present in the docker daemon according to the given
+// image pull policy -- "Always" pulls unconditionally, "IfNotPresent" pulls only if the image is
+// missing, and "Never" never pulls and fails if the image is missing.
+func ensureImage(
+	ctx context.Context,
+	logger claberneteslogging.Instance,
+	image, mirror, imagePullPolicy string,
+) error {
+	err := validateImagePullPolicy(imagePullPolicy)
+	if err != nil {
+		return err
+	}
+
+	if imagePullPolicy != imagePullPolicyAlways {
+		exists, err := imageExists(ctx, image)
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			logger.Debugf("image %q already present, not pulling", image)
+
+			return nil
+		}
+
+		if imagePullPolicy == imagePullPolicyNever {
+			return fmt.Errorf(
+				"%w: image %q is not present and image pull policy is %q",
+				claberneteserrors.ErrLaunch,
+				image,
+				imagePullPolicyNever,
+			)
+		}
+	}
+
+	logger.Infof("pulling image %q...", image)
+
+	return pullImage(ctx, logger, image, mirror)
+}
+
+// imagePull ensures the images of the nodes in the topology are present ahead of launching
+// containerlab according to the configured image pull policy, pulling via the configured
+// pull-through cache mirror (if any). Only the images of this launcher's nodes are pulled, if the
+// topology can't be read the node image is pulled. If neither a policy nor a mirror is configured
+// containerlab is left to pull the images as usual.
+func (c *clabernetes) imagePull() {
+	imagePullPolicy := os.Getenv(clabernetesconstants.LauncherImagePullPolicy)
+	mirror := os.Getenv(clabernetesconstants.LauncherImagePullMirror)
+
+	if imagePullPolicy == "" && mirror == "" {
+		return
+	}
+
+	if imagePullPolicy == "" {
+		imagePullPolicy = imagePullPolicyIfNotPresent
+	}
+
+	images, err := topologyNodeImages(c.topologyPath())
+	if err != nil {
+		c.logger.Warnf(
+			"failed determining node images from topology, falling back to node image, err: %s",
+			err,
+		)
+
+		images = nil
+	}
+
+	if len(images) == 0 && c.imageName != "" {
+		images = []string{c.imageName}
+	}
+
+	c.logger.Debugf("ensuring node images %q are present", images)
+
+	for _, image := range images {
+		if image == c.builtImage {
+			c.logger.Debugf("image %q was built by the launcher, not pulling it", image)
+
+			continue
+		}
+
+		err = ensureImage(c.ctx, c.logger, image, mirror, imagePullPolicy)
+		if err == nil {
+			continue
+		}
+
+		if imagePullPolicy == imagePullPolicyNever {
+			c.logger.Fatalf("failed ensuring image %q is present, err: %s", image, err)
+		}
+
+		c.logger.Warnf(
+			"failed pulling image %q, containerlab will attempt to pull it, err: %s",
+			image,
+			err,
+		)
+	}
+}
@@ -0,0 +1,307 @@
+package launcher_test
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"testing"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+func TestImagePullCandidates(t *testing.T) {
+	cases := []struct {
+		name     string
+		image    string
+		mirror   string
+		expected []string
+	}{
+		{
+			name:     "no-mirror",
+			image:    "ghcr.io/nokia/srlinux:latest",
+			expected: []string{"ghcr.io/nokia/srlinux:latest"},
+		},
+		{
+			name:   "registry-image",
+			image:  "ghcr.io/nokia/srlinux:latest",
+			mirror: "mirror.local:5000",
+			expected: []string{
+				"mirror.local:5000/nokia/srlinux:latest",
+				"ghcr.io/nokia/srlinux:latest",
+			},
+		},
+		{
+			name:     "docker-hub-official-image",
+			image:    "alpine:3",
+			mirror:   "mirror.local:5000/",
+			expected: []string{"mirror.local:5000/library/alpine:3", "alpine:3"},
+		},
+		{
+			name:     "docker-hub-user-image",
+			image:    "someuser/someimage",
+			mirror:   "mirror.local:5000",
+			expected: []string{"mirror.local:5000/someuser/someimage", "someuser/someimage"},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				actual := claberneteslauncher.ImagePullCandidates(testCase.image, testCase.mirror)
+
+				clabernetestesthelper.MarshaledEqual(t, actual, testCase.expected)
+			})
+	}
+}
+
+func TestPullImage(t *testing.T) {
+	cases := []struct {
+		name          string
+		mirrorErr     error
+		upstreamErr   error
+		expectErr     bool
+		expectedCalls map[string]int
+	}{
+		{
+			name: "mirror-ok",
+			expectedCalls: map[string]int{
+				"docker image pull mirror.local/nokia/srlinux":                      1,
+				"docker image tag mirror.local/nokia/srlinux ghcr.io/nokia/srlinux": 1,
+			},
+		},
+		{
+			name:      "mirror-down",
+			mirrorErr: errFakeCommand,
+			expectedCalls: map[string]int{
+				"docker image pull mirror.local/nokia/srlinux": 1,
+				"docker image pull ghcr.io/nokia/srlinux":      1,
+			},
+		},
+		{
+			name:        "both-down",
+			mirrorErr:   errFakeCommand,
+			upstreamErr: errFakeCommand,
+			expectErr:   true,
+			expectedCalls: map[string]int{
+				"docker image pull mirror.local/nokia/srlinux": 1,
+				"docker image pull ghcr.io/nokia/srlinux":      1,
+			},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				fakeRunner := newFakeCommandRunner()
+
+				fakeRunner.results["docker image pull mirror.local/nokia/srlinux"] =
+					testCase.mirrorErr
+				fakeRunner.results["docker image pull ghcr.io/nokia/srlinux"] =
+					testCase.upstreamErr
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				err := claberneteslauncher.PullImage(
+					context.Background(),
+					"ghcr.io/nokia/srlinux",
+					"mirror.local",
+				)
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+
+				clabernetestesthelper.MarshaledEqual(t, fakeRunner.calls, testCase.expectedCalls)
+			})
+	}
+}
+
+func TestEnsureImage(t *testing.T) {
+	notFound := &exec.ExitError{Stderr: []byte("Error: No such image: ghcr.io/nokia/srlinux")}
+
+	cases := []struct {
+		name            string
+		imagePullPolicy string
+		inspectErr      error
+		expectErr       bool
+		expectedCalls   map[string]int
+	}{
+		{
+			name:            "always",
+			imagePullPolicy: "Always",
+			expectedCalls: map[string]int{
+				"docker image pull ghcr.io/nokia/srlinux": 1,
+			},
+		},
+		{
+			name:            "if-not-present-present",
+			imagePullPolicy: "IfNotPresent",
+			expectedCalls: map[string]int{
+				"docker image inspect ghcr.io/nokia/srlinux": 1,
+			},
+		},
+		{
+			name:            "if-not-present-missing",
+			imagePullPolicy: "IfNotPresent",
+			inspectErr:      notFound,
+			expectedCalls: map[string]int{
+				"docker image inspect ghcr.io/nokia/srlinux": 1,
+				"docker image pull ghcr.io/nokia/srlinux":    1,
+			},
+		},
+		{
+			name:            "never-present",
+			imagePullPolicy: "Never",
+			expectedCalls: map[string]int{
+				"docker image inspect ghcr.io/nokia/srlinux": 1,
+			},
+		},
+		{
+			name:            "never-missing",
+			imagePullPolicy: "Never",
+			inspectErr:      notFound,
+			expectErr:       true,
+			expectedCalls: map[string]int{
+				"docker image inspect ghcr.io/nokia/srlinux": 1,
+			},
+		},
+		{
+			name:            "invalid-policy",
+			imagePullPolicy: "Sometimes",
+			expectErr:       true,
+			expectedCalls:   map[string]int{},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				fakeRunner := newFakeCommandRunner()
+
+				fakeRunner.results["docker image inspect ghcr.io/nokia/srlinux"] =
+					testCase.inspectErr
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				err := claberneteslauncher.EnsureImage(
+					context.Background(),
+					"ghcr.io/nokia/srlinux",
+					"",
+					testCase.imagePullPolicy,
+				)
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+
+				clabernetestesthelper.MarshaledEqual(t, fakeRunner.calls, testCase.expectedCalls)
+			})
+	}
+}
+
+func TestTopologyNodeImages(t *testing.T) {
+	actual, err := claberneteslauncher.TopologyNodeImages(
+		"test-fixtures/node-images/topo.clab.yaml",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clabernetestesthelper.MarshaledEqual(
+		t,
+		actual,
+		[]string{
+			"alpine:latest",
+			"ghcr.io/nokia/srlinux:23.10.1",
+			"ghcr.io/nokia/srlinux:24.3.1",
+		},
+	)
+}
+
+func TestImagePullProgressSummary(t *testing.T) {
+	cases := []struct {
+		name     string
+		lines    []string
+		expected string
+	}{
+		{
+			name:     "no-progress-output",
+			lines:    []string{"latest: Pulling from nokia/srlinux"},
+			expected: "no progress reported yet",
+		},
+		{
+			name: "partial",
+			lines: []string{
+				"latest: Pulling from nokia/srlinux",
+				"a1b2c3d4e5f6: Pulling fs layer",
+				"b1b2c3d4e5f6: Pulling fs layer",
+				"c1b2c3d4e5f6: Already exists",
+				"a1b2c3d4e5f6: Verifying Checksum",
+				"a1b2c3d4e5f6: Download complete",
+				"a1b2c3d4e5f6: Pull complete",
+				"b1b2c3d4e5f6: Waiting",
+			},
+			expected: "66%: layer 2/3",
+		},
+		{
+			name: "complete",
+			lines: []string{
+				"a1b2c3d4e5f6: Pulling fs layer",
+				"a1b2c3d4e5f6: Pull complete",
+				"a1b2c3d4e5f6: Downloading",
+				"Digest: sha256:abc",
+				"Status: Downloaded newer image for ghcr.io/nokia/srlinux:latest",
+			},
+			expected: "100%: layer 1/1",
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				actual := claberneteslauncher.ImagePullProgressSummary(testCase.lines)
+				if actual != testCase.expected {
+					clabernetestesthelper.FailOutput(t, actual, testCase.expected)
+				}
+			})
+	}
+}
+
+func TestPullImageTimeout(t *testing.T) {
+	// a timeout this short has always passed by the time the (fake) pull returns, so every attempt
+	// counts as timed out
+	t.Setenv(clabernetesconstants.LauncherImagePullTimeout, "1ns")
+
+	fakeRunner := newFakeCommandRunner()
+
+	fakeRunner.results["docker image pull ghcr.io/nokia/srlinux"] = errFakeCommand
+
+	restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+	defer restore()
+
+	err := claberneteslauncher.PullImage(context.Background(), "ghcr.io/nokia/srlinux", "")
+	if !errors.Is(err, claberneteserrors.ErrImagePullTimeout) {
+		clabernetestesthelper.FailOutput(t, err, claberneteserrors.ErrImagePullTimeout)
+	}
+
+	clabernetestesthelper.MarshaledEqual(
+		t,
+		fakeRunner.calls,
+		map[string]int{"docker image pull ghcr.io/nokia/srlinux": 3},
+	)
+}
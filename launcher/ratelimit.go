@@ -0,0 +1,79 @@
+package launcher
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+const logRateLimitWindow = time.Second
+
+// newRateLimitedWriter returns a writer that writes at most linesPerSecond lines per second to w,
+// dropping any excess lines. Whenever a second in which lines were dropped has passed, report is
+// called with the number of dropped lines. If linesPerSecond is not positive w is returned as is.
+func newRateLimitedWriter(
+	w io.Writer,
+	linesPerSecond int,
+	now func() time.Time,
+	report func(suppressed int),
+) io.Writer {
+	if linesPerSecond <= 0 {
+		return w
+	}
+
+	limiter := &lineRateLimiter{
+		linesPerSecond: linesPerSecond,
+		now:            now,
+		report:         report,
+	}
+
+	return newLineWriter(func(line []byte) error {
+		if !limiter.allow() {
+			return nil
+		}
+
+		_, err := w.Write(line)
+
+		return err
+	})
+}
+
+// lineRateLimiter is a fixed window rate limiter counting lines per window.
+type lineRateLimiter struct {
+	lock           sync.Mutex
+	linesPerSecond int
+	now            func() time.Time
+	report         func(suppressed int)
+
+	windowStart time.Time
+	windowLines int
+	suppressed  int
+}
+
+// allow records a line and returns true if it may be written.
+func (l *lineRateLimiter) allow() bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := l.now()
+
+	if now.Sub(l.windowStart) >= logRateLimitWindow {
+		if l.suppressed > 0 {
+			l.report(l.suppressed)
+		}
+
+		l.windowStart = now
+		l.windowLines = 0
+		l.suppressed = 0
+	}
+
+	if l.windowLines >= l.linesPerSecond {
+		l.suppressed++
+
+		return false
+	}
+
+	l.windowLines++
+
+	return true
+}
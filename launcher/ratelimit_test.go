@@ -0,0 +1,67 @@
+package launcher_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+func TestNewRateLimitedWriter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var reported []int
+
+	out := &bytes.Buffer{}
+
+	w := claberneteslauncher.NewRateLimitedWriter(
+		out,
+		2,
+		func() time.Time { return now },
+		func(suppressed int) { reported = append(reported, suppressed) },
+	)
+
+	// first second: 2 of 5 lines written, the last one split across writes
+	for _, chunk := range []string{"one\ntwo\nthree\n", "four\nfi", "ve\n"} {
+		_, err := w.Write([]byte(chunk))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	now = now.Add(1500 * time.Millisecond)
+
+	_, err := w.Write([]byte("six\nseven\neight\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now = now.Add(time.Second)
+
+	_, err = w.Write([]byte("nine\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "one\ntwo\nsix\nseven\nnine\n"
+
+	if out.String() != expected {
+		clabernetestesthelper.FailOutput(t, out.String(), expected)
+	}
+
+	clabernetestesthelper.MarshaledEqual(t, reported, []int{3, 1})
+}
+
+func TestNewRateLimitedWriterUnlimited(t *testing.T) {
+	out := &bytes.Buffer{}
+
+	w := claberneteslauncher.NewRateLimitedWriter(out, 0, time.Now, func(int) {
+		t.Fatal("unexpected suppression report")
+	})
+
+	if w != out {
+		t.Fatal("expected unlimited writer to be the wrapped writer")
+	}
+}
@@ -0,0 +1,166 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+)
+
+const (
+	nodeReachabilityICMP = "icmp"
+
+	// reachabilityAttemptTimeout is how long a single dial/ping attempt may take.
+	reachabilityAttemptTimeout = time.Second
+
+	maxPort = 65535
+)
+
+// nodeReachability is how a container address is checked for reachability, either by dialing a
+// tcp port or, if icmp is set, by pinging it.
+type nodeReachability struct {
+	port int
+	icmp bool
+}
+
+func (r nodeReachability) String() string {
+	if r.icmp {
+		return nodeReachabilityICMP
+	}
+
+	return fmt.Sprintf("tcp port %d", r.port)
+}
+
+// loadNodeReachability loads the node reachability check from LauncherNodeReachability, returning
+// nil if no check is configured.
+func loadNodeReachability() (*nodeReachability, error) {
+	rawReachability := os.Getenv(clabernetesconstants.LauncherNodeReachability)
+
+	switch {
+	case rawReachability == "":
+		return nil, nil //nolint:nilnil
+	case strings.EqualFold(rawReachability, nodeReachabilityICMP):
+		if !strings.EqualFold(
+			os.Getenv(clabernetesconstants.LauncherPrivilegedEnv),
+			clabernetesconstants.True,
+		) {
+			return nil, fmt.Errorf(
+				"%w: icmp node reachability requires a privileged launcher, configure a tcp port"+
+					" instead",
+				claberneteserrors.ErrLaunch,
+			)
+		}
+
+		return &nodeReachability{icmp: true}, nil
+	}
+
+	port, err := strconv.Atoi(rawReachability)
+	if err != nil || port < 1 || port > maxPort {
+		return nil, fmt.Errorf(
+			"%w: invalid node reachability %q, must be a tcp port number or %q",
+			claberneteserrors.ErrLaunch,
+			rawReachability,
+			nodeReachabilityICMP,
+		)
+	}
+
+	return &nodeReachability{port: port}, nil
+}
+
+// reachable makes a single attempt at reaching the given address.
+func (r nodeReachability) reachable(ctx context.Context, addr string) bool {
+	ctx, cancel := context.WithTimeout(ctx, reachabilityAttemptTimeout)
+	defer cancel()
+
+	if r.icmp {
+		pingCmd := exec.CommandContext(
+			ctx,
+			"ping",
+			"-c",
+			"1",
+			"-W",
+			strconv.Itoa(int(reachabilityAttemptTimeout.Seconds())),
+			addr,
+		)
+
+		return runner.Run(pingCmd) == nil
+	}
+
+	dialer := &net.Dialer{}
+
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(addr, strconv.Itoa(r.port)))
+	if err != nil {
+		return false
+	}
+
+	_ = conn.Close()
+
+	return true
+}
+
+// waitContainerReachable waits until the given container address is reachable with the configured
+// node reachability check (see LauncherNodeReachability), returning ErrContainerUnreachable if it
+// does not become reachable within the timeout. This confirms the node's network is actually up
+// rather than just that docker assigned the container an address.
+func waitContainerReachable(ctx context.Context, addr string, timeout time.Duration) error {
+	reachability, err := loadNodeReachability()
+	if err != nil {
+		return err
+	}
+
+	if reachability == nil {
+		return fmt.Errorf("%w: no node reachability check configured", claberneteserrors.ErrLaunch)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err = pollUntil(waitCtx, func() (bool, error) {
+		return reachability.reachable(waitCtx, addr), nil
+	})
+	if err != nil && ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf(
+			"%w: %q not reachable (%s) within %s",
+			claberneteserrors.ErrContainerUnreachable,
+			addr,
+			reachability,
+			timeout,
+		)
+	}
+
+	return err
+}
+
+// waitNodeReachable waits for the node container's address to be reachable (if a node
+// reachability check is configured), so that readiness reflects the node's network being up.
+func (c *clabernetes) waitNodeReachable() {
+	if os.Getenv(clabernetesconstants.LauncherNodeReachability) == "" {
+		return
+	}
+
+	addr, err := getContainerAddr(c.ctx, c.nodeContainerID)
+	if err != nil || addr == "" {
+		c.logger.Warnf("failed determining node address, skipping reachability wait, err: %v", err)
+
+		return
+	}
+
+	err = c.startupTimings.runE("launch/node-reachable-wait", func() error {
+		return waitContainerReachable(c.ctx, addr, nodeReadyTimeout)
+	})
+	if err != nil {
+		c.logger.Warnf("node did not become reachable, will continue, err: %s", err)
+
+		return
+	}
+
+	c.logger.Infof("node reachable at %q", addr)
+}
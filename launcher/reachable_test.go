@@ -0,0 +1,116 @@
+package launcher_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+)
+
+func TestWaitContainerReachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = listener.Close()
+	}()
+
+	openPort := strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)
+
+	closedListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	closedPort := strconv.Itoa(closedListener.Addr().(*net.TCPAddr).Port)
+
+	_ = closedListener.Close()
+
+	cases := []struct {
+		name         string
+		reachability string
+		privileged   string
+		pingErr      error
+		expectedErr  error
+	}{
+		{
+			name:         "tcp-reachable",
+			reachability: openPort,
+		},
+		{
+			name:         "tcp-unreachable",
+			reachability: closedPort,
+			expectedErr:  claberneteserrors.ErrContainerUnreachable,
+		},
+		{
+			name:         "icmp-reachable",
+			reachability: "icmp",
+			privileged:   "true",
+		},
+		{
+			name:         "icmp-unreachable",
+			reachability: "icmp",
+			privileged:   "true",
+			pingErr:      errFakeCommand,
+			expectedErr:  claberneteserrors.ErrContainerUnreachable,
+		},
+		{
+			name:         "icmp-unprivileged",
+			reachability: "icmp",
+			expectedErr:  claberneteserrors.ErrLaunch,
+		},
+		{
+			name:         "invalid-port",
+			reachability: "70000",
+			expectedErr:  claberneteserrors.ErrLaunch,
+		},
+		{
+			name:        "not-configured",
+			expectedErr: claberneteserrors.ErrLaunch,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				t.Setenv(clabernetesconstants.LauncherNodeReachability, testCase.reachability)
+				t.Setenv(clabernetesconstants.LauncherPrivilegedEnv, testCase.privileged)
+				t.Setenv(clabernetesconstants.LauncherWaitPollInterval, "10ms")
+
+				fakeRunner := newFakeCommandRunner()
+
+				fakeRunner.results["ping -c 1 -W 1 127.0.0.1"] = testCase.pingErr
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				err := claberneteslauncher.WaitContainerReachable(
+					context.Background(),
+					"127.0.0.1",
+					100*time.Millisecond,
+				)
+				if testCase.expectedErr == nil {
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					return
+				}
+
+				if !errors.Is(err, testCase.expectedErr) {
+					t.Fatalf("expected error %v, got: %v", testCase.expectedErr, err)
+				}
+			})
+	}
+}
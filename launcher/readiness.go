@@ -0,0 +1,190 @@
+package launcher
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+	clabernetesutil "github.com/srl-labs/clabernetes/util"
+)
+
+const readyzRoute = "/readyz"
+
+// expectedNodes returns the names of the nodes that must be ready (the node this launcher
+// represents plus any configured expected nodes) and the number of node containers that must be
+// running for the launcher to be ready -- at least one, so we never report ready with nothing
+// running.
+func expectedNodes(nodeName string) ([]string, int, error) {
+	nodeNames := []string{nodeName}
+
+	for _, expectedNodeName := range strings.Split(
+		os.Getenv(clabernetesconstants.LauncherExpectedNodes),
+		",",
+	) {
+		expectedNodeName = strings.TrimSpace(expectedNodeName)
+		if expectedNodeName != "" && !slices.Contains(nodeNames, expectedNodeName) {
+			nodeNames = append(nodeNames, expectedNodeName)
+		}
+	}
+
+	count := clabernetesutil.GetEnvIntOrDefault(
+		clabernetesconstants.LauncherExpectedNodeCount,
+		0,
+	)
+	if count < 0 {
+		return nil, 0, fmt.Errorf(
+			"%w: invalid expected node count %d, must not be negative",
+			claberneteserrors.ErrLaunch,
+			count,
+		)
+	}
+
+	return nodeNames, max(count, len(nodeNames)), nil
+}
+
+// countRunningNodeContainers returns the number of running containerlab node containers.
+func countRunningNodeContainers(ctx context.Context) (int, error) {
+	psCmd := exec.CommandContext( //nolint:gosec
+		ctx,
+		"docker",
+		"ps",
+		"--quiet",
+		"--filter",
+		fmt.Sprintf("label=%s", containerlabLabLabel),
+	)
+
+	output, err := runner.Output(psCmd)
+	if err != nil {
+		return 0, classifyDockerError(err)
+	}
+
+	var count int
+
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.TrimSpace(line) != "" {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// waitExpectedNodeCount waits until at least count node containers are running, returning an
+// error saying how many were running if that does not happen within the timeout.
+func waitExpectedNodeCount(ctx context.Context, count int, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var running int
+
+	err := pollUntil(ctx, func() (bool, error) {
+		var err error
+
+		running, err = countRunningNodeContainers(ctx)
+		if err != nil {
+			return false, err
+		}
+
+		return running >= count, nil
+	})
+	if err != nil {
+		return fmt.Errorf(
+			"%w: expected %d running node containers but found %d after %s, err: %w",
+			claberneteserrors.ErrLaunch,
+			count,
+			running,
+			timeout,
+			err,
+		)
+	}
+
+	return nil
+}
+
+// readyzStatus returns the readyz http status and message -- never ready while the launcher is
+// degraded (held on a node failure, see LauncherHoldOnFailure), otherwise ready only once startup
+// completed and at least the expected number of node containers (as counted by countRunning) are
+// running. If not enough node containers are running, any nodes that were OOMKilled or exited
+// non-zero are called out in the message.
+func readyzStatus(
+	ctx context.Context,
+	degradedReason string,
+	startupComplete bool,
+	expectedCount int,
+	oomKilledNodes []string,
+	exitCodes map[string]int,
+	countRunning func(ctx context.Context) (int, error),
+) (int, string) {
+	if degradedReason != "" {
+		return http.StatusServiceUnavailable, "degraded, held for debugging: " + degradedReason
+	}
+
+	if !startupComplete {
+		return http.StatusServiceUnavailable, "startup in progress"
+	}
+
+	running, err := countRunning(ctx)
+	if err != nil {
+		return http.StatusServiceUnavailable, fmt.Sprintf(
+			"failed counting running node containers: %s", err,
+		)
+	}
+
+	if running < expectedCount {
+		message := fmt.Sprintf(
+			"%d of %d expected node containers running", running, expectedCount,
+		)
+
+		for _, nodeName := range oomKilledNodes {
+			message += fmt.Sprintf(", node %q OOMKilled", nodeName)
+		}
+
+		for _, nodeName := range slices.Sorted(maps.Keys(exitCodes)) {
+			if exitCodes[nodeName] != 0 {
+				message += fmt.Sprintf(
+					", node %q exited with code %d", nodeName, exitCodes[nodeName],
+				)
+			}
+		}
+
+		return http.StatusServiceUnavailable, message
+	}
+
+	return http.StatusOK, "ok"
+}
+
+func (c *clabernetes) readyzHandler(w http.ResponseWriter, r *http.Request) {
+	c.logger.Debugf("received %q on %q endpoint from %q", r.Method, r.RequestURI, r.RemoteAddr)
+
+	// validated in validateConfig
+	_, expectedCount, _ := expectedNodes(c.nodeName)
+
+	status, message := readyzStatus(
+		r.Context(),
+		c.degraded(),
+		c.startupComplete.Load(),
+		expectedCount,
+		c.oomKills.nodes(),
+		c.nodeExitCodes.snapshot(),
+		func(ctx context.Context) (int, error) {
+			if c.nodeStates.isLive() {
+				return c.nodeStates.runningCount(), nil
+			}
+
+			return countRunningNodeContainers(ctx)
+		},
+	)
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(status)
+
+	_, _ = fmt.Fprintln(w, message)
+}
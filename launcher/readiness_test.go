@@ -0,0 +1,197 @@
+package launcher_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+func TestExpectedNodes(t *testing.T) {
+	cases := []struct {
+		name              string
+		expectedNodes     string
+		expectedNodeCount string
+		expectedNames     []string
+		expectedCount     int
+		expectErr         bool
+	}{
+		{
+			name:          "defaults",
+			expectedNames: []string{"srl1"},
+			expectedCount: 1,
+		},
+		{
+			name:          "names",
+			expectedNodes: "srl2, srl1,,srl3",
+			expectedNames: []string{"srl1", "srl2", "srl3"},
+			expectedCount: 3,
+		},
+		{
+			name:              "count",
+			expectedNodeCount: "4",
+			expectedNames:     []string{"srl1"},
+			expectedCount:     4,
+		},
+		{
+			name:              "count-less-than-names",
+			expectedNodes:     "srl2",
+			expectedNodeCount: "1",
+			expectedNames:     []string{"srl1", "srl2"},
+			expectedCount:     2,
+		},
+		{
+			name:              "negative-count",
+			expectedNodeCount: "-1",
+			expectErr:         true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				t.Setenv(clabernetesconstants.LauncherExpectedNodes, testCase.expectedNodes)
+				t.Setenv(
+					clabernetesconstants.LauncherExpectedNodeCount,
+					testCase.expectedNodeCount,
+				)
+
+				actualNames, actualCount, err := claberneteslauncher.ExpectedNodes("srl1")
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+
+				if testCase.expectErr {
+					return
+				}
+
+				clabernetestesthelper.MarshaledEqual(t, actualNames, testCase.expectedNames)
+
+				if actualCount != testCase.expectedCount {
+					clabernetestesthelper.FailOutput(t, actualCount, testCase.expectedCount)
+				}
+			})
+	}
+}
+
+func TestReadyzStatus(t *testing.T) {
+	psKey := "docker ps --quiet --filter label=containerlab"
+
+	cases := []struct {
+		name            string
+		degradedReason  string
+		startupComplete bool
+		psOutput        string
+		psErr           error
+		expectedCount   int
+		oomKilledNodes  []string
+		exitCodes       map[string]int
+		expectedStatus  int
+		expectedMessage string
+	}{
+		{
+			name:           "startup-in-progress",
+			psOutput:       "abc123\n",
+			expectedCount:  1,
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:            "nothing-running",
+			startupComplete: true,
+			expectedCount:   1,
+			expectedStatus:  http.StatusServiceUnavailable,
+		},
+		{
+			name:            "too-few-running",
+			startupComplete: true,
+			psOutput:        "abc123\n",
+			expectedCount:   2,
+			expectedStatus:  http.StatusServiceUnavailable,
+		},
+		{
+			name:            "too-few-running-oom-killed",
+			startupComplete: true,
+			psOutput:        "abc123\n",
+			expectedCount:   2,
+			oomKilledNodes:  []string{"srl2"},
+			expectedStatus:  http.StatusServiceUnavailable,
+			expectedMessage: "1 of 2 expected node containers running, node \"srl2\" OOMKilled",
+		},
+		{
+			name:            "too-few-running-exited",
+			startupComplete: true,
+			psOutput:        "abc123\n",
+			expectedCount:   2,
+			exitCodes:       map[string]int{"srl1": 0, "srl2": 1},
+			expectedStatus:  http.StatusServiceUnavailable,
+			expectedMessage: "1 of 2 expected node containers running, node \"srl2\" exited with" +
+				" code 1",
+		},
+		{
+			name:            "degraded",
+			degradedReason:  "expected 2 running containers, but got 1",
+			startupComplete: true,
+			psOutput:        "abc123\ndef456\n",
+			expectedCount:   2,
+			expectedStatus:  http.StatusServiceUnavailable,
+			expectedMessage: "degraded, held for debugging: expected 2 running containers, but" +
+				" got 1",
+		},
+		{
+			name:            "docker-error",
+			startupComplete: true,
+			psErr:           errFakeCommand,
+			expectedCount:   1,
+			expectedStatus:  http.StatusServiceUnavailable,
+		},
+		{
+			name:            "ready",
+			startupComplete: true,
+			psOutput:        "abc123\ndef456\n",
+			expectedCount:   2,
+			expectedStatus:  http.StatusOK,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				fakeRunner := newFakeCommandRunner()
+
+				fakeRunner.outputs[psKey] = []byte(testCase.psOutput)
+				fakeRunner.results[psKey] = testCase.psErr
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				actualStatus, message := claberneteslauncher.ReadyzStatus(
+					context.Background(),
+					testCase.degradedReason,
+					testCase.startupComplete,
+					testCase.expectedCount,
+					testCase.oomKilledNodes,
+					testCase.exitCodes,
+				)
+				if actualStatus != testCase.expectedStatus {
+					clabernetestesthelper.FailOutput(
+						t,
+						[]any{actualStatus, message},
+						testCase.expectedStatus,
+					)
+				}
+
+				if testCase.expectedMessage != "" && message != testCase.expectedMessage {
+					clabernetestesthelper.FailOutput(t, message, testCase.expectedMessage)
+				}
+			})
+	}
+}
@@ -0,0 +1,79 @@
+package launcher
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+)
+
+const restartDockerTimeout = 5 * time.Minute
+
+// RestartDocker stops the launcher's docker daemon (if it is running) and starts it again via the
+// usual start flow, writing each step of the restart to stdout. Whether node containers survive the
+// restart is up to docker's live-restore setting -- the launcher does not touch it, it only reports
+// which way it will go before stopping the daemon.
+func RestartDocker() error {
+	ctx, cancel := context.WithTimeout(context.Background(), restartDockerTimeout)
+	defer cancel()
+
+	return restartDocker(ctx, os.Stdout)
+}
+
+func restartDocker(ctx context.Context, w io.Writer) error {
+	if dockerHostIsExternal() {
+		return fmt.Errorf(
+			"%w: %s points at an external docker daemon, refusing to restart it",
+			claberneteserrors.ErrLaunch,
+			dockerHostEnv,
+		)
+	}
+
+	info, err := getDockerInfo(ctx)
+	if err != nil {
+		_, _ = fmt.Fprintf(w, "docker does not appear to be running, err: %s\n", err)
+	} else {
+		if info.LiveRestoreEnabled {
+			_, _ = fmt.Fprintln(
+				w,
+				"docker live-restore is enabled, node containers will keep running",
+			)
+		} else {
+			_, _ = fmt.Fprintln(
+				w,
+				"docker live-restore is not enabled, node containers will be stopped",
+			)
+		}
+
+		_, _ = fmt.Fprintln(w, "stopping docker...")
+
+		stopCmd := exec.CommandContext(ctx, "service", "docker", "stop")
+
+		stopCmd.Stdout = w
+		stopCmd.Stderr = w
+
+		err = runner.Run(stopCmd)
+		if err != nil {
+			return fmt.Errorf(
+				"%w: failed stopping docker, err: %w",
+				claberneteserrors.ErrLaunch,
+				err,
+			)
+		}
+	}
+
+	_, _ = fmt.Fprintln(w, "starting docker...")
+
+	err = startDocker(ctx, w)
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintln(w, "docker restarted")
+
+	return nil
+}
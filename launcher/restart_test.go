@@ -0,0 +1,106 @@
+package launcher_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+)
+
+func TestRestartDocker(t *testing.T) {
+	infoKey := "docker info --format {{json .}}"
+	stopKey := "service docker stop"
+
+	cases := []struct {
+		name              string
+		dockerHost        string
+		infoOutput        string
+		infoResult        error
+		stopResult        error
+		expectedStopCalls int
+		expectedOutput    string
+		expectedErr       error
+	}{
+		{
+			name:              "live-restore-enabled",
+			infoOutput:        `{"LiveRestoreEnabled":true}`,
+			expectedStopCalls: 1,
+			expectedOutput:    "live-restore is enabled",
+		},
+		{
+			name:              "live-restore-disabled",
+			infoOutput:        `{"LiveRestoreEnabled":false}`,
+			expectedStopCalls: 1,
+			expectedOutput:    "live-restore is not enabled",
+		},
+		{
+			name:              "docker-not-running",
+			infoResult:        errFakeCommand,
+			expectedStopCalls: 0,
+			expectedOutput:    "docker does not appear to be running",
+		},
+		{
+			name:              "stop-fails",
+			infoOutput:        `{}`,
+			stopResult:        errFakeCommand,
+			expectedStopCalls: 1,
+			expectedErr:       claberneteserrors.ErrLaunch,
+		},
+		{
+			name:              "external-docker-host",
+			dockerHost:        "tcp://10.0.0.1:2375",
+			expectedStopCalls: 0,
+			expectedErr:       claberneteserrors.ErrLaunch,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				t.Setenv("DOCKER_HOST", testCase.dockerHost)
+
+				fakeRunner := newFakeCommandRunner()
+				fakeRunner.outputs[infoKey] = []byte(testCase.infoOutput)
+				fakeRunner.results[infoKey] = testCase.infoResult
+				fakeRunner.results[stopKey] = testCase.stopResult
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				out := &bytes.Buffer{}
+
+				err := claberneteslauncher.RestartDockerTo(context.Background(), out)
+				if !errors.Is(err, testCase.expectedErr) {
+					t.Fatalf("expected error %v, got %v", testCase.expectedErr, err)
+				}
+
+				if fakeRunner.calls[stopKey] != testCase.expectedStopCalls {
+					t.Fatalf(
+						"expected %d stop calls, got %d",
+						testCase.expectedStopCalls,
+						fakeRunner.calls[stopKey],
+					)
+				}
+
+				if !strings.Contains(out.String(), testCase.expectedOutput) {
+					t.Fatalf(
+						"expected output to contain %q, got %q",
+						testCase.expectedOutput,
+						out.String(),
+					)
+				}
+
+				if testCase.expectedErr == nil && fakeRunner.calls["docker ps"] == 0 {
+					t.Fatal("expected docker start flow to run")
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,117 @@
+package launcher
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+	claberneteslogging "github.com/srl-labs/clabernetes/logging"
+	clabernetesutil "github.com/srl-labs/clabernetes/util"
+)
+
+const (
+	defaultSelfTestImage  = "hello-world"
+	selfTestContainerName = "clabernetes-selftest"
+	selfTestTimeout       = 2 * time.Minute
+)
+
+// runSelfTest confirms the docker daemon can actually pull (if not present), run, and remove a
+// container of the given (tiny) image, catching storage driver or runtime problems before any
+// nodes are launched rather than just that the daemon is up.
+func runSelfTest(ctx context.Context, logger claberneteslogging.Instance, image string) error {
+	ctx, cancel := context.WithTimeout(ctx, selfTestTimeout)
+	defer cancel()
+
+	err := ensureImage(ctx, logger, image, "", imagePullPolicyIfNotPresent)
+	if err != nil {
+		return fmt.Errorf(
+			"%w: self-test failed pulling image %q, err: %w",
+			claberneteserrors.ErrLaunch,
+			image,
+			err,
+		)
+	}
+
+	runCmd := helperContainerCmd(ctx, selfTestContainerName, image)
+
+	output := &bytes.Buffer{}
+
+	runCmd.Stdout = output
+	runCmd.Stderr = output
+
+	err = runner.Run(runCmd)
+	if err != nil {
+		return fmt.Errorf(
+			"%w: self-test failed running image %q, err: %w, output: %s",
+			claberneteserrors.ErrLaunch,
+			image,
+			classifyDockerError(err),
+			strings.TrimSpace(output.String()),
+		)
+	}
+
+	psCmd := exec.CommandContext(
+		ctx,
+		"docker",
+		"ps",
+		"--all",
+		"--quiet",
+		"--filter",
+		fmt.Sprintf("name=^%s$", selfTestContainerName),
+	)
+
+	leftover, err := runner.Output(psCmd)
+	if err != nil {
+		return fmt.Errorf(
+			"%w: self-test failed listing containers, err: %w",
+			claberneteserrors.ErrLaunch,
+			classifyDockerError(err),
+		)
+	}
+
+	if len(bytes.TrimSpace(leftover)) > 0 {
+		return fmt.Errorf(
+			"%w: self-test container %q was not removed",
+			claberneteserrors.ErrLaunch,
+			selfTestContainerName,
+		)
+	}
+
+	return nil
+}
+
+// selfTest runs the docker self-test (see runSelfTest) if LauncherSelfTest is set, failing startup
+// if it does not pass.
+func (c *clabernetes) selfTest() {
+	if !strings.EqualFold(
+		os.Getenv(clabernetesconstants.LauncherSelfTest),
+		clabernetesconstants.True,
+	) {
+		return
+	}
+
+	image := clabernetesutil.GetEnvStrOrDefault(
+		clabernetesconstants.LauncherSelfTestImage,
+		defaultSelfTestImage,
+	)
+
+	// a self-test container left over from a previous run would keep this one from being created
+	c.cleanupHelperContainers()
+
+	c.logger.Infof("running docker self-test with image %q...", image)
+
+	err := c.startupTimings.runE("setup/self-test", func() error {
+		return runSelfTest(c.ctx, c.logger, image)
+	})
+	if err != nil {
+		c.logger.Fatalf("docker self-test failed, err: %s", err)
+	}
+
+	c.logger.Info("docker self-test passed")
+}
@@ -0,0 +1,107 @@
+package launcher_test
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+)
+
+func TestRunSelfTest(t *testing.T) {
+	const (
+		inspectKey = "docker image inspect hello-world"
+		pullKey    = "docker image pull hello-world"
+		runKey     = "docker run --rm --label clabernetes/launcherHelper=true --name clabernetes-selftest hello-world"
+		psKey      = "docker ps --all --quiet --filter name=^clabernetes-selftest$"
+	)
+
+	cases := []struct {
+		name          string
+		imagePresent  bool
+		pullFails     bool
+		runFails      bool
+		leftover      bool
+		expectedPulls int
+		expectedErr   string
+	}{
+		{
+			name:          "pull-run-remove",
+			expectedPulls: 1,
+		},
+		{
+			name:         "image-present",
+			imagePresent: true,
+		},
+		{
+			name:          "pull-fails",
+			pullFails:     true,
+			expectedPulls: 1,
+			expectedErr:   `self-test failed pulling image "hello-world"`,
+		},
+		{
+			name:         "run-fails",
+			imagePresent: true,
+			runFails:     true,
+			expectedErr:  "error creating overlay mount",
+		},
+		{
+			name:         "not-removed",
+			imagePresent: true,
+			leftover:     true,
+			expectedErr:  `self-test container "clabernetes-selftest" was not removed`,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				fakeRunner := newFakeCommandRunner()
+
+				if !testCase.imagePresent {
+					fakeRunner.results[inspectKey] = &exec.ExitError{
+						Stderr: []byte("Error: No such image: hello-world"),
+					}
+				}
+
+				if testCase.pullFails {
+					fakeRunner.results[pullKey] = errFakeCommand
+				}
+
+				if testCase.runFails {
+					fakeRunner.results[runKey] = errFakeCommand
+					fakeRunner.stderrs[runKey] = []byte("error creating overlay mount\n")
+				}
+
+				if testCase.leftover {
+					fakeRunner.outputs[psKey] = []byte("abc123\n")
+				}
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				err := claberneteslauncher.RunSelfTest(context.Background(), "hello-world")
+
+				if testCase.expectedErr == "" && err != nil {
+					t.Fatalf("expected no error, got %s", err)
+				}
+
+				if testCase.expectedErr != "" &&
+					(err == nil || !strings.Contains(err.Error(), testCase.expectedErr)) {
+					t.Fatalf("expected error containing %q, got %v", testCase.expectedErr, err)
+				}
+
+				if fakeRunner.calls[pullKey] != testCase.expectedPulls {
+					t.Fatalf(
+						"expected %d pull(s), got %d",
+						testCase.expectedPulls,
+						fakeRunner.calls[pullKey],
+					)
+				}
+			})
+	}
+}
@@ -0,0 +1,107 @@
+package launcher
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+)
+
+const defaultSidecarRole = "sidecar"
+
+// containerStream is a container whose logs are collected, along with the node it belongs to and
+// the name its log stream is labeled with.
+type containerStream struct {
+	ID string
+	// FullID is the full id of the container, empty if the container could not be inspected.
+	FullID string
+	// Node is the containerlab node the container is, or, for sidecars, belongs to.
+	Node string
+	// Role is the role of a sidecar container, empty for a node's own container.
+	Role string
+	// Name labels the container's log stream -- the container name for a node's own container,
+	// "<node>/<role>" for a sidecar.
+	Name string
+}
+
+// fileName returns the name of the log file for this stream.
+func (s containerStream) fileName() string {
+	return strings.ReplaceAll(s.Name, "/", ".") + ".log"
+}
+
+// containerStreams returns the log streams of the given containers, grouped per node -- each node's
+// own container followed by its sidecars (containers with a LabelNodeParent label) ordered by role.
+// If the containers can't be inspected every container is its own stream named by its container
+// name.
+func containerStreams(ctx context.Context, containerIDs []string) []containerStream {
+	streams := make([]containerStream, len(containerIDs))
+
+	inspected, err := inspectContainers(ctx, containerIDs)
+	if err != nil {
+		for idx, containerID := range containerIDs {
+			streams[idx] = containerStream{
+				ID:   containerID,
+				Name: getContainerLogName(ctx, containerID),
+			}
+		}
+
+		return streams
+	}
+
+	for idx, containerID := range containerIDs {
+		stream := containerStream{ID: containerID, Name: containerID}
+
+		result, ok := inspected[containerID]
+		if ok {
+			stream = containerStreamFromInspect(containerID, result)
+		}
+
+		streams[idx] = stream
+	}
+
+	sort.SliceStable(streams, func(i, j int) bool {
+		if streams[i].Node != streams[j].Node {
+			return streams[i].Node < streams[j].Node
+		}
+
+		return streams[i].Role < streams[j].Role
+	})
+
+	return streams
+}
+
+func containerStreamFromInspect(containerID string, result *containerInspect) containerStream {
+	containerName := strings.TrimPrefix(result.Name, "/")
+	if containerName == "" {
+		containerName = containerID
+	}
+
+	parent := result.Config.Labels[clabernetesconstants.LabelNodeParent]
+	if parent == "" {
+		nodeName := result.Config.Labels[containerlabNodeNameLabel]
+		if nodeName == "" {
+			nodeName = containerName
+		}
+
+		return containerStream{
+			ID:     containerID,
+			FullID: result.ID,
+			Node:   nodeName,
+			Name:   containerName,
+		}
+	}
+
+	role := result.Config.Labels[clabernetesconstants.LabelNodeRole]
+	if role == "" {
+		role = defaultSidecarRole
+	}
+
+	return containerStream{
+		ID:     containerID,
+		FullID: result.ID,
+		Node:   parent,
+		Role:   role,
+		Name:   parent + "/" + role,
+	}
+}
@@ -0,0 +1,72 @@
+package launcher_test
+
+import (
+	"context"
+	"testing"
+
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+func TestContainerStreams(t *testing.T) {
+	cases := []struct {
+		name          string
+		inspectOutput string
+		expected      [][2]string
+	}{
+		{
+			name: "sidecars-grouped-with-node",
+			inspectOutput: `[
+				{"Id": "side2", "Name": "/helper-2",
+					"Config": {"Labels": {"clabernetes/nodeParent": "srl2"}}},
+				{"Id": "srl2id", "Name": "/clab-topo-srl2",
+					"Config": {"Labels": {"clab-node-name": "srl2"}}},
+				{"Id": "side1", "Name": "/helper-1",
+					"Config": {"Labels": {"clabernetes/nodeParent": "srl1",
+						"clabernetes/nodeRole": "telemetry"}}},
+				{"Id": "srl1id", "Name": "/clab-topo-srl1",
+					"Config": {"Labels": {"clab-node-name": "srl1"}}}
+			]`,
+			expected: [][2]string{
+				{"srl1id", "clab-topo-srl1"},
+				{"side1", "srl1/telemetry"},
+				{"srl2id", "clab-topo-srl2"},
+				{"side2", "srl2/sidecar"},
+			},
+		},
+		{
+			name:          "inspect-failure",
+			inspectOutput: "",
+			expected: [][2]string{
+				{"side2", "side2"},
+				{"srl2id", "srl2id"},
+				{"side1", "side1"},
+				{"srl1id", "srl1id"},
+			},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				fakeRunner := newFakeCommandRunner()
+
+				fakeRunner.outputs["docker inspect side2 srl2id side1 srl1id"] = []byte(
+					testCase.inspectOutput,
+				)
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				actual := claberneteslauncher.ContainerStreamNames(
+					context.Background(),
+					[]string{"side2", "srl2id", "side1", "srl1id"},
+				)
+
+				clabernetestesthelper.MarshaledEqual(t, actual, testCase.expected)
+			})
+	}
+}
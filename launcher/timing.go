@@ -0,0 +1,143 @@
+package launcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+)
+
+const startupTimingsFileName = "startup-timings.json"
+
+// phaseTiming is the start time and (once finished) duration of a single launcher startup phase.
+type phaseTiming struct {
+	Name     string
+	Start    time.Time
+	Duration time.Duration
+	Finished bool
+}
+
+// startupTimings records how long each launcher startup phase took.
+type startupTimings struct {
+	lock   sync.Mutex
+	phases []*phaseTiming
+}
+
+func newStartupTimings() *startupTimings {
+	return &startupTimings{}
+}
+
+// run runs f recording its timing as the phase with the given name.
+func (t *startupTimings) run(name string, f func()) {
+	t.lock.Lock()
+
+	phase := &phaseTiming{
+		Name:  name,
+		Start: time.Now(),
+	}
+
+	t.phases = append(t.phases, phase)
+
+	t.lock.Unlock()
+
+	f()
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	phase.Duration = time.Since(phase.Start)
+	phase.Finished = true
+}
+
+// runE is run for phases that can fail, returning the error of f.
+func (t *startupTimings) runE(name string, f func() error) error {
+	var err error
+
+	t.run(name, func() {
+		err = f()
+	})
+
+	return err
+}
+
+// total returns the time since the first phase started.
+func (t *startupTimings) total() time.Duration {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if len(t.phases) == 0 {
+		return 0
+	}
+
+	return time.Since(t.phases[0].Start)
+}
+
+// MarshalJSON renders the timings as a list of phases with human readable durations.
+func (t *startupTimings) MarshalJSON() ([]byte, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	type phase struct {
+		Name     string    `json:"name"`
+		Start    time.Time `json:"start"`
+		Duration string    `json:"duration"`
+		Finished bool      `json:"finished"`
+	}
+
+	phases := make([]phase, len(t.phases))
+
+	for idx, p := range t.phases {
+		duration := p.Duration
+		if !p.Finished {
+			duration = time.Since(p.Start)
+		}
+
+		phases[idx] = phase{
+			Name:     p.Name,
+			Start:    p.Start,
+			Duration: duration.String(),
+			Finished: p.Finished,
+		}
+	}
+
+	return json.Marshal(phases)
+}
+
+// writeFile writes the timings as json to the given path.
+func (t *startupTimings) writeFile(path string) error {
+	content, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, content, clabernetesconstants.PermissionsEveryoneReadWrite)
+}
+
+// summary returns a phase by phase (one per line) summary of the recorded timings, phases that are
+// still running are reported with their duration so far.
+func (t *startupTimings) summary() string {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	lines := make([]string, len(t.phases))
+
+	for idx, phase := range t.phases {
+		if phase.Finished {
+			lines[idx] = fmt.Sprintf("%s: %s", phase.Name, phase.Duration.Round(time.Millisecond))
+
+			continue
+		}
+
+		lines[idx] = fmt.Sprintf(
+			"%s: %s (still running)",
+			phase.Name,
+			time.Since(phase.Start).Round(time.Millisecond),
+		)
+	}
+
+	return strings.Join(lines, "\n")
+}
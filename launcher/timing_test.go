@@ -0,0 +1,47 @@
+package launcher_test
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+)
+
+func TestStartupTimingsSummary(t *testing.T) {
+	actual := claberneteslauncher.StartupTimingsSummary([]string{"setup", "launch"}, "image")
+
+	expected := regexp.MustCompile(
+		`^setup: \d+(\.\d+)?[mµn]?s\nlaunch: \d+(\.\d+)?[mµn]?s\nimage: .+ \(still running\)$`,
+	)
+
+	if !expected.MatchString(actual) {
+		t.Fatalf("unexpected startup timings summary:\n%s", actual)
+	}
+}
+
+func TestStartupTimingsSubPhases(t *testing.T) {
+	errs, actual := claberneteslauncher.StartupTimingsSubPhases(
+		"setup",
+		[]string{"setup/daemon-config", "setup/docker-start"},
+		"setup/docker-start",
+	)
+
+	if errs[0] != nil {
+		t.Fatalf("expected no error for sub phase, got %v", errs[0])
+	}
+
+	if !errors.Is(errs[1], claberneteslauncher.ErrFailingSubPhase) {
+		t.Fatalf("expected failing sub phase error, got %v", errs[1])
+	}
+
+	expected := regexp.MustCompile(
+		`^setup: \d+(\.\d+)?[mµn]?s\n` +
+			`setup/daemon-config: \d+(\.\d+)?[mµn]?s\n` +
+			`setup/docker-start: \d+(\.\d+)?[mµn]?s$`,
+	)
+
+	if !expected.MatchString(actual) {
+		t.Fatalf("unexpected startup timings summary:\n%s", actual)
+	}
+}
@@ -0,0 +1,153 @@
+package launcher
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	topologyFileName        = "topo.clab.yaml"
+	patchedTopologyFileName = "topo.patched.clab.yaml"
+)
+
+// copyTopology copies the containerlab topology at srcPath to dstPath. The topology is mounted
+// read-only into the launcher pod, so the launcher patches (and deploys) a copy of it in its work
+// directory instead.
+func copyTopology(srcPath, dstPath string) error {
+	content, err := os.ReadFile(srcPath) //nolint:gosec
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(dstPath, content, clabernetesconstants.PermissionsEveryoneReadWrite)
+}
+
+// topologyPath returns the path of the launcher's (patchable) copy of the containerlab topology.
+func (c *clabernetes) topologyPath() string {
+	return c.workPath(patchedTopologyFileName)
+}
+
+// prepareTopology copies the containerlab topology to the launcher's work directory, all per node
+// settings are patched into, and containerlab deploys, that copy.
+func (c *clabernetes) prepareTopology() {
+	c.logger.Debugf("copying containerlab topology to %q...", c.topologyPath())
+
+	err := copyTopology(topologyFileName, c.topologyPath())
+	if err != nil {
+		c.logger.Fatalf("failed copying containerlab topology, err: %s", err)
+	}
+}
+
+// patchTopologyNodes loads the containerlab topology at path, calls patch for each node (with the
+// node's definition as a map that may be modified in place), and writes the topology back. This is
+// how the launcher applies per node container settings -- containerlab creates the containers, so
+// the settings have to go into the topology rather than onto a docker command line. Since the
+// topology is written back, path must be the launcher's copy of the topology (see topologyPath).
+func patchTopologyNodes(
+	path string,
+	patch func(nodeName string, node map[string]any) error,
+) error {
+	content, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return err
+	}
+
+	topology := map[string]any{}
+
+	err = yaml.Unmarshal(content, &topology)
+	if err != nil {
+		return err
+	}
+
+	topologySection, ok := topology["topology"].(map[string]any)
+	if !ok {
+		return fmt.Errorf(
+			"%w: containerlab topology has no topology section",
+			claberneteserrors.ErrLaunch,
+		)
+	}
+
+	nodes, ok := topologySection["nodes"].(map[string]any)
+	if !ok {
+		return fmt.Errorf("%w: containerlab topology has no nodes", claberneteserrors.ErrLaunch)
+	}
+
+	for nodeName, node := range nodes {
+		nodeDefinition, ok := node.(map[string]any)
+		if !ok || nodeDefinition == nil {
+			nodeDefinition = map[string]any{}
+		}
+
+		err = patch(nodeName, nodeDefinition)
+		if err != nil {
+			return err
+		}
+
+		nodes[nodeName] = nodeDefinition
+	}
+
+	content, err = yaml.Marshal(topology)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, content, clabernetesconstants.PermissionsEveryoneReadWrite)
+}
+
+// topologyNodeImages returns the (deduplicated, sorted) images of the nodes in the containerlab
+// topology at path -- each node's image is resolved the way containerlab does, the node's own image
+// first, then the image of its kind, then the topology default image. Since each launcher gets a
+// topology of only the nodes it is responsible for, these are exactly the images it needs.
+func topologyNodeImages(path string) ([]string, error) {
+	content, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+
+	topology := struct {
+		Topology struct {
+			Defaults struct {
+				Image string `yaml:"image"`
+			} `yaml:"defaults"`
+			Kinds map[string]struct {
+				Image string `yaml:"image"`
+			} `yaml:"kinds"`
+			Nodes map[string]struct {
+				Kind  string `yaml:"kind"`
+				Image string `yaml:"image"`
+			} `yaml:"nodes"`
+		} `yaml:"topology"`
+	}{}
+
+	err = yaml.Unmarshal(content, &topology)
+	if err != nil {
+		return nil, err
+	}
+
+	var images []string
+
+	for _, node := range topology.Topology.Nodes {
+		image := node.Image
+
+		if image == "" {
+			image = topology.Topology.Kinds[node.Kind].Image
+		}
+
+		if image == "" {
+			image = topology.Topology.Defaults.Image
+		}
+
+		if image != "" && !slices.Contains(images, image) {
+			images = append(images, image)
+		}
+	}
+
+	slices.Sort(images)
+
+	return images, nil
+}
@@ -0,0 +1,69 @@
+package launcher_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+func TestPrepareTopologyReadOnlySource(t *testing.T) {
+	sourceDir := t.TempDir()
+	workDir := t.TempDir()
+
+	source := clabernetestesthelper.ReadTestFixtureFile(t, "node-labels/topo.clab.yaml")
+
+	err := os.WriteFile(
+		filepath.Join(sourceDir, "topo.clab.yaml"),
+		source,
+		clabernetesconstants.PermissionsEveryoneRead,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the topology is mounted read-only into the launcher pod, mirror that for the source dir too
+	err = os.Chmod(sourceDir, clabernetesconstants.PermissionsEveryoneReadExecute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_ = os.Chmod(sourceDir, clabernetesconstants.PermissionsEveryoneAllPermissions)
+	})
+
+	t.Chdir(sourceDir)
+
+	topologyPath := claberneteslauncher.PrepareTopology(workDir)
+
+	if filepath.Dir(topologyPath) != workDir {
+		clabernetestesthelper.FailOutput(t, topologyPath, workDir)
+	}
+
+	err = claberneteslauncher.PatchNodeLabels(topologyPath, "clabernetes")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actualSource, err := os.ReadFile(filepath.Join(sourceDir, "topo.clab.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(actualSource) != string(source) {
+		clabernetestesthelper.FailOutput(t, actualSource, source)
+	}
+
+	patched, err := os.ReadFile(topologyPath) //nolint:gosec
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(patched), clabernetesconstants.LabelApp+": clabernetes") {
+		t.Fatalf("expected topology copy to be patched, got:\n%s", patched)
+	}
+}
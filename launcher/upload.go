@@ -0,0 +1,273 @@
+package launcher
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+	clabernetesutil "github.com/srl-labs/clabernetes/util"
+)
+
+const (
+	logUploadTimeout             = 5 * time.Minute
+	logUploadDefaultRegion       = "us-east-1"
+	logUploadAccessKeyIDFile     = "access-key-id"
+	logUploadSecretAccessKeyFile = "secret-access-key" //nolint:gosec
+	s3UnsignedPayload            = "UNSIGNED-PAYLOAD"
+	s3SigningAlgorithm           = "AWS4-HMAC-SHA256"
+	s3SignedHeaders              = "host;x-amz-content-sha256;x-amz-date"
+	s3AMZDateFormat              = "20060102T150405Z"
+	s3DateFormat                 = "20060102"
+)
+
+// s3Client is a (very) minimal s3 compatible client that can only put objects -- this exists so
+// that we don't need to pull in an entire sdk just to ship some log files somewhere.
+type s3Client struct {
+	endpoint        *url.URL
+	bucket          string
+	region          string
+	accessKeyID     string
+	secretAccessKey string
+	httpClient      *http.Client
+}
+
+func newS3ClientFromEnv() (*s3Client, error) {
+	endpoint, err := url.Parse(os.Getenv(clabernetesconstants.LauncherLogUploadEndpoint))
+	if err != nil {
+		return nil, err
+	}
+
+	bucket := os.Getenv(clabernetesconstants.LauncherLogUploadBucket)
+
+	if endpoint.Host == "" || bucket == "" {
+		return nil, fmt.Errorf(
+			"%w: log upload endpoint and bucket must both be set",
+			claberneteserrors.ErrLaunch,
+		)
+	}
+
+	credentialsPath := os.Getenv(clabernetesconstants.LauncherLogUploadCredentialsPath)
+
+	accessKeyID, err := os.ReadFile(filepath.Join(credentialsPath, logUploadAccessKeyIDFile))
+	if err != nil {
+		return nil, err
+	}
+
+	secretAccessKey, err := os.ReadFile(
+		filepath.Join(credentialsPath, logUploadSecretAccessKeyFile),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &s3Client{
+		endpoint: endpoint,
+		bucket:   bucket,
+		region: clabernetesutil.GetEnvStrOrDefault(
+			clabernetesconstants.LauncherLogUploadRegion,
+			logUploadDefaultRegion,
+		),
+		accessKeyID:     strings.TrimSpace(string(accessKeyID)),
+		secretAccessKey: strings.TrimSpace(string(secretAccessKey)),
+		httpClient:      &http.Client{},
+	}, nil
+}
+
+// s3URIEncode encodes a path segment per the aws sigv4 rules -- everything but the unreserved
+// characters is percent encoded.
+func s3URIEncode(s string) string {
+	var encoded strings.Builder
+
+	for _, b := range []byte(s) {
+		if (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z') || (b >= '0' && b <= '9') ||
+			b == '-' || b == '.' || b == '_' || b == '~' {
+			encoded.WriteByte(b)
+
+			continue
+		}
+
+		fmt.Fprintf(&encoded, "%%%02X", b)
+	}
+
+	return encoded.String()
+}
+
+func s3HMAC(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+
+	_, _ = h.Write([]byte(data))
+
+	return h.Sum(nil)
+}
+
+// putObject streams the file at filePath into the bucket at the given key. The payload is sent
+// "unsigned" so we never need to read the file into memory (or read it twice) to hash it. Only the
+// file's size at the time of opening it is sent, as (node log) files may still be growing.
+func (c *s3Client) putObject(ctx context.Context, key, filePath string) error {
+	f, err := os.Open(filePath) //nolint:gosec
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	fileInfo, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	encodedSegments := []string{s3URIEncode(c.bucket)}
+
+	for _, segment := range strings.Split(key, "/") {
+		encodedSegments = append(encodedSegments, s3URIEncode(segment))
+	}
+
+	canonicalURI := path.Join(c.endpoint.EscapedPath(), "/"+strings.Join(encodedSegments, "/"))
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPut,
+		fmt.Sprintf("%s://%s%s", c.endpoint.Scheme, c.endpoint.Host, canonicalURI),
+		io.LimitReader(f, fileInfo.Size()),
+	)
+	if err != nil {
+		return err
+	}
+
+	req.ContentLength = fileInfo.Size()
+
+	now := time.Now().UTC()
+	amzDate := now.Format(s3AMZDateFormat)
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", now.Format(s3DateFormat), c.region)
+
+	canonicalRequest := strings.Join(
+		[]string{
+			http.MethodPut,
+			canonicalURI,
+			"",
+			fmt.Sprintf(
+				"host:%s\nx-amz-content-sha256:%s\nx-amz-date:%s\n",
+				c.endpoint.Host,
+				s3UnsignedPayload,
+				amzDate,
+			),
+			s3SignedHeaders,
+			s3UnsignedPayload,
+		},
+		"\n",
+	)
+
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+
+	stringToSign := strings.Join(
+		[]string{
+			s3SigningAlgorithm,
+			amzDate,
+			scope,
+			hex.EncodeToString(canonicalRequestHash[:]),
+		},
+		"\n",
+	)
+
+	signingKey := s3HMAC([]byte("AWS4"+c.secretAccessKey), now.Format(s3DateFormat))
+	signingKey = s3HMAC(signingKey, c.region)
+	signingKey = s3HMAC(signingKey, "s3")
+	signingKey = s3HMAC(signingKey, "aws4_request")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", s3UnsignedPayload)
+	req.Header.Set(
+		"Authorization",
+		fmt.Sprintf(
+			"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+			s3SigningAlgorithm,
+			c.accessKeyID,
+			scope,
+			s3SignedHeaders,
+			hex.EncodeToString(s3HMAC(signingKey, stringToSign)),
+		),
+	)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close() //nolint
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf(
+			"%w: non 2xx status uploading %q to bucket %q, status code: %d",
+			claberneteserrors.ErrLaunch,
+			key,
+			c.bucket,
+			resp.StatusCode,
+		)
+	}
+
+	return nil
+}
+
+// uploadLogs uploads the node log file(s) to the configured s3 compatible bucket if log upload is
+// enabled -- this is meant to be called during shutdown so it uses its own context rather than the
+// (already cancelled) clabernetes context.
+func (c *clabernetes) uploadLogs() {
+	if !strings.EqualFold(
+		os.Getenv(clabernetesconstants.LauncherLogUploadEnabled),
+		clabernetesconstants.True,
+	) {
+		return
+	}
+
+	client, err := newS3ClientFromEnv()
+	if err != nil {
+		c.logger.Warnf("log upload enabled but not configured properly, skipping, err: %s", err)
+
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), logUploadTimeout)
+	defer cancel()
+
+	keyPrefix := path.Join(
+		os.Getenv(clabernetesconstants.LauncherLogUploadPrefix),
+		os.Getenv(clabernetesconstants.LauncherTopologyNameEnv),
+		c.nodeName,
+	)
+
+	logFiles := []string{c.workPath(nodeLogFileName)}
+
+	for _, containerLogFile := range c.containerLogFiles {
+		logFiles = append(logFiles, containerLogFile)
+	}
+
+	for _, logFile := range logFiles {
+		relativeLogFile, err := filepath.Rel(c.workDir, logFile)
+		if err != nil {
+			relativeLogFile = filepath.Base(logFile)
+		}
+
+		key := path.Join(keyPrefix, filepath.ToSlash(relativeLogFile))
+
+		c.logger.Infof("uploading log file %q to %q", logFile, key)
+
+		err = client.putObject(ctx, key, logFile)
+		if err != nil {
+			c.logger.Warnf("failed uploading log file %q, err: %s", logFile, err)
+		}
+	}
+}
@@ -0,0 +1,342 @@
+package launcher_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+const (
+	testS3AccessKeyID     = "AKIDEXAMPLE"
+	testS3SecretAccessKey = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY" //nolint:gosec
+	testS3Region          = "eu-west-1"
+	testS3Bucket          = "logs"
+)
+
+var s3AuthorizationPattern = regexp.MustCompile( //nolint:gochecknoglobals
+	`^AWS4-HMAC-SHA256 Credential=([^/]+)/([0-9]{8})/([^/]+)/s3/aws4_request,` +
+		` SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=([0-9a-f]{64})$`,
+)
+
+// s3Request is a request received by the fake s3 server.
+type s3Request struct {
+	method     string
+	requestURI string
+	host       string
+	header     http.Header
+	body       []byte
+}
+
+type fakeS3Server struct {
+	*httptest.Server
+
+	lock     sync.Mutex
+	requests []s3Request
+}
+
+func newFakeS3Server(t *testing.T, statusCode int) *fakeS3Server {
+	t.Helper()
+
+	s := &fakeS3Server{}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		s.lock.Lock()
+		s.requests = append(s.requests, s3Request{
+			method:     r.Method,
+			requestURI: r.RequestURI,
+			host:       r.Host,
+			header:     r.Header.Clone(),
+			body:       body,
+		})
+		s.lock.Unlock()
+
+		w.WriteHeader(statusCode)
+	}))
+
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// setS3Env points the log upload env at the given endpoint with a fake set of credentials.
+func setS3Env(t *testing.T, endpoint string) {
+	t.Helper()
+
+	credentialsPath := t.TempDir()
+
+	for fileName, content := range map[string]string{
+		"access-key-id":     testS3AccessKeyID + "\n",
+		"secret-access-key": testS3SecretAccessKey + "\n",
+	} {
+		err := os.WriteFile(filepath.Join(credentialsPath, fileName), []byte(content), 0o600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Setenv(clabernetesconstants.LauncherLogUploadEndpoint, endpoint)
+	t.Setenv(clabernetesconstants.LauncherLogUploadBucket, testS3Bucket)
+	t.Setenv(clabernetesconstants.LauncherLogUploadRegion, testS3Region)
+	t.Setenv(clabernetesconstants.LauncherLogUploadCredentialsPath, credentialsPath)
+}
+
+func testS3HMAC(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+
+	_, _ = h.Write([]byte(data))
+
+	return h.Sum(nil)
+}
+
+// assertS3Signature recomputes the sigv4 canonical request and signature of the given request and
+// fails if the request's signature does not match.
+func assertS3Signature(t *testing.T, req s3Request) {
+	t.Helper()
+
+	matches := s3AuthorizationPattern.FindStringSubmatch(req.header.Get("Authorization"))
+	if matches == nil {
+		t.Fatalf("unexpected authorization header %q", req.header.Get("Authorization"))
+	}
+
+	if matches[1] != testS3AccessKeyID || matches[3] != testS3Region {
+		t.Fatalf("unexpected credential scope in %q", req.header.Get("Authorization"))
+	}
+
+	amzDate := req.header.Get("X-Amz-Date")
+	if !strings.HasPrefix(amzDate, matches[2]) {
+		t.Fatalf("credential date %q does not match x-amz-date %q", matches[2], amzDate)
+	}
+
+	if req.header.Get("X-Amz-Content-Sha256") != "UNSIGNED-PAYLOAD" {
+		t.Fatalf("expected unsigned payload, got %q", req.header.Get("X-Amz-Content-Sha256"))
+	}
+
+	canonicalRequest := "PUT\n" +
+		req.requestURI + "\n" +
+		"\n" +
+		"host:" + req.host + "\n" +
+		"x-amz-content-sha256:UNSIGNED-PAYLOAD\n" +
+		"x-amz-date:" + amzDate + "\n" +
+		"\n" +
+		"host;x-amz-content-sha256;x-amz-date\n" +
+		"UNSIGNED-PAYLOAD"
+
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+
+	stringToSign := fmt.Sprintf(
+		"AWS4-HMAC-SHA256\n%s\n%s/%s/s3/aws4_request\n%s",
+		amzDate,
+		matches[2],
+		testS3Region,
+		hex.EncodeToString(canonicalRequestHash[:]),
+	)
+
+	signingKey := testS3HMAC([]byte("AWS4"+testS3SecretAccessKey), matches[2])
+	signingKey = testS3HMAC(signingKey, testS3Region)
+	signingKey = testS3HMAC(signingKey, "s3")
+	signingKey = testS3HMAC(signingKey, "aws4_request")
+
+	expectedSignature := hex.EncodeToString(testS3HMAC(signingKey, stringToSign))
+
+	if matches[4] != expectedSignature {
+		clabernetestesthelper.FailOutput(t, matches[4], expectedSignature)
+	}
+}
+
+func TestS3URIEncode(t *testing.T) {
+	cases := []struct {
+		name     string
+		in       string
+		expected string
+	}{
+		{
+			name:     "unreserved",
+			in:       "AZaz09-._~",
+			expected: "AZaz09-._~",
+		},
+		{
+			name:     "space",
+			in:       "node log",
+			expected: "node%20log",
+		},
+		{
+			name:     "reserved",
+			in:       "a+b=c/d:e",
+			expected: "a%2Bb%3Dc%2Fd%3Ae",
+		},
+		{
+			name:     "multibyte",
+			in:       "ü",
+			expected: "%C3%BC",
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				actual := claberneteslauncher.S3URIEncode(testCase.in)
+				if actual != testCase.expected {
+					clabernetestesthelper.FailOutput(t, actual, testCase.expected)
+				}
+			})
+	}
+}
+
+func TestS3PutObject(t *testing.T) {
+	cases := []struct {
+		name       string
+		statusCode int
+		expectErr  bool
+	}{
+		{
+			name:       "ok",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "no-content",
+			statusCode: http.StatusNoContent,
+		},
+		{
+			name:       "forbidden",
+			statusCode: http.StatusForbidden,
+			expectErr:  true,
+		},
+		{
+			name:       "redirect",
+			statusCode: http.StatusNotModified,
+			expectErr:  true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				server := newFakeS3Server(t, testCase.statusCode)
+
+				setS3Env(t, server.URL)
+
+				content := []byte("some log line\nanother log line\n")
+
+				logFile := filepath.Join(t.TempDir(), "node.log")
+
+				err := os.WriteFile(logFile, content, 0o600)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				err = claberneteslauncher.S3PutObject(
+					context.Background(),
+					"topo/srl 1/node+1.log",
+					logFile,
+				)
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+
+				if len(server.requests) != 1 {
+					t.Fatalf("expected one request, got %d", len(server.requests))
+				}
+
+				req := server.requests[0]
+
+				if req.method != http.MethodPut {
+					clabernetestesthelper.FailOutput(t, req.method, http.MethodPut)
+				}
+
+				expectedURI := "/logs/topo/srl%201/node%2B1.log"
+				if req.requestURI != expectedURI {
+					clabernetestesthelper.FailOutput(t, req.requestURI, expectedURI)
+				}
+
+				if string(req.body) != string(content) {
+					clabernetestesthelper.FailOutput(t, req.body, content)
+				}
+
+				assertS3Signature(t, req)
+			})
+	}
+}
+
+func TestS3ClientFromEnvMissingBucket(t *testing.T) {
+	setS3Env(t, "http://127.0.0.1:9000")
+
+	t.Setenv(clabernetesconstants.LauncherLogUploadBucket, "")
+
+	err := claberneteslauncher.S3PutObject(context.Background(), "key", "/does/not/matter")
+	if err == nil {
+		t.Fatal("expected error for missing bucket, got nil")
+	}
+}
+
+func TestUploadLogs(t *testing.T) {
+	server := newFakeS3Server(t, http.StatusOK)
+
+	setS3Env(t, server.URL)
+
+	t.Setenv(clabernetesconstants.LauncherLogUploadEnabled, "true")
+	t.Setenv(clabernetesconstants.LauncherLogUploadPrefix, "clabernetes")
+	t.Setenv(clabernetesconstants.LauncherTopologyNameEnv, "topo")
+
+	workDir := t.TempDir()
+
+	err := os.MkdirAll(filepath.Join(workDir, "node-logs"), 0o755) //nolint:gosec
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	containerLogFile := filepath.Join(workDir, "node-logs", "clab-topo-srl1.log")
+
+	for _, logFile := range []string{filepath.Join(workDir, "node.log"), containerLogFile} {
+		err = os.WriteFile(logFile, []byte("log\n"), 0o600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	claberneteslauncher.UploadLogs(
+		workDir,
+		"srl1",
+		map[string]string{"abc123": containerLogFile},
+	)
+
+	actual := make([]string, 0, len(server.requests))
+
+	for _, req := range server.requests {
+		actual = append(actual, req.requestURI)
+	}
+
+	slices.Sort(actual)
+
+	expected := []string{
+		"/logs/clabernetes/topo/srl1/node-logs/clab-topo-srl1.log",
+		"/logs/clabernetes/topo/srl1/node.log",
+	}
+
+	if !slices.Equal(actual, expected) {
+		clabernetestesthelper.FailOutput(t, actual, expected)
+	}
+}
@@ -0,0 +1,418 @@
+package launcher
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteserrors "github.com/srl-labs/clabernetes/errors"
+	clabernetesutil "github.com/srl-labs/clabernetes/util"
+)
+
+const (
+	// containerWaitPollInterval is the default interval the wait helpers poll docker at, see
+	// LauncherWaitPollInterval.
+	containerWaitPollInterval = time.Second
+	// containerWaitPollJitterDivisor sets the max jitter added to each poll interval, as a fraction
+	// (one over this) of the interval.
+	containerWaitPollJitterDivisor = 5
+
+	containerStateRunning  = "running"
+	containerStateExited   = "exited"
+	containerStateDead     = "dead"
+	containerStateRemoving = "removing"
+	containerHealthHealthy = "healthy"
+
+	partialFailurePolicyFailFast   = "fail-fast"
+	partialFailurePolicyBestEffort = "best-effort"
+
+	// containerStateReportTimeout bounds fetching the last known state of a node container that did
+	// not become ready, the wait context has likely expired by then so this gets its own budget.
+	containerStateReportTimeout = 5 * time.Second
+
+	// logStreamWaitDelay is how long we wait for a cancelled log stream to wind down before its
+	// output pipes are forcibly closed.
+	logStreamWaitDelay = time.Second
+)
+
+// nodeReadyStatus is the outcome of waiting on a single node container in waitAllNodesReady.
+type nodeReadyStatus struct {
+	ContainerID string
+	State       string
+	Health      string
+	Ready       bool
+	Err         error
+}
+
+// waitPollInterval returns the configured wait helper poll interval, see LauncherWaitPollInterval.
+func waitPollInterval() time.Duration {
+	interval := clabernetesutil.GetEnvDurationOrDefault(
+		clabernetesconstants.LauncherWaitPollInterval,
+		containerWaitPollInterval,
+	)
+	if interval <= 0 {
+		return containerWaitPollInterval
+	}
+
+	return interval
+}
+
+// validateWaitPollInterval ensures the given wait poll interval is a positive go duration string.
+func validateWaitPollInterval(interval string) error {
+	parsedInterval, err := time.ParseDuration(interval)
+	if err != nil || parsedInterval <= 0 {
+		return fmt.Errorf(
+			"%w: invalid wait poll interval %q, must be a positive duration such as \"500ms\"",
+			claberneteserrors.ErrLaunch,
+			interval,
+		)
+	}
+
+	return nil
+}
+
+// jitteredPollInterval returns the given interval plus a random jitter of up to one
+// containerWaitPollJitterDivisor-th of it, so that many concurrent waits don't all hit docker at
+// the same moment.
+func jitteredPollInterval(interval time.Duration) time.Duration {
+	maxJitter := int64(interval / containerWaitPollJitterDivisor)
+	if maxJitter <= 0 {
+		return interval
+	}
+
+	return interval + time.Duration(rand.Int63n(maxJitter+1)) //nolint:gosec
+}
+
+// pollUntil calls f every (jittered) waitPollInterval until f returns true, an error, or the
+// context is done.
+func pollUntil(ctx context.Context, f func() (bool, error)) error {
+	interval := waitPollInterval()
+
+	for {
+		done, err := f()
+		if err != nil {
+			return err
+		}
+
+		if done {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(jitteredPollInterval(interval)):
+		}
+	}
+}
+
+func getContainerState(ctx context.Context, containerID string) (state, health string, err error) {
+	inspectCmd := exec.CommandContext(
+		ctx,
+		"docker",
+		"inspect",
+		"--format",
+		"{{.State.Status}} {{if .State.Health}}{{.State.Health.Status}}{{end}}",
+		containerID,
+	)
+
+	output, err := runner.Output(inspectCmd)
+	if err != nil {
+		return "", "", classifyDockerError(err)
+	}
+
+	state, health, _ = strings.Cut(strings.TrimSpace(string(output)), " ")
+
+	return state, health, nil
+}
+
+// waitForLogLine follows the logs of the given container until a line matches pattern, returning
+// nil once that happens. The log stream is torn down as soon as a line matches or the timeout
+// passes, and an error is returned if the timeout passes or the stream ends without a match.
+func waitForLogLine(
+	ctx context.Context,
+	containerID string,
+	pattern *regexp.Regexp,
+	timeout time.Duration,
+) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var matched atomic.Bool
+
+	matcher := newLineWriter(func(line []byte) error {
+		if !matched.Load() && pattern.Match(bytes.TrimRight(line, "\r\n")) {
+			matched.Store(true)
+
+			cancel()
+		}
+
+		return nil
+	})
+
+	logsCmd := exec.CommandContext(ctx, "docker", "logs", "-f", containerID)
+
+	logsCmd.Stdout = matcher
+	logsCmd.Stderr = matcher
+	logsCmd.WaitDelay = logStreamWaitDelay
+
+	err := runner.Run(logsCmd)
+
+	// the last line may not be newline terminated
+	_ = matcher.emit(matcher.partial)
+
+	switch {
+	case matched.Load():
+		return nil
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return fmt.Errorf(
+			"%w: timed out after %s waiting for container %q to log a line matching %q",
+			claberneteserrors.ErrLaunch,
+			timeout,
+			containerID,
+			pattern,
+		)
+	case ctx.Err() != nil:
+		return ctx.Err()
+	case err != nil:
+		return classifyDockerError(err)
+	default:
+		return fmt.Errorf(
+			"%w: logs of container %q ended without a line matching %q",
+			claberneteserrors.ErrLaunch,
+			containerID,
+			pattern,
+		)
+	}
+}
+
+// retryDockerErr decides whether a failed docker invocation made while waiting is worth retrying --
+// anything but the container not existing is, as a single docker hiccup (say a timed out inspect)
+// shouldn't end the whole wait. Retryable errors are recorded in lastErr and nil is returned,
+// otherwise err is returned as is.
+func retryDockerErr(err error, lastErr *error) error {
+	if errors.Is(err, claberneteserrors.ErrContainerNotFound) {
+		return err
+	}
+
+	*lastErr = err
+
+	return nil
+}
+
+// withLastErr adds the last retried docker error (if any) to the given error of a wait.
+func withLastErr(err, lastErr error) error {
+	if lastErr == nil {
+		return err
+	}
+
+	return fmt.Errorf("%w, last docker error: %w", err, lastErr)
+}
+
+// waitContainerByName waits until a container (running or not) for the given node name exists,
+// returning its id. Failed container lookups are retried until the context is done.
+func waitContainerByName(ctx context.Context, nodeName string) (string, error) {
+	var containerID string
+
+	var lastErr error
+
+	err := pollUntil(ctx, func() (bool, error) {
+		var err error
+
+		containerID, err = getContainerIDForNodeName(ctx, nodeName, true, 0)
+		if err != nil {
+			return false, retryDockerErr(err, &lastErr)
+		}
+
+		lastErr = nil
+
+		return containerID != "", nil
+	})
+	if err != nil {
+		return "", fmt.Errorf(
+			"%w: failed waiting for container for node %q, err: %w",
+			claberneteserrors.ErrLaunch,
+			nodeName,
+			withLastErr(err, lastErr),
+		)
+	}
+
+	return containerID, nil
+}
+
+// waitContainerHealthy waits until the given container is running and, if the container has a
+// healthcheck defined, until that healthcheck reports healthy. It fails early if the container has
+// already exited or no longer exists, failing to inspect the container otherwise is retried until
+// the context is done.
+func waitContainerHealthy(ctx context.Context, containerID string) error {
+	var lastErr error
+
+	err := pollUntil(ctx, func() (bool, error) {
+		state, health, err := getContainerState(ctx, containerID)
+		if err != nil {
+			return false, retryDockerErr(err, &lastErr)
+		}
+
+		lastErr = nil
+
+		switch state {
+		case containerStateRunning:
+		case containerStateExited, containerStateDead:
+			return false, fmt.Errorf(
+				"%w: container %q is in state %q",
+				claberneteserrors.ErrLaunch,
+				containerID,
+				state,
+			)
+		default:
+			return false, nil
+		}
+
+		if health == "" {
+			return true, nil
+		}
+
+		return health == containerHealthHealthy, nil
+	})
+	if err != nil {
+		return fmt.Errorf(
+			"%w: failed waiting for container %q, err: %w",
+			claberneteserrors.ErrLaunch,
+			containerID,
+			withLastErr(err, lastErr),
+		)
+	}
+
+	return nil
+}
+
+// validatePartialFailurePolicy ensures the given partial failure policy is one we know how to
+// handle.
+func validatePartialFailurePolicy(policy string) error {
+	switch policy {
+	case partialFailurePolicyFailFast, partialFailurePolicyBestEffort:
+		return nil
+	default:
+		return fmt.Errorf(
+			"%w: invalid partial failure policy %q, must be one of %q or %q",
+			claberneteserrors.ErrLaunch,
+			policy,
+			partialFailurePolicyFailFast,
+			partialFailurePolicyBestEffort,
+		)
+	}
+}
+
+// outcome returns a short description of how waiting on the node went, for logging.
+func (s *nodeReadyStatus) outcome() string {
+	switch {
+	case s.Ready:
+		return "ready"
+	case s.ContainerID == "":
+		return fmt.Sprintf("never started, err: %s", s.Err)
+	default:
+		return fmt.Sprintf(
+			"not ready (state %q, health %q), err: %s",
+			s.State,
+			s.Health,
+			s.Err,
+		)
+	}
+}
+
+// lastContainerState returns the state and health of the given container, just so we have the
+// last known state to report. This is best effort and uses its own short deadline (only carrying
+// over the values of ctx) since the wait that precedes it has usually used up ctx.
+func lastContainerState(ctx context.Context, containerID string) (state, health string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), containerStateReportTimeout)
+	defer cancel()
+
+	state, health, _ = getContainerState(ctx, containerID)
+
+	return state, health
+}
+
+// waitAllNodesReady blocks until all the given nodes have a running (and, if a healthcheck is
+// defined, healthy) container or the timeout passes. If failFast is set it instead gives up on all
+// nodes as soon as any node is known to have failed (its container exited) rather than waiting out
+// the timeout for the others. It returns the status of each node and an aggregated error of all
+// nodes that did not become ready.
+func waitAllNodesReady(
+	ctx context.Context,
+	nodeNames []string,
+	timeout time.Duration,
+	failFast bool,
+) (map[string]*nodeReadyStatus, error) {
+	ctx, cancelTimeout := context.WithTimeout(ctx, timeout)
+	defer cancelTimeout()
+
+	waitCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	statuses := make(map[string]*nodeReadyStatus, len(nodeNames))
+
+	var statusesLock sync.Mutex
+
+	wg := &sync.WaitGroup{}
+
+	for _, nodeName := range nodeNames {
+		wg.Add(1)
+
+		go func(nodeName string) {
+			defer wg.Done()
+
+			status := &nodeReadyStatus{}
+
+			status.ContainerID, status.Err = waitContainerByName(waitCtx, nodeName)
+			if status.Err == nil {
+				status.Err = waitContainerHealthy(waitCtx, status.ContainerID)
+				status.Ready = status.Err == nil
+
+				status.State, status.Health = lastContainerState(ctx, status.ContainerID)
+			}
+
+			if failFast && status.Err != nil && waitCtx.Err() == nil {
+				cancel(fmt.Errorf("node %q failed", nodeName))
+			}
+
+			statusesLock.Lock()
+			defer statusesLock.Unlock()
+
+			statuses[nodeName] = status
+		}(nodeName)
+	}
+
+	wg.Wait()
+
+	var errs []error
+
+	for _, nodeName := range nodeNames {
+		status := statuses[nodeName]
+
+		if status.Err == nil {
+			continue
+		}
+
+		if errors.Is(status.Err, context.Canceled) && ctx.Err() == nil {
+			status.Err = fmt.Errorf(
+				"%w: gave up waiting (%s), %w",
+				claberneteserrors.ErrLaunch,
+				partialFailurePolicyFailFast,
+				context.Cause(waitCtx),
+			)
+		}
+
+		errs = append(errs, fmt.Errorf("node %q not ready: %w", nodeName, status.Err))
+	}
+
+	return statuses, errors.Join(errs...)
+}
@@ -0,0 +1,454 @@
+package launcher_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	clabernetesconstants "github.com/srl-labs/clabernetes/constants"
+	claberneteslauncher "github.com/srl-labs/clabernetes/launcher"
+	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
+)
+
+func TestWaitForLogLine(t *testing.T) {
+	cases := []struct {
+		name      string
+		logs      string
+		logsErr   error
+		expectErr bool
+	}{
+		{
+			name: "match",
+			logs: "booting...\nloading config\nSystem is ready\nmore output\n",
+		},
+		{
+			name: "match-unterminated-last-line",
+			logs: "booting...\nSystem is ready",
+		},
+		{
+			name:      "no-match",
+			logs:      "booting...\nloading config\n",
+			expectErr: true,
+		},
+		{
+			name:      "docker-error",
+			logsErr:   errFakeCommand,
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				fakeRunner := newFakeCommandRunner()
+
+				fakeRunner.outputs["docker logs -f abc123"] = []byte(testCase.logs)
+				fakeRunner.results["docker logs -f abc123"] = testCase.logsErr
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				err := claberneteslauncher.WaitForLogLine(
+					context.Background(),
+					"abc123",
+					regexp.MustCompile(`^System is ready$`),
+					time.Minute,
+				)
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+			})
+	}
+}
+
+func TestWaitPollInterval(t *testing.T) {
+	cases := []struct {
+		name          string
+		env           string
+		expected      time.Duration
+		expectInvalid bool
+	}{
+		{
+			name:     "default",
+			expected: time.Second,
+		},
+		{
+			name:     "configured",
+			env:      "250ms",
+			expected: 250 * time.Millisecond,
+		},
+		{
+			name:          "invalid",
+			env:           "soon",
+			expected:      time.Second,
+			expectInvalid: true,
+		},
+		{
+			name:          "negative",
+			env:           "-1s",
+			expected:      time.Second,
+			expectInvalid: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				if testCase.env != "" {
+					t.Setenv(clabernetesconstants.LauncherWaitPollInterval, testCase.env)
+				}
+
+				actual := claberneteslauncher.WaitPollInterval()
+				if actual != testCase.expected {
+					clabernetestesthelper.FailOutput(t, actual, testCase.expected)
+				}
+
+				if testCase.env == "" {
+					return
+				}
+
+				err := claberneteslauncher.ValidateWaitPollInterval(testCase.env)
+				if (err != nil) != testCase.expectInvalid {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectInvalid)
+				}
+			})
+	}
+}
+
+func TestJitteredPollInterval(t *testing.T) {
+	interval := time.Second
+
+	for range 100 {
+		actual := claberneteslauncher.JitteredPollInterval(interval)
+		if actual < interval || actual > interval+interval/5 {
+			t.Fatalf("jittered interval %s out of bounds for interval %s", actual, interval)
+		}
+	}
+
+	if claberneteslauncher.JitteredPollInterval(time.Nanosecond) != time.Nanosecond {
+		t.Fatal("expected tiny intervals to not be jittered")
+	}
+}
+
+func TestWaitAllNodesReadyPartialFailure(t *testing.T) {
+	stateKey := "docker inspect --format " +
+		"{{.State.Status}} {{if .State.Health}}{{.State.Health.Status}}{{end}} abc123"
+
+	timeout := 500 * time.Millisecond
+
+	cases := []struct {
+		name                string
+		failFast            bool
+		expectedSRL1Outcome string
+		expectedSRL2Outcome string
+		expectTimeout       bool
+	}{
+		{
+			name:                "best-effort",
+			expectedSRL1Outcome: `not ready (state "exited", health "")`,
+			expectedSRL2Outcome: "never started",
+			expectTimeout:       true,
+		},
+		{
+			name:                "fail-fast",
+			failFast:            true,
+			expectedSRL1Outcome: `not ready (state "exited", health "")`,
+			expectedSRL2Outcome: `gave up waiting (fail-fast), node "srl1" failed`,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				t.Setenv(clabernetesconstants.LauncherWaitPollInterval, "10ms")
+
+				fakeRunner := newFakeCommandRunner()
+
+				fakeRunner.outputs["docker ps -a --quiet --filter label=clab-node-name=srl1"] = []byte("abc123\n")
+				fakeRunner.outputs[stateKey] = []byte("exited \n")
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				start := time.Now()
+
+				outcomes, err := claberneteslauncher.WaitAllNodesReady(
+					context.Background(),
+					[]string{"srl1", "srl2"},
+					timeout,
+					testCase.failFast,
+				)
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+
+				if timedOut := time.Since(start) >= timeout; timedOut != testCase.expectTimeout {
+					t.Fatalf(
+						"expected waiting out the timeout to be %t, took %s",
+						testCase.expectTimeout,
+						time.Since(start),
+					)
+				}
+
+				if !strings.Contains(outcomes["srl1"], testCase.expectedSRL1Outcome) {
+					clabernetestesthelper.FailOutput(
+						t,
+						outcomes["srl1"],
+						testCase.expectedSRL1Outcome,
+					)
+				}
+
+				if !strings.Contains(outcomes["srl2"], testCase.expectedSRL2Outcome) {
+					clabernetestesthelper.FailOutput(
+						t,
+						outcomes["srl2"],
+						testCase.expectedSRL2Outcome,
+					)
+				}
+			})
+	}
+}
+
+func TestWaitAllNodesReady(t *testing.T) {
+	stateKeyFmt := "docker inspect --format " +
+		"{{.State.Status}} {{if .State.Health}}{{.State.Health.Status}}{{end}} %s"
+
+	cases := []struct {
+		name             string
+		states           map[string]string
+		expectedOutcomes map[string]string
+		expectErr        bool
+	}{
+		{
+			name: "all-ready",
+			states: map[string]string{
+				"srl1": "running \n",
+				"srl2": "running healthy\n",
+			},
+			expectedOutcomes: map[string]string{
+				"srl1": "ready",
+				"srl2": "ready",
+			},
+		},
+		{
+			name: "unhealthy",
+			states: map[string]string{
+				"srl1": "running \n",
+				"srl2": "running starting\n",
+			},
+			expectedOutcomes: map[string]string{
+				"srl1": "ready",
+				"srl2": `not ready (state "running", health "starting")`,
+			},
+			expectErr: true,
+		},
+		{
+			name: "created",
+			states: map[string]string{
+				"srl1": "created \n",
+				"srl2": "running \n",
+			},
+			expectedOutcomes: map[string]string{
+				"srl1": `not ready (state "created", health "")`,
+				"srl2": "ready",
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				t.Setenv(clabernetesconstants.LauncherWaitPollInterval, "10ms")
+
+				fakeRunner := newFakeCommandRunner()
+
+				for nodeName, state := range testCase.states {
+					containerID := "id-" + nodeName
+
+					fakeRunner.outputs["docker ps -a --quiet --filter label=clab-node-name="+nodeName] = []byte(containerID + "\n")
+					fakeRunner.outputs[fmt.Sprintf(stateKeyFmt, containerID)] = []byte(state)
+				}
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				outcomes, err := claberneteslauncher.WaitAllNodesReady(
+					context.Background(),
+					[]string{"srl1", "srl2"},
+					200*time.Millisecond,
+					false,
+				)
+				if (err != nil) != testCase.expectErr {
+					clabernetestesthelper.FailOutput(t, err, testCase.expectErr)
+				}
+
+				for nodeName, expectedOutcome := range testCase.expectedOutcomes {
+					if !strings.HasPrefix(outcomes[nodeName], expectedOutcome) {
+						clabernetestesthelper.FailOutput(t, outcomes[nodeName], expectedOutcome)
+					}
+				}
+			})
+	}
+}
+
+// flakyCommandRunner is a fakeCommandRunner whose Output fails with the given error for the first
+// failures[key] calls of each command.
+type flakyCommandRunner struct {
+	*fakeCommandRunner
+
+	lock     sync.Mutex
+	failures map[string]int
+	err      error
+}
+
+func (r *flakyCommandRunner) Output(cmd *exec.Cmd) ([]byte, error) {
+	k := r.key(cmd)
+
+	r.lock.Lock()
+
+	fail := r.failures[k] > 0
+	if fail {
+		r.failures[k]--
+	}
+
+	r.lock.Unlock()
+
+	if fail {
+		r.fakeCommandRunner.lock.Lock()
+		defer r.fakeCommandRunner.lock.Unlock()
+
+		r.calls[k]++
+
+		return nil, r.err
+	}
+
+	return r.fakeCommandRunner.Output(cmd)
+}
+
+func TestWaitAllNodesReadyDockerErrors(t *testing.T) {
+	psKey := "docker ps -a --quiet --filter label=clab-node-name=srl1"
+	stateKey := "docker inspect --format " +
+		"{{.State.Status}} {{if .State.Health}}{{.State.Health.Status}}{{end}} abc123"
+
+	timeout := 500 * time.Millisecond
+
+	cases := []struct {
+		name            string
+		err             error
+		failures        map[string]int
+		expectedOutcome string
+	}{
+		{
+			name:            "transient",
+			err:             errFakeCommand,
+			failures:        map[string]int{psKey: 2, stateKey: 2},
+			expectedOutcome: "ready",
+		},
+		{
+			name: "not-found",
+			err: &exec.ExitError{
+				Stderr: []byte("Error: No such object: abc123"),
+			},
+			failures:        map[string]int{stateKey: 1},
+			expectedOutcome: "not ready",
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				t.Setenv(clabernetesconstants.LauncherWaitPollInterval, "10ms")
+
+				fakeRunner := &flakyCommandRunner{
+					fakeCommandRunner: newFakeCommandRunner(),
+					failures:          testCase.failures,
+					err:               testCase.err,
+				}
+
+				fakeRunner.outputs[psKey] = []byte("abc123\n")
+				fakeRunner.outputs[stateKey] = []byte("running \n")
+
+				restore := claberneteslauncher.SetCommandRunner(fakeRunner)
+				defer restore()
+
+				start := time.Now()
+
+				outcomes, _ := claberneteslauncher.WaitAllNodesReady(
+					context.Background(),
+					[]string{"srl1"},
+					timeout,
+					false,
+				)
+
+				if time.Since(start) >= timeout {
+					t.Fatalf("expected wait to end before the timeout, took %s", time.Since(start))
+				}
+
+				if !strings.HasPrefix(outcomes["srl1"], testCase.expectedOutcome) {
+					clabernetestesthelper.FailOutput(t, outcomes["srl1"], testCase.expectedOutcome)
+				}
+			})
+	}
+}
+
+func TestLastContainerStateExpiredContext(t *testing.T) {
+	binDir := t.TempDir()
+
+	err := os.WriteFile(
+		filepath.Join(binDir, "docker"),
+		[]byte("#!/bin/sh\necho 'running starting'\n"),
+		0o755, //nolint:gosec
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	// the wait preceding the state lookup has usually used up its context by the time the state
+	// is fetched, that should not keep the last known state from being reported
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	state, health := claberneteslauncher.LastContainerState(ctx, "abc123")
+	if state != "running" || health != "starting" {
+		clabernetestesthelper.FailOutput(t, state+" "+health, "running starting")
+	}
+}
+
+func TestValidatePartialFailurePolicy(t *testing.T) {
+	for _, policy := range []string{"fail-fast", "best-effort"} {
+		err := claberneteslauncher.ValidatePartialFailurePolicy(policy)
+		if err != nil {
+			t.Fatalf("expected policy %q to be valid, err: %s", policy, err)
+		}
+	}
+
+	err := claberneteslauncher.ValidatePartialFailurePolicy("yolo")
+	if err == nil {
+		t.Fatal("expected invalid policy to fail validation")
+	}
+}
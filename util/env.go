@@ -3,6 +3,7 @@ package util
 import (
 	"os"
 	"strconv"
+	"time"
 )
 
 // GetEnvStrOrDefault returns the value of the environment variable k as a string *or* the default d
@@ -48,6 +49,23 @@ func GetEnvFloat64OrDefault(k string, d float64) float64 {
 	return d
 }
 
+// GetEnvDurationOrDefault returns the value of the environment variable k as a time.Duration (as
+// parsed by time.ParseDuration) *or* the default d if parsing fails or the environment variable is
+// not set.
+func GetEnvDurationOrDefault(k string, d time.Duration) time.Duration {
+	v, ok := os.LookupEnv(k)
+	if ok {
+		ev, err := time.ParseDuration(v)
+		if err != nil {
+			return d
+		}
+
+		return ev
+	}
+
+	return d
+}
+
 // GetEnvBoolOrDefault returns true if the environment variable is set, or the default d if it is
 // unset.
 func GetEnvBoolOrDefault(k string, d bool) bool {
@@ -2,6 +2,7 @@ package util_test
 
 import (
 	"testing"
+	"time"
 
 	clabernetestesthelper "github.com/srl-labs/clabernetes/testhelper"
 	clabernetesutil "github.com/srl-labs/clabernetes/util"
@@ -130,6 +131,54 @@ func TestGetEnvFloat64OrDefault(t *testing.T) {
 	}
 }
 
+func TestGetEnvDurationOrDefault(t *testing.T) {
+	cases := []struct {
+		name     string
+		k        string
+		setV     string
+		defaultV time.Duration
+		expected time.Duration
+	}{
+		{
+			name:     "simple-default",
+			k:        "SOME_ENV_VAR",
+			setV:     "",
+			defaultV: time.Second,
+			expected: time.Second,
+		},
+		{
+			name:     "simple-already-set",
+			k:        "SOME_ENV_VAR",
+			setV:     "5m",
+			defaultV: time.Second,
+			expected: 5 * time.Minute,
+		},
+		{
+			name:     "invalid",
+			k:        "SOME_ENV_VAR",
+			setV:     "five minutes",
+			defaultV: time.Second,
+			expected: time.Second,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				t.Logf("%s: starting", testCase.name)
+
+				t.Setenv(testCase.k, testCase.setV)
+
+				actual := clabernetesutil.GetEnvDurationOrDefault(testCase.k, testCase.defaultV)
+
+				if actual != testCase.expected {
+					clabernetestesthelper.FailOutput(t, actual, testCase.expected)
+				}
+			})
+	}
+}
+
 func TestGetEnvBoolOrDefault(t *testing.T) {
 	cases := []struct {
 		name     string